@@ -151,6 +151,20 @@ typedef struct
   int error;
 } SnapshotResult;
 
+typedef struct
+{
+  void *result;
+  int error;
+} PrepareSnapshotResult;
+
+typedef struct
+{
+  uint64_t index;
+  uint64_t result;
+  const unsigned char *cmd;
+  size_t cmdLen;
+} Entry;
+
 typedef struct
 {
   uint64_t csoid;
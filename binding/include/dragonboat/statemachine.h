@@ -137,10 +137,96 @@ class StateMachine
   DISALLOW_COPY_MOVE_AND_ASSIGN(StateMachine);
 };
 
+// ConcurrentStateMachine is the base class of all C++ state machines that
+// allow the lookup() method to be concurrently invoked with batchedUpdate() and
+// allow saveSnapshot() to be invoked concurrently with batchedUpdate(). It is
+// up to the subclass of ConcurrentStateMachine to correctly and safely maintain
+// its internal data structures during such concurrent accesses.
+//
+// Similar to the StateMachine class, your implementation should be linked as a
+// .so dynamic library named as dragonboat-cpp-plugin-xxxxx.so together with the
+// following factory function defined in the global scope.
+//
+// extern "C" CPPConcurrentStateMachine *
+//   CreateDragonboatPluginConcurrentStateMachine(uint64_t, uint64_t)
+class ConcurrentStateMachine
+{
+ public:
+  // The clusterID and nodeID parameters are the cluster id and node id of
+  // the node. They are provided for logging/debugging purposes.
+  ConcurrentStateMachine(uint64_t clusterID, uint64_t nodeID) noexcept;
+  virtual ~ConcurrentStateMachine();
+  void BatchedUpdate(Entry *entries, size_t size) noexcept;
+  LookupResult Lookup(const Byte *data, size_t size) const noexcept;
+  uint64_t GetHash() const noexcept;
+  PrepareSnapshotResult PrepareSnapshot() const noexcept;
+  SnapshotResult SaveSnapshot(const void *context, SnapshotWriter *writer,
+    SnapshotFileCollection *collection, const DoneChan &done) const noexcept;
+  int RecoverFromSnapshot(SnapshotReader *reader,
+    const std::vector<SnapshotFile> &files, const DoneChan &done) noexcept;
+  void FreePrepareSnapshotResult(void *context) noexcept;
+  void FreeLookupResult(LookupResult r) noexcept;
+ protected:
+  // Cluster ID of the state machine. This is mainly used for logging/debugging
+  // purposes.
+  uint64_t cluster_id_;
+  // Node ID of the state machine. This is mainly used for logging/debugging
+  // purposes.
+  uint64_t node_id_;
+  // batchedUpdate() updates the state machine object using the specified
+  // entries. The cmd field of each Entry is the proposed data, the result field
+  // of each Entry should be set to the result of the update operation. Input
+  // entries and their cmd buffers are owned by the caller, batchedUpdate()
+  // should not keep any reference to them after the call.
+  virtual void batchedUpdate(Entry *entries, size_t size) noexcept = 0;
+  // lookup() queries the state of the ConcurrentStateMachine and returns the
+  // query result. lookup() can be invoked concurrently with batchedUpdate().
+  // See the lookup() method of the StateMachine class for more details.
+  virtual LookupResult lookup(const Byte *data, size_t size) const noexcept = 0;
+  // getHash() returns a uint64_t integer representing the state of the
+  // ConcurrentStateMachine instance, it is usually a hash result of the object
+  // state.
+  virtual uint64_t getHash() const noexcept = 0;
+  // prepareSnapshot() prepares the snapshot to be concurrently captured and
+  // saved. It is invoked before saveSnapshot() and it is guaranteed that no
+  // batchedUpdate() call is in progress. The result field of the returned
+  // PrepareSnapshotResult is the context, usually a point in time view of the
+  // state, which is later passed to saveSnapshot(). The error field is the
+  // error code or SNAPSHOT_OK when there is no error.
+  virtual PrepareSnapshotResult prepareSnapshot() const noexcept = 0;
+  // saveSnapshot() saves the point in time state identified by the context
+  // returned by prepareSnapshot(). saveSnapshot() can be invoked concurrently
+  // with batchedUpdate(). See the saveSnapshot() method of the StateMachine
+  // class for more details.
+  virtual SnapshotResult saveSnapshot(const void *context,
+    SnapshotWriter *writer, SnapshotFileCollection *collection,
+    const DoneChan &done) const noexcept = 0;
+  // recoverFromSnapshot() recovers the state of the ConcurrentStateMachine
+  // object from a previously saved snapshot. It is guaranteed that no other
+  // method is invoked when recoverFromSnapshot() is in progress. See the
+  // recoverFromSnapshot() method of the StateMachine class for more details.
+  virtual int recoverFromSnapshot(SnapshotReader *reader,
+    const std::vector<SnapshotFile> &files,
+    const DoneChan &done) noexcept = 0;
+  // freePrepareSnapshotResult() receives the context previously returned by
+  // prepareSnapshot() after the completion of the saveSnapshot() call.
+  virtual void freePrepareSnapshotResult(void *context) noexcept = 0;
+  // freeLookupResult() receives a LookupResult struct previously returned by
+  // lookup(). See the freeLookupResult() method of the StateMachine class for
+  // more details.
+  virtual void freeLookupResult(LookupResult r) noexcept = 0;
+ private:
+  DISALLOW_COPY_MOVE_AND_ASSIGN(ConcurrentStateMachine);
+};
+
 }  // namespace dragonboat
 
 typedef struct CPPStateMachine {
   dragonboat::StateMachine *sm;
 } CPPStateMachine;
 
+typedef struct CPPConcurrentStateMachine {
+  dragonboat::ConcurrentStateMachine *sm;
+} CPPConcurrentStateMachine;
+
 #endif  // BINDING_INCLUDE_DRAGONBOAT_STATEMACHINE_H_
@@ -21,6 +21,8 @@ package config
 import (
 	"crypto/tls"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/lni/dragonboat/internal/settings"
 	"github.com/lni/dragonboat/internal/utils/netutil"
@@ -43,6 +45,47 @@ type RaftRPCFactoryFunc func(NodeHostConfig,
 type LogDBFactoryFunc func(dirs []string,
 	lowLatencyDirs []string) (raftio.ILogDB, error)
 
+// GhostReplica describes a ghost replica, i.e. a replica that is no longer a
+// member of its Raft cluster but still has its data kept by its NodeHost.
+type GhostReplica struct {
+	// ClusterID is the cluster ID of the ghost replica.
+	ClusterID uint64
+	// NodeID is the node ID of the ghost replica.
+	NodeID uint64
+	// Address is the RaftAddress of the NodeHost managing the ghost replica.
+	Address string
+	// Local indicates whether the ghost replica is managed by the NodeHost
+	// reporting it. Local ghost replicas are detected when they apply the
+	// membership change removing themselves, including when such change is
+	// replayed after a restart. Remote ghost replicas are detected when they
+	// send messages to the reporting NodeHost, e.g. when they keep campaigning
+	// after being removed while they were down. A replica removed while it was
+	// down has no local record of its removal, it is only reported as a remote
+	// ghost replica by the NodeHost instances of the remaining members.
+	Local bool
+}
+
+// GhostReplicaHandlerFunc is the function invoked when a ghost replica is
+// detected.
+type GhostReplicaHandlerFunc func(GhostReplica)
+
+// LabelResolverFunc is the function used for looking up labels of the NodeHost
+// identified by the specified RaftAddress. The returned boolean value indicates
+// whether labels of the specified NodeHost are known.
+type LabelResolverFunc func(raftAddress string) (map[string]string, bool)
+
+// NodeHostIDResolverFunc is the function used for looking up the current
+// RaftAddress of the NodeHost identified by the specified NodeHost ID. The
+// returned boolean value indicates whether the NodeHost is known.
+type NodeHostIDResolverFunc func(nodeHostID string) (string, bool)
+
+// ClientRateLimitKeyFunc is the function used for getting the key of the token
+// bucket used for rate limiting the proposal made by the specified client on
+// the specified Raft cluster. Proposals with the same key share the same token
+// bucket. The returned key must only depend on the specified parameters.
+type ClientRateLimitKeyFunc func(clusterID uint64,
+	clientID uint64, cmd []byte) string
+
 // Config is used to configure Raft nodes.
 type Config struct {
 	// NodeID is a non-zero value used to identify a node within a Raft cluster.
@@ -59,6 +102,14 @@ type Config struct {
 	// Quiesce specifies whether to let the Raft cluster enter quiesce mode when
 	// there is no cluster activity.
 	Quiesce bool
+	// PreferredLeaderID is the NodeID of the voting member preferred to be the
+	// leader of the Raft cluster, e.g. the node in the same zone as the bulk of
+	// clients. Once every election timeout, the leader transfers the leadership
+	// to the preferred node when the preferred node has been responsive and its
+	// log is up to date. PreferredLeaderID should be set to the same value on
+	// all nodes of the Raft cluster. The default zero value means there is no
+	// preferred leader.
+	PreferredLeaderID uint64
 	// ElectionRTT is the minimum number of message RTT between elections. Message
 	// RTT is defined by NodeHostConfig.RTTMillisecond. The Raft paper suggests it
 	// to be a magnitude greater than HeartbeatRTT, which is the interval between
@@ -106,6 +157,24 @@ type Config struct {
 	// MaxInMemLogSize should be left as 0 or be set to be greater than
 	// 3 * MaxProposalPayloadSize, MaxProposalPayloadSize is 32Mbytes by default.
 	MaxInMemLogSize uint64
+	// PlacementConstraints is a list of constraints on how voting members of
+	// the Raft cluster can be distributed across NodeHost instances with
+	// different labels. Constraints are checked when starting a brand new Raft
+	// cluster with initial members and when adding new nodes to the Raft
+	// cluster. Labels of remote NodeHost instances are looked up using the
+	// LabelResolver function specified in NodeHostConfig.
+	PlacementConstraints []PlacementConstraint
+	// MaxProposalRate is the max number of proposals per second allowed to be
+	// made on the local node of the Raft cluster, proposals beyond the quota
+	// fail with the ErrQuotaExceeded error before they enter the Raft log. It
+	// allows Raft clusters sharing the same NodeHost to be fairly served. The
+	// default zero value means no limit.
+	MaxProposalRate uint64
+	// MaxProposalBytesRate is the max total bytes of proposed commands per
+	// second allowed to be made on the local node of the Raft cluster,
+	// proposals beyond the quota fail with the ErrQuotaExceeded error. The
+	// default zero value means no limit.
+	MaxProposalBytesRate uint64
 }
 
 // Validate validates the Config instance and return an error when any member
@@ -129,6 +198,65 @@ func (c *Config) Validate() error {
 	if c.MaxInMemLogSize > 0 && c.MaxInMemLogSize < settings.Soft.ExpectedMaxInMemLogSize {
 		return errors.New("MaxInMemLogSize is too small")
 	}
+	for _, pc := range c.PlacementConstraints {
+		if len(pc.Label) == 0 {
+			return errors.New("empty label in placement constraint")
+		}
+		if pc.MaxReplicas < 0 {
+			return errors.New("invalid MaxReplicas in placement constraint")
+		}
+	}
+	return nil
+}
+
+// PlacementConstraint defines the maximum number of voting members of a Raft
+// cluster that can be placed on NodeHost instances sharing the same value of
+// the specified label. As an example, with each NodeHost labelled with the
+// availability zone it runs in, the constraint {Label: "zone", MaxReplicas: 1}
+// requires each voting member to be placed in a different zone.
+type PlacementConstraint struct {
+	// Label is the key of the NodeHost label used for grouping NodeHost
+	// instances, e.g. "zone" or "rack".
+	Label string
+	// MaxReplicas is the maximum number of voting members allowed to be placed
+	// on NodeHost instances sharing the same label value. When MaxReplicas is
+	// 0, the limit is set to the largest value that still leaves the quorum
+	// available when all NodeHost instances sharing the same label value fail.
+	MaxReplicas int
+}
+
+func (pc *PlacementConstraint) maxReplicas(clusterSize int) int {
+	if pc.MaxReplicas > 0 {
+		return pc.MaxReplicas
+	}
+	if max := (clusterSize - 1) / 2; max > 0 {
+		return max
+	}
+	return 1
+}
+
+// CheckPlacement checks whether voting members placed on NodeHost instances
+// with the specified labels satisfy all placement constraints. clusterSize is
+// the total number of voting members of the Raft cluster, labels can cover
+// a subset of those voting members. An error is returned when any constraint
+// is violated or when any involved NodeHost is missing a constrained label.
+func CheckPlacement(constraints []PlacementConstraint,
+	clusterSize int, labels []map[string]string) error {
+	for _, pc := range constraints {
+		max := pc.maxReplicas(clusterSize)
+		counts := make(map[string]int)
+		for _, l := range labels {
+			v, ok := l[pc.Label]
+			if !ok {
+				return fmt.Errorf("label %s not set on NodeHost", pc.Label)
+			}
+			counts[v]++
+			if counts[v] > max {
+				return fmt.Errorf("more than %d voting members with %s=%s",
+					max, pc.Label, v)
+			}
+		}
+	}
 	return nil
 }
 
@@ -202,14 +330,103 @@ type NodeHostConfig struct {
 	// KeyFile is the path of the node key file. This field is ignored when
 	// MutualTLS is false.
 	KeyFile string
+	// TransportSecret is the optional secret shared by all NodeHost instances
+	// in the deployment. When it is set, Raft messages and snapshot chunks
+	// exchanged by the built-in TCP based Raft RPC module are authenticated
+	// using HMAC-SHA256, received messages that can not be authenticated are
+	// dropped. TransportSecret must be set to the same value on all NodeHost
+	// instances. Note that messages are not encrypted and there is no replay
+	// protection, authenticated messages captured on the network can be sent
+	// again by anyone able to connect to the NodeHost. Use MutualTLS when
+	// confidentiality or protection against replayed messages is required.
+	TransportSecret []byte
 	// LogDBFactory is the factory function used for creating the Log DB instance
 	// used by NodeHost. The default zero value causes the default built-in RocksDB
 	// based Log DB implementation to be used.
 	LogDBFactory LogDBFactoryFunc
+	// LogDBSyncIntervalMillisecond enables the opt-in relaxed durability mode of
+	// the default built-in Log DB when it is set to a non-zero value. In such
+	// mode, Raft states and entries are saved without waiting for them to be
+	// synced to disk, instead, they are synced in the background every
+	// LogDBSyncIntervalMillisecond milliseconds. This significantly improves
+	// throughput at the cost of a bounded loss window. The default zero value
+	// causes every write to be synced to disk before it is acknowledged.
+	//
+	// Saved Raft states and entries survive process crashes, but those saved
+	// within the last LogDBSyncIntervalMillisecond milliseconds can be lost when
+	// the operating system crashes or the machine loses power. A NodeHost
+	// restarted after such failure may have forgotten its votes and log entries
+	// it acknowledged, Raft's safety guarantees no longer hold when that
+	// happens to a majority of a Raft cluster at around the same time, e.g.
+	// committed entries can be lost. Only use this mode when such data loss
+	// can be tolerated, or when machines hosting the same Raft cluster are
+	// unlikely to fail at the same time. This field is ignored when
+	// LogDBFactory is set.
+	LogDBSyncIntervalMillisecond uint64
 	// RaftRPCFactory is the factory function used for creating the Raft RPC
 	// instance for exchanging Raft message between NodeHost instances. The default
 	// zero value causes the built-in TCP based RPC module to be used.
 	RaftRPCFactory RaftRPCFactoryFunc
+	// Labels is a set of key-value pairs describing the NodeHost, e.g. the
+	// availability zone or the rack it runs in. Labels are reported to the
+	// optional Master servers and are used for checking placement constraints
+	// specified in the PlacementConstraints field of Config.
+	Labels map[string]string
+	// LabelResolver is the optional function used for looking up labels of
+	// remote NodeHost instances. It is required when PlacementConstraints are
+	// specified for Raft clusters managed by the NodeHost.
+	LabelResolver LabelResolverFunc
+	// PluginDir is the directory scanned for Go plugins by the
+	// StartClusterUsingAppName method of NodeHost. The working directory is
+	// scanned when PluginDir is not set. C++ plugins are always loaded from the
+	// working directory.
+	PluginDir string
+	// GhostReplicaHandler is the optional function invoked when a ghost
+	// replica is detected. Each ghost replica is reported at most once by the
+	// same NodeHost instance. Messages sent by remote ghost replicas are always
+	// dropped.
+	GhostReplicaHandler GhostReplicaHandlerFunc
+	// RemoveGhostReplica indicates whether data of local ghost replicas should
+	// be automatically removed once they are stopped. When set to false, such
+	// data can be removed using the RemoveData method of NodeHost.
+	RemoveGhostReplica bool
+	// NodeHostID is the optional persistent identifier of the NodeHost. It is
+	// saved in NodeHostDir when the NodeHost is first started, a random one is
+	// generated when NodeHostID is not set. Once saved, NodeHostID can not be
+	// changed. The NodeHost ID is available from the ID method of NodeHost.
+	// When both NodeHostID and AddressByNodeHostID are set, NodeHostID rather
+	// than the hostname is used for naming the data directories, so the
+	// NodeHost can also change its hostname across restarts. NewNodeHost
+	// panics when data directories named after the hostname exist in such
+	// case, they need to be renamed after NodeHostID first. Data directories
+	// are named after the hostname when NodeHostID is not set, a stable
+	// hostname is thus required unless NodeHostID is explicitly specified.
+	NodeHostID string
+	// AddressByNodeHostID indicates whether NodeHost instances are identified
+	// by their NodeHost IDs rather than their RaftAddress values. When set to
+	// true, addresses specified when starting Raft clusters or requesting
+	// membership changes are NodeHost IDs, they are resolved to RaftAddress
+	// values using the NodeHostIDResolver function. This allows a NodeHost to
+	// change its RaftAddress across restarts, e.g. when running in containers.
+	AddressByNodeHostID bool
+	// NodeHostIDResolver is the function used for looking up the current
+	// RaftAddress of remote NodeHost instances. It is required when
+	// AddressByNodeHostID is set to true.
+	NodeHostIDResolver NodeHostIDResolverFunc
+	// ClientProposalRate is the max number of proposals per second allowed to
+	// be made by each client on each Raft cluster, proposals beyond the limit
+	// fail with the ErrRateLimited error. Clients are identified by the
+	// ClientID of their client sessions unless ClientRateLimitKey is set. Note
+	// that each NO-OP client session returned by GetNoOPSession has its own
+	// random ClientID. The default zero value disables rate limiting.
+	ClientProposalRate uint64
+	// ClientProposalBurst is the max number of proposals allowed to be made by
+	// each client in a burst. ClientProposalRate is used when it is not set.
+	ClientProposalBurst uint64
+	// ClientRateLimitKey is the optional function used for identifying clients
+	// for rate limiting purposes, e.g. by the tenant encoded in the proposed
+	// command.
+	ClientRateLimitKey ClientRateLimitKeyFunc
 }
 
 // Validate validates the NodeHostConfig instance and return an error when
@@ -244,7 +461,17 @@ func (c *NodeHostConfig) Validate() error {
 			return errors.New("key file not specified")
 		}
 	}
-
+	if c.AddressByNodeHostID && c.NodeHostIDResolver == nil {
+		return errors.New("NodeHostIDResolver not specified")
+	}
+	if strings.TrimSpace(c.NodeHostID) != c.NodeHostID ||
+		strings.ContainsAny(c.NodeHostID, "/\\:") {
+		return errors.New("invalid NodeHostID")
+	}
+	if c.ClientProposalRate == 0 &&
+		(c.ClientProposalBurst > 0 || c.ClientRateLimitKey != nil) {
+		return errors.New("ClientProposalRate not specified")
+	}
 	return nil
 }
 
@@ -105,3 +105,95 @@ func TestIsValidAddress(t *testing.T) {
 		checkInvalidAddress(t, v)
 	}
 }
+
+func TestPlacementConstraintValidation(t *testing.T) {
+	c := Config{
+		NodeID:       1,
+		HeartbeatRTT: 1,
+		ElectionRTT:  10,
+	}
+	c.PlacementConstraints = []PlacementConstraint{{Label: "zone"}}
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	c.PlacementConstraints = []PlacementConstraint{{MaxReplicas: 1}}
+	if err := c.Validate(); err == nil {
+		t.Errorf("empty label not reported")
+	}
+	c.PlacementConstraints = []PlacementConstraint{{Label: "zone", MaxReplicas: -1}}
+	if err := c.Validate(); err == nil {
+		t.Errorf("negative MaxReplicas not reported")
+	}
+}
+
+func TestCheckPlacement(t *testing.T) {
+	zone := func(z string) map[string]string {
+		return map[string]string{"zone": z}
+	}
+	tests := []struct {
+		constraints []PlacementConstraint
+		size        int
+		labels      []map[string]string
+		ok          bool
+	}{
+		{nil, 3, []map[string]string{nil, nil, nil}, true},
+		{[]PlacementConstraint{{Label: "zone"}}, 3,
+			[]map[string]string{zone("a"), zone("b"), zone("c")}, true},
+		{[]PlacementConstraint{{Label: "zone"}}, 3,
+			[]map[string]string{zone("a"), zone("a"), zone("c")}, false},
+		{[]PlacementConstraint{{Label: "zone"}}, 5,
+			[]map[string]string{zone("a"), zone("a"), zone("b"), zone("b"),
+				zone("c")}, true},
+		{[]PlacementConstraint{{Label: "zone"}}, 5,
+			[]map[string]string{zone("a"), zone("a"), zone("a")}, false},
+		{[]PlacementConstraint{{Label: "zone", MaxReplicas: 1}}, 5,
+			[]map[string]string{zone("a"), zone("a")}, false},
+		{[]PlacementConstraint{{Label: "zone", MaxReplicas: 2}}, 3,
+			[]map[string]string{zone("a"), zone("a"), zone("b")}, true},
+		{[]PlacementConstraint{{Label: "zone"}}, 1,
+			[]map[string]string{zone("a")}, true},
+		{[]PlacementConstraint{{Label: "rack"}}, 3,
+			[]map[string]string{zone("a"), zone("b"), zone("c")}, false},
+	}
+	for idx, tt := range tests {
+		err := CheckPlacement(tt.constraints, tt.size, tt.labels)
+		if (err == nil) != tt.ok {
+			t.Errorf("%d, got %v, want ok %t", idx, err, tt.ok)
+		}
+	}
+}
+
+func TestNodeHostIDValidation(t *testing.T) {
+	c := NodeHostConfig{RaftAddress: "localhost:9010"}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	c.AddressByNodeHostID = true
+	if err := c.Validate(); err == nil {
+		t.Errorf("missing NodeHostIDResolver not reported")
+	}
+	c.NodeHostIDResolver = func(string) (string, bool) { return "", false }
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	for _, id := range []string{" nh1", "nh/1", "nh:1"} {
+		c.NodeHostID = id
+		if err := c.Validate(); err == nil {
+			t.Errorf("invalid NodeHostID %s not reported", id)
+		}
+	}
+}
+
+func TestClientRateLimitValidation(t *testing.T) {
+	c := NodeHostConfig{
+		RaftAddress:         "localhost:9010",
+		ClientProposalBurst: 10,
+	}
+	if err := c.Validate(); err == nil {
+		t.Errorf("missing ClientProposalRate not reported")
+	}
+	c.ClientProposalRate = 5
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
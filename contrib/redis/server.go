@@ -0,0 +1,373 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package redis implements a Redis protocol adapter backed by a Raft cluster.
+
+The adapter maps a subset of the Redis protocol onto proposals and lookups
+against the key-value StateMachine provided in this package. SET, DEL and INCR
+commands are applied as proposals, GET commands are served by linearizable
+reads. PING and QUIT are also supported for compatibility with common Redis
+clients.
+
+To use the adapter, start a Raft cluster using NewStateMachine as the state
+machine factory function, then serve Redis clients using a Server instance
+bound to the same cluster, e.g.
+
+	nh.StartCluster(members, false, redis.NewStateMachine, rc)
+	s := redis.NewServer(nh, clusterID, 3*time.Second)
+	s.ListenAndServe("localhost:6379")
+
+Values incremented by INCR are limited to 63-bit signed integers.
+*/
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/internal/utils/syncutil"
+	"github.com/lni/dragonboat/logger"
+)
+
+var (
+	plog = logger.GetLogger("redis")
+)
+
+const (
+	maxArgs       = 1024
+	maxBulkLength = 64 * 1024 * 1024
+)
+
+var (
+	// ErrServerClosed is returned by Serve and ListenAndServe after the server
+	// is closed.
+	ErrServerClosed = errors.New("redis server closed")
+	errProtocol     = errors.New("protocol error")
+)
+
+// Server serves Redis clients using the specified Raft cluster.
+type Server struct {
+	nh        dragonboat.ISyncRequester
+	clusterID uint64
+	timeout   time.Duration
+	stopper   *syncutil.Stopper
+	mu        struct {
+		sync.Mutex
+		closed    bool
+		listeners map[net.Listener]struct{}
+		conns     map[net.Conn]struct{}
+	}
+}
+
+// NewServer returns a new Server instance. The timeout value is applied to
+// each proposal or read made on behalf of Redis clients.
+func NewServer(nh dragonboat.ISyncRequester,
+	clusterID uint64, timeout time.Duration) *Server {
+	s := &Server{
+		nh:        nh,
+		clusterID: clusterID,
+		timeout:   timeout,
+		stopper:   syncutil.NewStopper(),
+	}
+	s.mu.listeners = make(map[net.Listener]struct{})
+	s.mu.conns = make(map[net.Conn]struct{})
+	return s
+}
+
+// ListenAndServe listens on the specified TCP address and serves Redis
+// clients until the server is closed.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts and serves Redis client connections from the specified
+// listener until the server is closed.
+func (s *Server) Serve(l net.Listener) error {
+	if !s.addListener(l) {
+		l.Close()
+		return ErrServerClosed
+	}
+	defer s.removeListener(l)
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.closed() {
+				return ErrServerClosed
+			}
+			return err
+		}
+		if !s.addConn(conn) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.stopper.RunWorker(func() {
+			defer s.removeConn(conn)
+			s.serveConn(conn)
+		})
+	}
+}
+
+// Close closes all listeners and client connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.mu.closed = true
+	for l := range s.mu.listeners {
+		l.Close()
+	}
+	for c := range s.mu.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	s.stopper.Stop()
+	return nil
+}
+
+func (s *Server) closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.closed
+}
+
+func (s *Server) addListener(l net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.closed {
+		return false
+	}
+	s.mu.listeners[l] = struct{}{}
+	return true
+}
+
+func (s *Server) removeListener(l net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.mu.listeners, l)
+}
+
+func (s *Server) addConn(c net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.closed {
+		return false
+	}
+	s.mu.conns[c] = struct{}{}
+	return true
+}
+
+func (s *Server) removeConn(c net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Close()
+	delete(s.mu.conns, c)
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if err == errProtocol {
+				writeError(w, "ERR Protocol error")
+				w.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		name := strings.ToUpper(string(args[0]))
+		if name == "QUIT" {
+			writeSimpleString(w, "OK")
+			w.Flush()
+			return
+		}
+		s.handleCommand(w, name, args[1:])
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) handleCommand(w *bufio.Writer, name string, args [][]byte) {
+	switch name {
+	case "PING":
+		if len(args) == 0 {
+			writeSimpleString(w, "PONG")
+		} else if len(args) == 1 {
+			writeBulkString(w, args[0])
+		} else {
+			writeArgsError(w, name)
+		}
+	case "GET":
+		if len(args) != 1 {
+			writeArgsError(w, name)
+			return
+		}
+		s.get(w, args[0])
+	case "SET":
+		if len(args) != 2 {
+			writeArgsError(w, name)
+			return
+		}
+		c := command{op: opSet, keys: args[:1], value: args[1]}
+		if _, err := s.propose(c); err != nil {
+			writeError(w, "ERR "+err.Error())
+			return
+		}
+		writeSimpleString(w, "OK")
+	case "DEL":
+		if len(args) == 0 {
+			writeArgsError(w, name)
+			return
+		}
+		result, err := s.propose(command{op: opDel, keys: args})
+		if err != nil {
+			writeError(w, "ERR "+err.Error())
+			return
+		}
+		writeInteger(w, int64(result))
+	case "INCR":
+		if len(args) != 1 {
+			writeArgsError(w, name)
+			return
+		}
+		result, err := s.propose(command{op: opIncr, keys: args})
+		if err != nil {
+			writeError(w, "ERR "+err.Error())
+			return
+		}
+		v, ok := decodeIncrResult(result)
+		if !ok {
+			writeError(w, "ERR value is not an integer or out of range")
+			return
+		}
+		writeInteger(w, v)
+	default:
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", name))
+	}
+}
+
+func (s *Server) get(w *bufio.Writer, key []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	result, err := s.nh.SyncRead(ctx, s.clusterID, key)
+	if err != nil {
+		writeError(w, "ERR "+err.Error())
+		return
+	}
+	if len(result) == 0 || result[0] != lookupFound {
+		writeNull(w)
+		return
+	}
+	writeBulkString(w, result[1:])
+}
+
+func (s *Server) propose(c command) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	session := s.nh.GetNoOPSession(s.clusterID)
+	return s.nh.SyncPropose(ctx, session, c.encode())
+}
+
+// readCommand reads a command sent in the RESP array format or as an inline
+// command.
+func readCommand(r *bufio.Reader) ([][]byte, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return bytes.Fields(line), nil
+	}
+	count, err := strconv.Atoi(string(line[1:]))
+	if err != nil || count < 0 || count > maxArgs {
+		return nil, errProtocol
+	}
+	args := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, errProtocol
+		}
+		sz, err := strconv.Atoi(string(line[1:]))
+		if err != nil || sz < 0 || sz > maxBulkLength {
+			return nil, errProtocol
+		}
+		buf := make([]byte, sz+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		if buf[sz] != '\r' || buf[sz+1] != '\n' {
+			return nil, errProtocol
+		}
+		args = append(args, buf[:sz])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(line, "\r\n"), nil
+}
+
+func writeSimpleString(w *bufio.Writer, s string) {
+	w.WriteString("+" + s + "\r\n")
+}
+
+func writeError(w *bufio.Writer, s string) {
+	w.WriteString("-" + s + "\r\n")
+}
+
+func writeArgsError(w *bufio.Writer, name string) {
+	writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command",
+		strings.ToLower(name)))
+}
+
+func writeInteger(w *bufio.Writer, v int64) {
+	w.WriteString(":" + strconv.FormatInt(v, 10) + "\r\n")
+}
+
+func writeBulkString(w *bufio.Writer, data []byte) {
+	w.WriteString("$" + strconv.Itoa(len(data)) + "\r\n")
+	w.Write(data)
+	w.WriteString("\r\n")
+}
+
+func writeNull(w *bufio.Writer) {
+	w.WriteString("$-1\r\n")
+}
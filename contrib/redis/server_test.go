@@ -0,0 +1,145 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/internal/utils/random"
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+type testNodeHost struct {
+	mu sync.Mutex
+	sm sm.IStateMachine
+}
+
+func (nh *testNodeHost) SyncPropose(ctx context.Context,
+	session *client.Session, cmd []byte) (uint64, error) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	return nh.sm.Update(cmd), nil
+}
+
+func (nh *testNodeHost) SyncRead(ctx context.Context,
+	clusterID uint64, query []byte) ([]byte, error) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	return nh.sm.Lookup(query), nil
+}
+
+func (nh *testNodeHost) GetNoOPSession(clusterID uint64) *client.Session {
+	return client.NewNoOPSession(clusterID, random.LockGuardedRand)
+}
+
+func runTestServer(t *testing.T, tf func(t *testing.T, conn net.Conn)) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen %v", err)
+	}
+	s := NewServer(&testNodeHost{sm: NewStateMachine(1, 1)}, 1, time.Second)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.Serve(l)
+	}()
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect %v", err)
+	}
+	defer conn.Close()
+	tf(t, conn)
+	s.Close()
+	if err := <-errc; err != ErrServerClosed {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestServerHandlesCommands(t *testing.T) {
+	tf := func(t *testing.T, conn net.Conn) {
+		r := bufio.NewReader(conn)
+		tests := []struct {
+			req  string
+			resp string
+		}{
+			{"*1\r\n$4\r\nPING\r\n", "+PONG\r\n"},
+			{"*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n", "$-1\r\n"},
+			{"*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nva\r\nl\r\n", "+OK\r\n"},
+			{"*2\r\n$3\r\nget\r\n$3\r\nkey\r\n", "$5\r\nva\r\nl\r\n"},
+			{"INCR counter\r\n", ":1\r\n"},
+			{"INCR counter\r\n", ":2\r\n"},
+			{"INCR key\r\n", "-ERR value is not an integer or out of range\r\n"},
+			{"DEL key counter missing\r\n", ":2\r\n"},
+			{"GET key\r\n", "$-1\r\n"},
+			{"GET\r\n", "-ERR wrong number of arguments for 'get' command\r\n"},
+			{"FLUSHALL\r\n", "-ERR unknown command 'FLUSHALL'\r\n"},
+			{"QUIT\r\n", "+OK\r\n"},
+		}
+		for idx, tt := range tests {
+			if _, err := conn.Write([]byte(tt.req)); err != nil {
+				t.Fatalf("failed to write %v", err)
+			}
+			buf := make([]byte, len(tt.resp))
+			if _, err := io.ReadFull(r, buf); err != nil {
+				t.Fatalf("%d, failed to read %v", idx, err)
+			}
+			if string(buf) != tt.resp {
+				t.Errorf("%d, got %q, want %q", idx, buf, tt.resp)
+			}
+		}
+	}
+	runTestServer(t, tf)
+}
+
+func TestServerHandlesPipelinedCommands(t *testing.T) {
+	tf := func(t *testing.T, conn net.Conn) {
+		req := "SET k 1\r\nINCR k\r\nGET k\r\n"
+		if _, err := conn.Write([]byte(req)); err != nil {
+			t.Fatalf("failed to write %v", err)
+		}
+		want := "+OK\r\n:2\r\n$1\r\n2\r\n"
+		buf := make([]byte, len(want))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			t.Fatalf("failed to read %v", err)
+		}
+		if string(buf) != want {
+			t.Errorf("got %q, want %q", buf, want)
+		}
+	}
+	runTestServer(t, tf)
+}
+
+func TestServerRejectsNegativeArrayLength(t *testing.T) {
+	tf := func(t *testing.T, conn net.Conn) {
+		if _, err := conn.Write([]byte("*-1\r\n")); err != nil {
+			t.Fatalf("failed to write %v", err)
+		}
+		want := "-ERR Protocol error\r\n"
+		buf := make([]byte, len(want))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			t.Fatalf("failed to read %v", err)
+		}
+		if string(buf) != want {
+			t.Errorf("got %q, want %q", buf, want)
+		}
+	}
+	runTestServer(t, tf)
+}
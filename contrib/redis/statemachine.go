@@ -0,0 +1,236 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+const (
+	opSet byte = iota + 1
+	opDel
+	opIncr
+)
+
+const (
+	// INCR results are encoded with the lowest bit indicating whether the
+	// operation succeeded, incremented values are thus limited to 63 bits.
+	incrFailed  uint64 = 0
+	maxIncrVal  int64  = 1<<62 - 1
+	minIncrVal  int64  = -1 << 62
+	lookupFound byte   = 1
+	lookupEmpty byte   = 0
+)
+
+type command struct {
+	op    byte
+	keys  [][]byte
+	value []byte
+}
+
+func (c *command) encode() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(c.op)
+	writeBytes(&buf, c.value)
+	for _, key := range c.keys {
+		writeBytes(&buf, key)
+	}
+	return buf.Bytes()
+}
+
+func decodeCommand(data []byte) (command, bool) {
+	if len(data) == 0 {
+		return command{}, false
+	}
+	c := command{op: data[0]}
+	data = data[1:]
+	v, data, ok := readBytes(data)
+	if !ok {
+		return command{}, false
+	}
+	c.value = v
+	for len(data) > 0 {
+		var key []byte
+		key, data, ok = readBytes(data)
+		if !ok {
+			return command{}, false
+		}
+		c.keys = append(c.keys, key)
+	}
+	return c, true
+}
+
+func writeBytes(buf *bytes.Buffer, data []byte) {
+	sz := make([]byte, 4)
+	binary.BigEndian.PutUint32(sz, uint32(len(data)))
+	buf.Write(sz)
+	buf.Write(data)
+}
+
+func readBytes(data []byte) ([]byte, []byte, bool) {
+	if len(data) < 4 {
+		return nil, nil, false
+	}
+	sz := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(len(data)) < uint64(sz) {
+		return nil, nil, false
+	}
+	return data[:sz], data[sz:], true
+}
+
+func encodeIncrResult(v int64) uint64 {
+	return uint64(v)<<1 | 1
+}
+
+func decodeIncrResult(result uint64) (int64, bool) {
+	if result&1 == 0 {
+		return 0, false
+	}
+	return int64(result) >> 1, true
+}
+
+// StateMachine is the key-value state machine used by the Redis protocol
+// adapter. Both keys and values are binary safe byte strings.
+type StateMachine struct {
+	clusterID uint64
+	nodeID    uint64
+	kv        map[string][]byte
+}
+
+// NewStateMachine creates and returns a new StateMachine instance. It has the
+// signature of the factory function expected by the StartCluster method of
+// NodeHost.
+func NewStateMachine(clusterID uint64, nodeID uint64) sm.IStateMachine {
+	return &StateMachine{
+		clusterID: clusterID,
+		nodeID:    nodeID,
+		kv:        make(map[string][]byte),
+	}
+}
+
+// Update applies the SET, DEL or INCR command to the state machine.
+func (s *StateMachine) Update(data []byte) uint64 {
+	c, ok := decodeCommand(data)
+	if !ok {
+		plog.Panicf("%d:%d, invalid command", s.clusterID, s.nodeID)
+	}
+	switch c.op {
+	case opSet:
+		s.kv[string(c.keys[0])] = append([]byte(nil), c.value...)
+		return 1
+	case opDel:
+		count := uint64(0)
+		for _, key := range c.keys {
+			if _, ok := s.kv[string(key)]; ok {
+				delete(s.kv, string(key))
+				count++
+			}
+		}
+		return count
+	case opIncr:
+		key := string(c.keys[0])
+		v := int64(0)
+		if old, ok := s.kv[key]; ok {
+			var err error
+			if v, err = strconv.ParseInt(string(old), 10, 64); err != nil {
+				return incrFailed
+			}
+		}
+		if v >= maxIncrVal || v < minIncrVal {
+			return incrFailed
+		}
+		v++
+		s.kv[key] = []byte(strconv.FormatInt(v, 10))
+		return encodeIncrResult(v)
+	}
+	plog.Panicf("%d:%d, unknown op %d", s.clusterID, s.nodeID, c.op)
+	return 0
+}
+
+// Lookup returns the value of the specified key. The first byte of the
+// returned slice indicates whether the key exists.
+func (s *StateMachine) Lookup(query []byte) []byte {
+	v, ok := s.kv[string(query)]
+	if !ok {
+		return []byte{lookupEmpty}
+	}
+	return append([]byte{lookupFound}, v...)
+}
+
+// SaveSnapshot saves all key-value pairs to the snapshot writer.
+func (s *StateMachine) SaveSnapshot(w io.Writer,
+	fc sm.ISnapshotFileCollection, done <-chan struct{}) (uint64, error) {
+	data := s.marshal()
+	if _, err := w.Write(data); err != nil {
+		return 0, err
+	}
+	return uint64(len(data)), nil
+}
+
+// RecoverFromSnapshot recovers the key-value pairs from the snapshot reader.
+func (s *StateMachine) RecoverFromSnapshot(r io.Reader,
+	files []sm.SnapshotFile, done <-chan struct{}) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	kv := make(map[string][]byte)
+	for len(data) > 0 {
+		var key []byte
+		var value []byte
+		var ok bool
+		if key, data, ok = readBytes(data); !ok {
+			return io.ErrUnexpectedEOF
+		}
+		if value, data, ok = readBytes(data); !ok {
+			return io.ErrUnexpectedEOF
+		}
+		kv[string(key)] = value
+	}
+	s.kv = kv
+	return nil
+}
+
+// Close closes the state machine.
+func (s *StateMachine) Close() {}
+
+// GetHash returns the hash value of all key-value pairs.
+func (s *StateMachine) GetHash() uint64 {
+	h := md5.Sum(s.marshal())
+	return binary.LittleEndian.Uint64(h[:8])
+}
+
+func (s *StateMachine) marshal() []byte {
+	keys := make([]string, 0, len(s.kv))
+	for key := range s.kv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	for _, key := range keys {
+		writeBytes(&buf, []byte(key))
+		writeBytes(&buf, s.kv[key])
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,108 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCommandCanBeEncodedAndDecoded(t *testing.T) {
+	c := command{
+		op:    opDel,
+		keys:  [][]byte{[]byte("k1"), []byte(""), []byte("k3")},
+		value: []byte("v"),
+	}
+	dc, ok := decodeCommand(c.encode())
+	if !ok {
+		t.Fatalf("failed to decode")
+	}
+	if dc.op != c.op || !bytes.Equal(dc.value, c.value) ||
+		len(dc.keys) != len(c.keys) {
+		t.Fatalf("unexpected command %v", dc)
+	}
+	for idx := range c.keys {
+		if !bytes.Equal(dc.keys[idx], c.keys[idx]) {
+			t.Errorf("key %d changed", idx)
+		}
+	}
+	if _, ok := decodeCommand(c.encode()[:8]); ok {
+		t.Errorf("truncated command decoded")
+	}
+}
+
+func TestIncrResultCanBeEncodedAndDecoded(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, maxIncrVal, minIncrVal} {
+		dv, ok := decodeIncrResult(encodeIncrResult(v))
+		if !ok || dv != v {
+			t.Errorf("got %d, want %d", dv, v)
+		}
+	}
+	if _, ok := decodeIncrResult(incrFailed); ok {
+		t.Errorf("failed result decoded as success")
+	}
+}
+
+func TestStateMachineUpdateAndLookup(t *testing.T) {
+	s := NewStateMachine(1, 1)
+	set := command{op: opSet, keys: [][]byte{[]byte("k")}, value: []byte("v")}
+	if v := s.Update(set.encode()); v != 1 {
+		t.Errorf("unexpected result %d", v)
+	}
+	if v := s.Lookup([]byte("k")); !bytes.Equal(v, []byte{lookupFound, 'v'}) {
+		t.Errorf("unexpected value %v", v)
+	}
+	if v := s.Lookup([]byte("x")); !bytes.Equal(v, []byte{lookupEmpty}) {
+		t.Errorf("unexpected value %v", v)
+	}
+	incr := command{op: opIncr, keys: [][]byte{[]byte("c")}}
+	for i := int64(1); i <= 3; i++ {
+		v, ok := decodeIncrResult(s.Update(incr.encode()))
+		if !ok || v != i {
+			t.Errorf("got %d, want %d", v, i)
+		}
+	}
+	incr = command{op: opIncr, keys: [][]byte{[]byte("k")}}
+	if _, ok := decodeIncrResult(s.Update(incr.encode())); ok {
+		t.Errorf("non-integer value incremented")
+	}
+	del := command{op: opDel, keys: [][]byte{[]byte("k"), []byte("x")}}
+	if v := s.Update(del.encode()); v != 1 {
+		t.Errorf("unexpected result %d", v)
+	}
+}
+
+func TestStateMachineSnapshot(t *testing.T) {
+	s := NewStateMachine(1, 1)
+	for _, kv := range []string{"k1", "k2", "k3"} {
+		c := command{op: opSet, keys: [][]byte{[]byte(kv)}, value: []byte(kv)}
+		s.Update(c.encode())
+	}
+	var buf bytes.Buffer
+	sz, err := s.SaveSnapshot(&buf, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to save snapshot %v", err)
+	}
+	if sz != uint64(buf.Len()) {
+		t.Errorf("sz %d, want %d", sz, buf.Len())
+	}
+	s2 := NewStateMachine(1, 2)
+	if err := s2.RecoverFromSnapshot(&buf, nil, nil); err != nil {
+		t.Fatalf("failed to recover from snapshot %v", err)
+	}
+	if s.GetHash() != s2.GetHash() {
+		t.Errorf("hash changed")
+	}
+}
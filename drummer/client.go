@@ -48,8 +48,11 @@ func RemoveDrummerServer(ctx context.Context, client pb.DrummerClient,
 }
 
 // SubmitCreateDrummerChange submits Drummer change used for defining clusters.
+// Optional placement constraints can be specified to control how nodes of the
+// defined cluster are distributed across labelled nodehosts.
 func SubmitCreateDrummerChange(ctx context.Context, client pb.DrummerClient,
-	clusterID uint64, members []uint64, appName string) error {
+	clusterID uint64, members []uint64, appName string,
+	constraints ...pb.PlacementConstraint) error {
 	checkClusterIDValue(clusterID)
 	if len(appName) == 0 {
 		panic("empty app name")
@@ -57,11 +60,17 @@ func SubmitCreateDrummerChange(ctx context.Context, client pb.DrummerClient,
 	if len(members) == 0 {
 		panic("empty members")
 	}
+	for _, c := range constraints {
+		if len(c.Label) == 0 {
+			panic("empty label in placement constraint")
+		}
+	}
 	change := pb.Change{
-		Type:      pb.Change_CREATE,
-		ClusterId: clusterID,
-		Members:   members,
-		AppName:   appName,
+		Type:        pb.Change_CREATE,
+		ClusterId:   clusterID,
+		Members:     members,
+		AppName:     appName,
+		Constraints: constraints,
 	}
 	req, err := client.SubmitChange(ctx, &change)
 	if err != nil {
@@ -50,11 +50,6 @@ type drummerClient struct {
 		sync.Mutex
 		requests []pb.NodeHostRequest
 	}
-	mu struct {
-		sync.Mutex
-		// app name -> func(uint64, uint64) IStateMachine
-		smFactory map[string]pluginDetails
-	}
 	connections *Pool
 }
 
@@ -65,9 +60,6 @@ func NewDrummerClient(nh *dragonboat.NodeHost) dragonboat.IMasterClient {
 		connections: NewDrummerConnectionPool(),
 	}
 	dc.req.requests = make([]pb.NodeHostRequest, 0)
-	// currently it is hard coded to scan the working dir for plugins.
-	// might need to make this configurable.
-	dc.mu.smFactory = getPluginMap(".")
 	return dc
 }
 
@@ -125,6 +117,7 @@ func (dc *drummerClient) SendNodeHostInfo(ctx context.Context,
 		PlogInfoIncluded: nhi.LogInfoIncluded,
 		PlogInfo:         toDrummerPBLogInfo(nhi.LogInfo),
 		Region:           nhi.Region,
+		Labels:           nhi.Labels,
 	}
 	requestCollection, err := client.ReportAvailableNodeHost(ctx, info)
 	if err != nil {
@@ -371,21 +364,11 @@ func (dc *drummerClient) handleInstantiateRequest(req pb.NodeHostRequest) {
 	config.NodeID = nodeID
 	config.ClusterID = req.Change.ClusterId
 	config.OrderedConfigChange = true
-	pd, ok := dc.mu.smFactory[req.AppName]
-	if !ok {
+	err := dc.nh.StartClusterUsingAppName(peers, req.Join, req.AppName, config)
+	if err == dragonboat.ErrPluginNotFound {
 		// installation or configuration issue
 		panic("failed to start the node as the plugin is not ready")
 	}
-	var err error
-	if pd.isRegularStateMachine() {
-		err = dc.nh.StartCluster(peers,
-			req.Join, pd.createNativeStateMachine, config)
-	} else if pd.isConcurrentStateMachine() {
-		err = dc.nh.StartConcurrentCluster(peers,
-			req.Join, pd.createConcurrentStateMachine, config)
-	} else {
-		err = dc.nh.StartClusterUsingPlugin(peers, req.Join, pd.filepath, config)
-	}
 	if err != nil {
 		plog.Errorf("add cluster %s failed: %v",
 			logutil.DescribeNode(clusterID, nodeID), err)
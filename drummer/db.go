@@ -370,9 +370,10 @@ func (d *DB) tryCreateCluster(c pb.Change) uint64 {
 	members := make([]uint64, len(c.Members))
 	copy(members, c.Members)
 	d.Clusters[c.ClusterId] = &pb.Cluster{
-		Members:   members,
-		ClusterId: c.ClusterId,
-		AppName:   c.AppName,
+		Members:     members,
+		ClusterId:   c.ClusterId,
+		AppName:     c.AppName,
+		Constraints: copyPlacementConstraints(c.Constraints),
 	}
 	return DBUpdated
 }
@@ -489,9 +490,10 @@ func (d *DB) handleClusterLookup(req pb.LookupRequest) []byte {
 		m := make([]uint64, len(v.Members))
 		copy(m, v.Members)
 		cc := pb.Cluster{
-			ClusterId: v.ClusterId,
-			Members:   m,
-			AppName:   v.AppName,
+			ClusterId:   v.ClusterId,
+			Members:     m,
+			AppName:     v.AppName,
+			Constraints: copyPlacementConstraints(v.Constraints),
 		}
 		clusters = append(clusters, &cc)
 	}
@@ -503,6 +505,16 @@ func (d *DB) handleClusterLookup(req pb.LookupRequest) []byte {
 	return result
 }
 
+func copyPlacementConstraints(
+	pc []pb.PlacementConstraint) []pb.PlacementConstraint {
+	if len(pc) == 0 {
+		return nil
+	}
+	result := make([]pb.PlacementConstraint, len(pc))
+	copy(result, pc)
+	return result
+}
+
 func (d *DB) assertNotFailed() {
 	if d.Failed {
 		panic("Drummer based system failed to launch")
@@ -64,7 +64,7 @@ func (x *Change_Type) UnmarshalJSON(data []byte) error {
 	return nil
 }
 func (Change_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{4, 0}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{5, 0}
 }
 
 type ChangeResponse_Code int32
@@ -115,7 +115,7 @@ func (x *ChangeResponse_Code) UnmarshalJSON(data []byte) error {
 	return nil
 }
 func (ChangeResponse_Code) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{5, 0}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{6, 0}
 }
 
 type Update_Type int32
@@ -160,7 +160,7 @@ func (x *Update_Type) UnmarshalJSON(data []byte) error {
 	return nil
 }
 func (Update_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{6, 0}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{7, 0}
 }
 
 type LookupRequest_Type int32
@@ -205,7 +205,7 @@ func (x *LookupRequest_Type) UnmarshalJSON(data []byte) error {
 	return nil
 }
 func (LookupRequest_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{7, 0}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{8, 0}
 }
 
 type LookupResponse_Code int32
@@ -241,7 +241,7 @@ func (x *LookupResponse_Code) UnmarshalJSON(data []byte) error {
 	return nil
 }
 func (LookupResponse_Code) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{8, 0}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{9, 0}
 }
 
 type Request_Type int32
@@ -283,7 +283,7 @@ func (x *Request_Type) UnmarshalJSON(data []byte) error {
 	return nil
 }
 func (Request_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{9, 0}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{10, 0}
 }
 
 type ClusterState_State int32
@@ -319,7 +319,7 @@ func (x *ClusterState_State) UnmarshalJSON(data []byte) error {
 	return nil
 }
 func (ClusterState_State) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{11, 0}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{12, 0}
 }
 
 // Regions is the message used to describe the requested region.
@@ -332,7 +332,7 @@ func (m *Regions) Reset()         { *m = Regions{} }
 func (m *Regions) String() string { return proto.CompactTextString(m) }
 func (*Regions) ProtoMessage()    {}
 func (*Regions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{0}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{0}
 }
 func (m *Regions) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -375,18 +375,73 @@ func (m *Regions) GetCount() []uint64 {
 	return nil
 }
 
+// PlacementConstraint is the message used to describe the maximum number of
+// raft nodes that can be placed on nodehosts sharing the same label value.
+type PlacementConstraint struct {
+	Label       string `protobuf:"bytes,1,opt,name=label" json:"label"`
+	MaxReplicas uint64 `protobuf:"varint,2,opt,name=max_replicas,json=maxReplicas" json:"max_replicas"`
+}
+
+func (m *PlacementConstraint) Reset()         { *m = PlacementConstraint{} }
+func (m *PlacementConstraint) String() string { return proto.CompactTextString(m) }
+func (*PlacementConstraint) ProtoMessage()    {}
+func (*PlacementConstraint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_drummer_217d22f7def87e80, []int{1}
+}
+func (m *PlacementConstraint) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PlacementConstraint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PlacementConstraint.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *PlacementConstraint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlacementConstraint.Merge(dst, src)
+}
+func (m *PlacementConstraint) XXX_Size() int {
+	return m.Size()
+}
+func (m *PlacementConstraint) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlacementConstraint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlacementConstraint proto.InternalMessageInfo
+
+func (m *PlacementConstraint) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *PlacementConstraint) GetMaxReplicas() uint64 {
+	if m != nil {
+		return m.MaxReplicas
+	}
+	return 0
+}
+
 // Cluster is the message used to describe a defined raft cluster.
 type Cluster struct {
-	Members   []uint64 `protobuf:"varint,1,rep,name=members" json:"members,omitempty"`
-	ClusterId uint64   `protobuf:"varint,2,opt,name=cluster_id,json=clusterId" json:"cluster_id"`
-	AppName   string   `protobuf:"bytes,3,opt,name=app_name,json=appName" json:"app_name"`
+	Members     []uint64              `protobuf:"varint,1,rep,name=members" json:"members,omitempty"`
+	ClusterId   uint64                `protobuf:"varint,2,opt,name=cluster_id,json=clusterId" json:"cluster_id"`
+	AppName     string                `protobuf:"bytes,3,opt,name=app_name,json=appName" json:"app_name"`
+	Constraints []PlacementConstraint `protobuf:"bytes,4,rep,name=constraints" json:"constraints"`
 }
 
 func (m *Cluster) Reset()         { *m = Cluster{} }
 func (m *Cluster) String() string { return proto.CompactTextString(m) }
 func (*Cluster) ProtoMessage()    {}
 func (*Cluster) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{1}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{2}
 }
 func (m *Cluster) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -436,6 +491,13 @@ func (m *Cluster) GetAppName() string {
 	return ""
 }
 
+func (m *Cluster) GetConstraints() []PlacementConstraint {
+	if m != nil {
+		return m.Constraints
+	}
+	return nil
+}
+
 // ClusterCollection is the message used to describe a list of clusters.
 type ClusterCollection struct {
 	Clusters []*Cluster `protobuf:"bytes,1,rep,name=clusters" json:"clusters,omitempty"`
@@ -445,7 +507,7 @@ func (m *ClusterCollection) Reset()         { *m = ClusterCollection{} }
 func (m *ClusterCollection) String() string { return proto.CompactTextString(m) }
 func (*ClusterCollection) ProtoMessage()    {}
 func (*ClusterCollection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{2}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{3}
 }
 func (m *ClusterCollection) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -495,7 +557,7 @@ func (m *KV) Reset()         { *m = KV{} }
 func (m *KV) String() string { return proto.CompactTextString(m) }
 func (*KV) ProtoMessage()    {}
 func (*KV) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{3}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{4}
 }
 func (m *KV) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -568,17 +630,18 @@ func (m *KV) GetFinalized() bool {
 
 // Change is the message used to define new raft clusters in Drummer.
 type Change struct {
-	Type      Change_Type `protobuf:"varint,1,req,name=type,enum=drummerpb.Change_Type" json:"type"`
-	ClusterId uint64      `protobuf:"varint,2,req,name=cluster_id,json=clusterId" json:"cluster_id"`
-	Members   []uint64    `protobuf:"varint,3,rep,name=members" json:"members,omitempty"`
-	AppName   string      `protobuf:"bytes,4,opt,name=app_name,json=appName" json:"app_name"`
+	Type        Change_Type           `protobuf:"varint,1,req,name=type,enum=drummerpb.Change_Type" json:"type"`
+	ClusterId   uint64                `protobuf:"varint,2,req,name=cluster_id,json=clusterId" json:"cluster_id"`
+	Members     []uint64              `protobuf:"varint,3,rep,name=members" json:"members,omitempty"`
+	AppName     string                `protobuf:"bytes,4,opt,name=app_name,json=appName" json:"app_name"`
+	Constraints []PlacementConstraint `protobuf:"bytes,5,rep,name=constraints" json:"constraints"`
 }
 
 func (m *Change) Reset()         { *m = Change{} }
 func (m *Change) String() string { return proto.CompactTextString(m) }
 func (*Change) ProtoMessage()    {}
 func (*Change) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{4}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{5}
 }
 func (m *Change) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -635,6 +698,13 @@ func (m *Change) GetAppName() string {
 	return ""
 }
 
+func (m *Change) GetConstraints() []PlacementConstraint {
+	if m != nil {
+		return m.Constraints
+	}
+	return nil
+}
+
 // ChangeResponse is the message issued by Drummer in response to Change
 // messages.
 type ChangeResponse struct {
@@ -645,7 +715,7 @@ func (m *ChangeResponse) Reset()         { *m = ChangeResponse{} }
 func (m *ChangeResponse) String() string { return proto.CompactTextString(m) }
 func (*ChangeResponse) ProtoMessage()    {}
 func (*ChangeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{5}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{6}
 }
 func (m *ChangeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -695,7 +765,7 @@ func (m *Update) Reset()         { *m = Update{} }
 func (m *Update) String() string { return proto.CompactTextString(m) }
 func (*Update) ProtoMessage()    {}
 func (*Update) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{6}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{7}
 }
 func (m *Update) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -773,7 +843,7 @@ func (m *LookupRequest) Reset()         { *m = LookupRequest{} }
 func (m *LookupRequest) String() string { return proto.CompactTextString(m) }
 func (*LookupRequest) ProtoMessage()    {}
 func (*LookupRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{7}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{8}
 }
 func (m *LookupRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -856,7 +926,7 @@ func (m *LookupResponse) Reset()         { *m = LookupResponse{} }
 func (m *LookupResponse) String() string { return proto.CompactTextString(m) }
 func (*LookupResponse) ProtoMessage()    {}
 func (*LookupResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{8}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{9}
 }
 func (m *LookupResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -926,7 +996,7 @@ func (m *Request) Reset()         { *m = Request{} }
 func (m *Request) String() string { return proto.CompactTextString(m) }
 func (*Request) ProtoMessage()    {}
 func (*Request) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{9}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{10}
 }
 func (m *Request) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1000,7 +1070,7 @@ func (m *ClusterStateRequest) Reset()         { *m = ClusterStateRequest{} }
 func (m *ClusterStateRequest) String() string { return proto.CompactTextString(m) }
 func (*ClusterStateRequest) ProtoMessage()    {}
 func (*ClusterStateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{10}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{11}
 }
 func (m *ClusterStateRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1050,7 +1120,7 @@ func (m *ClusterState) Reset()         { *m = ClusterState{} }
 func (m *ClusterState) String() string { return proto.CompactTextString(m) }
 func (*ClusterState) ProtoMessage()    {}
 func (*ClusterState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{11}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{12}
 }
 func (m *ClusterState) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1135,7 +1205,7 @@ func (m *ClusterInfo) Reset()         { *m = ClusterInfo{} }
 func (m *ClusterInfo) String() string { return proto.CompactTextString(m) }
 func (*ClusterInfo) ProtoMessage()    {}
 func (*ClusterInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{12}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{13}
 }
 func (m *ClusterInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1224,7 +1294,7 @@ func (m *LogInfo) Reset()         { *m = LogInfo{} }
 func (m *LogInfo) String() string { return proto.CompactTextString(m) }
 func (*LogInfo) ProtoMessage()    {}
 func (*LogInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{13}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{14}
 }
 func (m *LogInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1276,7 +1346,7 @@ func (m *ClusterStates) Reset()         { *m = ClusterStates{} }
 func (m *ClusterStates) String() string { return proto.CompactTextString(m) }
 func (*ClusterStates) ProtoMessage()    {}
 func (*ClusterStates) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{14}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{15}
 }
 func (m *ClusterStates) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1315,21 +1385,22 @@ func (m *ClusterStates) GetCollection() []*ClusterState {
 // NodeHostInfo is the message used by nodehost to report its state, including
 // managed raft clusters and local persistent logs to Drummer.
 type NodeHostInfo struct {
-	RaftAddress      string        `protobuf:"bytes,1,req,name=raft_address,json=raftAddress" json:"raft_address"`
-	ClusterInfo      []ClusterInfo `protobuf:"bytes,2,rep,name=cluster_info,json=clusterInfo" json:"cluster_info"`
-	ClusterIdList    []uint64      `protobuf:"varint,3,rep,name=cluster_id_list,json=clusterIdList" json:"cluster_id_list,omitempty"`
-	LastTick         uint64        `protobuf:"varint,4,opt,name=last_tick,json=lastTick" json:"last_tick"`
-	PlogInfoIncluded bool          `protobuf:"varint,5,opt,name=plog_info_included,json=plogInfoIncluded" json:"plog_info_included"`
-	PlogInfo         []LogInfo     `protobuf:"bytes,6,rep,name=plog_info,json=plogInfo" json:"plog_info"`
-	Region           string        `protobuf:"bytes,7,opt,name=region" json:"region"`
-	RPCAddress       string        `protobuf:"bytes,8,opt,name=RPCAddress" json:"RPCAddress"`
+	RaftAddress      string            `protobuf:"bytes,1,req,name=raft_address,json=raftAddress" json:"raft_address"`
+	ClusterInfo      []ClusterInfo     `protobuf:"bytes,2,rep,name=cluster_info,json=clusterInfo" json:"cluster_info"`
+	ClusterIdList    []uint64          `protobuf:"varint,3,rep,name=cluster_id_list,json=clusterIdList" json:"cluster_id_list,omitempty"`
+	LastTick         uint64            `protobuf:"varint,4,opt,name=last_tick,json=lastTick" json:"last_tick"`
+	PlogInfoIncluded bool              `protobuf:"varint,5,opt,name=plog_info_included,json=plogInfoIncluded" json:"plog_info_included"`
+	PlogInfo         []LogInfo         `protobuf:"bytes,6,rep,name=plog_info,json=plogInfo" json:"plog_info"`
+	Region           string            `protobuf:"bytes,7,opt,name=region" json:"region"`
+	RPCAddress       string            `protobuf:"bytes,8,opt,name=RPCAddress" json:"RPCAddress"`
+	Labels           map[string]string `protobuf:"bytes,9,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *NodeHostInfo) Reset()         { *m = NodeHostInfo{} }
 func (m *NodeHostInfo) String() string { return proto.CompactTextString(m) }
 func (*NodeHostInfo) ProtoMessage()    {}
 func (*NodeHostInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{15}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{16}
 }
 func (m *NodeHostInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1414,6 +1485,13 @@ func (m *NodeHostInfo) GetRPCAddress() string {
 	return ""
 }
 
+func (m *NodeHostInfo) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 // NodeHostCollection contains a list of NodeHostInfo messages.
 type NodeHostCollection struct {
 	Collection []NodeHostInfo `protobuf:"bytes,1,rep,name=collection" json:"collection"`
@@ -1424,7 +1502,7 @@ func (m *NodeHostCollection) Reset()         { *m = NodeHostCollection{} }
 func (m *NodeHostCollection) String() string { return proto.CompactTextString(m) }
 func (*NodeHostCollection) ProtoMessage()    {}
 func (*NodeHostCollection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{16}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{17}
 }
 func (m *NodeHostCollection) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1477,7 +1555,7 @@ func (m *ConfigChangeIndexList) Reset()         { *m = ConfigChangeIndexList{} }
 func (m *ConfigChangeIndexList) String() string { return proto.CompactTextString(m) }
 func (*ConfigChangeIndexList) ProtoMessage()    {}
 func (*ConfigChangeIndexList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{17}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{18}
 }
 func (m *ConfigChangeIndexList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1523,7 +1601,7 @@ func (m *DeploymentInfo) Reset()         { *m = DeploymentInfo{} }
 func (m *DeploymentInfo) String() string { return proto.CompactTextString(m) }
 func (*DeploymentInfo) ProtoMessage()    {}
 func (*DeploymentInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{18}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{19}
 }
 func (m *DeploymentInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1567,7 +1645,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{19}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{20}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1613,7 +1691,7 @@ func (m *NodeHostRequest) Reset()         { *m = NodeHostRequest{} }
 func (m *NodeHostRequest) String() string { return proto.CompactTextString(m) }
 func (*NodeHostRequest) ProtoMessage()    {}
 func (*NodeHostRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{20}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{21}
 }
 func (m *NodeHostRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1714,7 +1792,7 @@ func (m *NodeHostRequestCollection) Reset()         { *m = NodeHostRequestCollec
 func (m *NodeHostRequestCollection) String() string { return proto.CompactTextString(m) }
 func (*NodeHostRequestCollection) ProtoMessage()    {}
 func (*NodeHostRequestCollection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{21}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{22}
 }
 func (m *NodeHostRequestCollection) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1759,7 +1837,7 @@ func (m *DrummerConfigRequest) Reset()         { *m = DrummerConfigRequest{} }
 func (m *DrummerConfigRequest) String() string { return proto.CompactTextString(m) }
 func (*DrummerConfigRequest) ProtoMessage()    {}
 func (*DrummerConfigRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{22}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{23}
 }
 func (m *DrummerConfigRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1825,7 +1903,7 @@ func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_drummer_dbd5fe4da91fe005, []int{23}
+	return fileDescriptor_drummer_217d22f7def87e80, []int{24}
 }
 func (m *Config) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1961,6 +2039,7 @@ func (m *Config) GetMaxInMemLogSize() uint64 {
 
 func init() {
 	proto.RegisterType((*Regions)(nil), "drummerpb.Regions")
+	proto.RegisterType((*PlacementConstraint)(nil), "drummerpb.PlacementConstraint")
 	proto.RegisterType((*Cluster)(nil), "drummerpb.Cluster")
 	proto.RegisterType((*ClusterCollection)(nil), "drummerpb.ClusterCollection")
 	proto.RegisterType((*KV)(nil), "drummerpb.KV")
@@ -1979,6 +2058,7 @@ func init() {
 	proto.RegisterType((*LogInfo)(nil), "drummerpb.LogInfo")
 	proto.RegisterType((*ClusterStates)(nil), "drummerpb.ClusterStates")
 	proto.RegisterType((*NodeHostInfo)(nil), "drummerpb.NodeHostInfo")
+	proto.RegisterMapType((map[string]string)(nil), "drummerpb.NodeHostInfo.LabelsEntry")
 	proto.RegisterType((*NodeHostCollection)(nil), "drummerpb.NodeHostCollection")
 	proto.RegisterType((*ConfigChangeIndexList)(nil), "drummerpb.ConfigChangeIndexList")
 	proto.RegisterMapType((map[uint64]uint64)(nil), "drummerpb.ConfigChangeIndexList.IndexesEntry")
@@ -2469,6 +2549,31 @@ func (m *Regions) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *PlacementConstraint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PlacementConstraint) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintDrummer(dAtA, i, uint64(len(m.Label)))
+	i += copy(dAtA[i:], m.Label)
+	dAtA[i] = 0x10
+	i++
+	i = encodeVarintDrummer(dAtA, i, uint64(m.MaxReplicas))
+	return i, nil
+}
+
 func (m *Cluster) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2498,6 +2603,18 @@ func (m *Cluster) MarshalTo(dAtA []byte) (int, error) {
 	i++
 	i = encodeVarintDrummer(dAtA, i, uint64(len(m.AppName)))
 	i += copy(dAtA[i:], m.AppName)
+	if len(m.Constraints) > 0 {
+		for _, msg := range m.Constraints {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintDrummer(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -2606,6 +2723,18 @@ func (m *Change) MarshalTo(dAtA []byte) (int, error) {
 	i++
 	i = encodeVarintDrummer(dAtA, i, uint64(len(m.AppName)))
 	i += copy(dAtA[i:], m.AppName)
+	if len(m.Constraints) > 0 {
+		for _, msg := range m.Constraints {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintDrummer(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -3098,6 +3227,23 @@ func (m *NodeHostInfo) MarshalTo(dAtA []byte) (int, error) {
 	i++
 	i = encodeVarintDrummer(dAtA, i, uint64(len(m.RPCAddress)))
 	i += copy(dAtA[i:], m.RPCAddress)
+	if len(m.Labels) > 0 {
+		for k, _ := range m.Labels {
+			dAtA[i] = 0x4a
+			i++
+			v := m.Labels[k]
+			mapSize := 1 + len(k) + sovDrummer(uint64(len(k))) + 1 + len(v) + sovDrummer(uint64(len(v)))
+			i = encodeVarintDrummer(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintDrummer(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintDrummer(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
 	return i, nil
 }
 
@@ -3457,6 +3603,18 @@ func (m *Regions) Size() (n int) {
 	return n
 }
 
+func (m *PlacementConstraint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Label)
+	n += 1 + l + sovDrummer(uint64(l))
+	n += 1 + sovDrummer(uint64(m.MaxReplicas))
+	return n
+}
+
 func (m *Cluster) Size() (n int) {
 	if m == nil {
 		return 0
@@ -3471,6 +3629,12 @@ func (m *Cluster) Size() (n int) {
 	n += 1 + sovDrummer(uint64(m.ClusterId))
 	l = len(m.AppName)
 	n += 1 + l + sovDrummer(uint64(l))
+	if len(m.Constraints) > 0 {
+		for _, e := range m.Constraints {
+			l = e.Size()
+			n += 1 + l + sovDrummer(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -3521,6 +3685,12 @@ func (m *Change) Size() (n int) {
 	}
 	l = len(m.AppName)
 	n += 1 + l + sovDrummer(uint64(l))
+	if len(m.Constraints) > 0 {
+		for _, e := range m.Constraints {
+			l = e.Size()
+			n += 1 + l + sovDrummer(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -3736,6 +3906,14 @@ func (m *NodeHostInfo) Size() (n int) {
 	n += 1 + l + sovDrummer(uint64(l))
 	l = len(m.RPCAddress)
 	n += 1 + l + sovDrummer(uint64(l))
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovDrummer(uint64(len(k))) + 1 + len(v) + sovDrummer(uint64(len(v)))
+			n += mapEntrySize + 1 + sovDrummer(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -4045,6 +4223,104 @@ func (m *Regions) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *PlacementConstraint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDrummer
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PlacementConstraint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PlacementConstraint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDrummer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDrummer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Label = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxReplicas", wireType)
+			}
+			m.MaxReplicas = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDrummer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxReplicas |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDrummer(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDrummer
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *Cluster) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -4195,6 +4471,37 @@ func (m *Cluster) Unmarshal(dAtA []byte) error {
 			}
 			m.AppName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Constraints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDrummer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDrummer
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Constraints = append(m.Constraints, PlacementConstraint{})
+			if err := m.Constraints[len(m.Constraints)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDrummer(dAtA[iNdEx:])
@@ -4663,6 +4970,37 @@ func (m *Change) Unmarshal(dAtA []byte) error {
 			}
 			m.AppName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Constraints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDrummer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDrummer
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Constraints = append(m.Constraints, PlacementConstraint{})
+			if err := m.Constraints[len(m.Constraints)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDrummer(dAtA[iNdEx:])
@@ -6812,6 +7150,124 @@ func (m *NodeHostInfo) Unmarshal(dAtA []byte) error {
 			}
 			m.RPCAddress = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDrummer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDrummer
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowDrummer
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowDrummer
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthDrummer
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowDrummer
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthDrummer
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipDrummer(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthDrummer
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Labels[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDrummer(dAtA[iNdEx:])
@@ -8231,141 +8687,147 @@ var (
 	ErrIntOverflowDrummer   = fmt.Errorf("proto: integer overflow")
 )
 
-func init() { proto.RegisterFile("drummer.proto", fileDescriptor_drummer_dbd5fe4da91fe005) }
-
-var fileDescriptor_drummer_dbd5fe4da91fe005 = []byte{
-	// 2121 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0x4d, 0x73, 0xdb, 0xc6,
-	0x19, 0x26, 0xf8, 0xcd, 0x97, 0xa4, 0x04, 0xad, 0xbf, 0x10, 0x4d, 0x2c, 0xd3, 0xa8, 0x93, 0x2a,
-	0x9e, 0x44, 0x76, 0x34, 0x6e, 0xed, 0xa6, 0x75, 0x13, 0x8a, 0x84, 0x64, 0x56, 0x34, 0xe9, 0x80,
-	0x90, 0x9d, 0xf4, 0xc2, 0x81, 0x88, 0x95, 0x84, 0x8a, 0x04, 0x50, 0x60, 0xa9, 0x89, 0x72, 0xec,
-	0x0f, 0xe8, 0xf4, 0xde, 0x3f, 0xd0, 0xe9, 0xa1, 0xb7, 0xfe, 0x87, 0xcc, 0xf4, 0xd0, 0x1c, 0x7b,
-	0xea, 0x74, 0xec, 0x1f, 0xd0, 0x69, 0x7b, 0xcc, 0xa5, 0x83, 0xdd, 0x05, 0xb8, 0x20, 0x20, 0xcb,
-	0xd3, 0x3a, 0x17, 0x0e, 0xf1, 0x7e, 0xed, 0xee, 0xfb, 0xbc, 0x5f, 0xbb, 0xd0, 0xb4, 0xfc, 0xf9,
-	0x6c, 0x86, 0xfd, 0x2d, 0xcf, 0x77, 0x89, 0x8b, 0x6a, 0xfc, 0xd3, 0x3b, 0x5c, 0xff, 0xe8, 0xd8,
-	0x26, 0x27, 0xf3, 0xc3, 0xad, 0x89, 0x3b, 0xbb, 0x77, 0xec, 0x1e, 0xbb, 0xf7, 0xa8, 0xc4, 0xe1,
-	0xfc, 0x88, 0x7e, 0xd1, 0x0f, 0xfa, 0x8f, 0x69, 0xaa, 0x0f, 0xa1, 0xa2, 0xe3, 0x63, 0xdb, 0x75,
-	0x02, 0x74, 0x1d, 0xca, 0x3e, 0xfd, 0xab, 0x48, 0xad, 0xc2, 0x66, 0x4d, 0xe7, 0x5f, 0xe8, 0x2a,
-	0x94, 0x26, 0xee, 0xdc, 0x21, 0x4a, 0xbe, 0x55, 0xd8, 0x2c, 0xea, 0xec, 0x43, 0xb5, 0xa1, 0xd2,
-	0x99, 0xce, 0x03, 0x82, 0x7d, 0xa4, 0x40, 0x65, 0x86, 0x67, 0x87, 0xd8, 0x0f, 0xa8, 0x66, 0x51,
-	0x8f, 0x3e, 0xd1, 0x0f, 0x00, 0x26, 0x4c, 0x68, 0x6c, 0x5b, 0x4a, 0xbe, 0x25, 0x6d, 0x16, 0x77,
-	0x8a, 0xdf, 0xfc, 0xfd, 0x56, 0x4e, 0xaf, 0x71, 0x7a, 0xcf, 0x42, 0xb7, 0xa0, 0x6a, 0x7a, 0xde,
-	0xd8, 0x31, 0x67, 0x58, 0x29, 0xb4, 0xa4, 0xcd, 0x1a, 0x17, 0xa9, 0x98, 0x9e, 0x37, 0x30, 0x67,
-	0x58, 0xed, 0xc0, 0x1a, 0x5f, 0xaa, 0xe3, 0x4e, 0xa7, 0x78, 0x42, 0xc2, 0x5d, 0x6d, 0x41, 0x95,
-	0x9b, 0x60, 0xab, 0xd6, 0xb7, 0xd1, 0x56, 0xec, 0x85, 0x2d, 0x2e, 0xaf, 0xc7, 0x32, 0xea, 0x5f,
-	0x24, 0xc8, 0xef, 0x3f, 0x47, 0xd7, 0xa1, 0x70, 0x8a, 0xcf, 0x15, 0xa9, 0x95, 0x8f, 0xd7, 0x09,
-	0x09, 0x68, 0x1d, 0x4a, 0x67, 0xe6, 0x74, 0x8e, 0x95, 0xbc, 0xc0, 0x61, 0x24, 0xf4, 0x1e, 0xd4,
-	0x6d, 0x27, 0x20, 0xa6, 0x33, 0xc1, 0xe1, 0x31, 0x0a, 0xc2, 0x31, 0x20, 0x62, 0xf4, 0x2c, 0xa4,
-	0x40, 0x91, 0xd8, 0x93, 0x53, 0xa5, 0x28, 0xf0, 0x29, 0x05, 0x7d, 0x08, 0xab, 0xee, 0xd4, 0x1a,
-	0x8b, 0x46, 0x4a, 0x82, 0x50, 0xd3, 0x9d, 0x5a, 0xbd, 0x85, 0x1d, 0x15, 0x6a, 0x47, 0xb6, 0x63,
-	0x4e, 0xed, 0xaf, 0xb1, 0xa5, 0x94, 0x5b, 0xd2, 0x66, 0x35, 0xf2, 0x59, 0x4c, 0x56, 0xff, 0x24,
-	0x41, 0xb9, 0x73, 0x62, 0x3a, 0xc7, 0x18, 0xdd, 0x87, 0x22, 0x39, 0xf7, 0x30, 0x3d, 0xd2, 0xca,
-	0xf6, 0x75, 0xd1, 0x09, 0x54, 0x60, 0xcb, 0x38, 0xf7, 0x70, 0xbc, 0x9d, 0x73, 0x0f, 0xa7, 0x50,
-	0xc9, 0x67, 0xa1, 0x22, 0x80, 0x5a, 0x48, 0x82, 0x2a, 0xe2, 0x55, 0xcc, 0xc2, 0x0b, 0x41, 0x31,
-	0x5c, 0x13, 0x01, 0x94, 0x3b, 0xba, 0xd6, 0x36, 0x34, 0x39, 0xa7, 0xfe, 0x55, 0x82, 0x15, 0xb6,
-	0x1f, 0x1d, 0x07, 0x9e, 0xeb, 0x04, 0x18, 0x3d, 0x82, 0xe2, 0xc4, 0xb5, 0xa2, 0x8d, 0x6f, 0xa4,
-	0x36, 0x1e, 0x09, 0x6e, 0x75, 0x5c, 0x2b, 0x3e, 0x40, 0xa8, 0xa1, 0xfe, 0x46, 0x82, 0x62, 0x48,
-	0x44, 0x65, 0xc8, 0x0f, 0xf7, 0xe5, 0x1c, 0xba, 0x06, 0x6b, 0x9d, 0xfe, 0xc1, 0xc8, 0xd0, 0xf4,
-	0xf1, 0x60, 0x68, 0x8c, 0x77, 0x87, 0x07, 0x83, 0xae, 0x2c, 0x21, 0x04, 0x2b, 0x9d, 0xe1, 0x60,
-	0xb7, 0xdf, 0xeb, 0x44, 0xb4, 0x3c, 0x5a, 0x83, 0xe6, 0xc1, 0x60, 0x7f, 0x30, 0x7c, 0x31, 0x18,
-	0xeb, 0x9a, 0xa1, 0x7f, 0x29, 0x17, 0x42, 0x52, 0xa4, 0xad, 0x7d, 0xd1, 0x1b, 0x19, 0x72, 0x11,
-	0xc9, 0xd0, 0xd8, 0x19, 0x0e, 0x8d, 0x91, 0xa1, 0xb7, 0x9f, 0x3d, 0xd3, 0xba, 0x72, 0x09, 0xad,
-	0x42, 0x5d, 0xd7, 0xf6, 0x7a, 0xc3, 0xc1, 0x68, 0x3c, 0xd2, 0x0c, 0xb9, 0xac, 0xfe, 0x2b, 0x0f,
-	0xe5, 0x03, 0xcf, 0x32, 0x09, 0x46, 0xf7, 0xa0, 0x3c, 0xa1, 0x5b, 0x56, 0xa4, 0x96, 0xb4, 0x59,
-	0xdf, 0x5e, 0x4b, 0x9d, 0x85, 0x6f, 0x9f, 0x8b, 0xc5, 0x98, 0xe5, 0x53, 0x98, 0x31, 0x8b, 0x69,
-	0xcc, 0xee, 0x43, 0xed, 0xf4, 0x6c, 0x3c, 0xa7, 0x5c, 0x1a, 0x81, 0xf5, 0xed, 0xa6, 0xa0, 0xb6,
-	0xff, 0x9c, 0x4b, 0x57, 0x4f, 0xcf, 0xf8, 0xa6, 0x76, 0xa0, 0xe9, 0xb8, 0x16, 0x3e, 0x71, 0x03,
-	0x32, 0xb6, 0x9d, 0x23, 0x97, 0x62, 0x55, 0xdf, 0xbe, 0x21, 0x68, 0x0d, 0x5c, 0x0b, 0x3f, 0x71,
-	0x03, 0xd2, 0x73, 0x8e, 0x5c, 0xae, 0xdf, 0x88, 0x74, 0x42, 0x1a, 0xda, 0x85, 0xaa, 0x8f, 0x7f,
-	0x3d, 0xc7, 0x01, 0x09, 0x68, 0xc4, 0xd6, 0xb7, 0xef, 0x64, 0xa8, 0xeb, 0x4c, 0x64, 0x91, 0x9c,
-	0xd1, 0x5e, 0x22, 0x5d, 0x75, 0x97, 0x47, 0x44, 0x1d, 0x2a, 0xdc, 0xd3, 0x72, 0x2e, 0x04, 0x6f,
-	0xff, 0xb9, 0x2c, 0xa1, 0x2a, 0x14, 0x8d, 0x5e, 0x67, 0x9f, 0x61, 0x33, 0x18, 0x76, 0xb5, 0x27,
-	0xc3, 0x91, 0x31, 0xee, 0x0d, 0x76, 0x87, 0x72, 0x01, 0x35, 0xa0, 0xaa, 0x6b, 0x9f, 0x1f, 0x68,
-	0x23, 0x63, 0x24, 0x17, 0xd5, 0xef, 0xf2, 0xd0, 0xec, 0xbb, 0xee, 0xe9, 0xdc, 0xe3, 0x6b, 0xa2,
-	0x87, 0x89, 0xe8, 0xbf, 0x29, 0xec, 0x2e, 0x21, 0x97, 0x76, 0xe8, 0xfb, 0xb0, 0xba, 0x48, 0x82,
-	0xf1, 0xd4, 0x0e, 0xa2, 0xfa, 0xd6, 0x8c, 0x73, 0xa0, 0x6f, 0x07, 0x84, 0x3b, 0x7e, 0x4a, 0x8d,
-	0x5d, 0xe2, 0x78, 0xb6, 0x62, 0x58, 0x2e, 0xa2, 0x83, 0x87, 0xf9, 0x25, 0x96, 0x03, 0x88, 0x18,
-	0x3d, 0x0b, 0x6d, 0x40, 0xc5, 0xb4, 0x2c, 0x1f, 0x07, 0xcc, 0xb5, 0x8b, 0x2c, 0x62, 0x44, 0xf4,
-	0x09, 0x94, 0x02, 0x62, 0x92, 0x80, 0x96, 0x80, 0x7a, 0x32, 0x3f, 0xd8, 0x0e, 0x47, 0xc4, 0x24,
-	0x98, 0x1f, 0x30, 0xaa, 0x58, 0x54, 0x45, 0x35, 0x5e, 0xe7, 0xef, 0x6b, 0xb0, 0x36, 0xea, 0x3c,
-	0xd1, 0xba, 0x07, 0x7d, 0x4d, 0x1f, 0x77, 0x86, 0x03, 0x43, 0xfb, 0xc2, 0x58, 0xf6, 0x34, 0x4d,
-	0x1d, 0x9e, 0x13, 0x23, 0xa3, 0x6d, 0x68, 0x23, 0xb9, 0xa4, 0xfe, 0x3e, 0x0f, 0x2b, 0x91, 0x57,
-	0x53, 0x39, 0x2c, 0x2d, 0xe5, 0x70, 0x52, 0x30, 0x95, 0xc3, 0x89, 0xfa, 0x9d, 0xbf, 0xbc, 0x7e,
-	0x73, 0x1c, 0x7c, 0x1c, 0xcc, 0xa7, 0xe4, 0x12, 0x1c, 0x74, 0x2a, 0x94, 0x08, 0xde, 0xe2, 0xff,
-	0x11, 0xbc, 0xef, 0xbd, 0x51, 0xb1, 0x51, 0xbf, 0x93, 0xc2, 0x56, 0xca, 0xa2, 0xf2, 0xe3, 0x44,
-	0x54, 0x8a, 0x29, 0x77, 0x61, 0x3c, 0x7e, 0xcf, 0x45, 0x19, 0xdd, 0x85, 0x95, 0x89, 0xeb, 0x1c,
-	0x8d, 0x59, 0x05, 0x5a, 0x6e, 0x41, 0x8d, 0x90, 0xc7, 0x4a, 0x55, 0xcf, 0x52, 0x3f, 0x4e, 0x17,
-	0xf0, 0xf0, 0x7f, 0x57, 0xeb, 0x6b, 0x86, 0x26, 0x4b, 0xa8, 0x02, 0x85, 0x76, 0x37, 0x2c, 0xa6,
-	0x55, 0x28, 0xee, 0xf7, 0xfa, 0x7d, 0xb9, 0xa0, 0x3e, 0x86, 0x2b, 0x19, 0x51, 0x99, 0x95, 0x65,
-	0x52, 0x46, 0x96, 0xa9, 0xbf, 0x2d, 0x42, 0x43, 0xd4, 0x5f, 0x72, 0x87, 0x94, 0xed, 0x8e, 0xbb,
-	0xb0, 0x32, 0xc5, 0xa6, 0x85, 0xfd, 0x71, 0x58, 0xb5, 0x96, 0x47, 0x8c, 0x06, 0xe3, 0x85, 0x40,
-	0xf7, 0x2c, 0xf4, 0x08, 0x4a, 0xa1, 0x10, 0x73, 0x5c, 0x7d, 0x5b, 0xbd, 0x20, 0x9d, 0x68, 0x5c,
-	0x04, 0x9a, 0x43, 0xfc, 0x73, 0x9d, 0x29, 0xa0, 0xa7, 0xd0, 0xd0, 0x9f, 0x75, 0xda, 0x2c, 0x2d,
-	0x71, 0x18, 0x4b, 0xa1, 0x81, 0x0f, 0x2e, 0x32, 0x20, 0xca, 0x32, 0x3b, 0x09, 0x75, 0xf4, 0x13,
-	0x96, 0xd7, 0x98, 0xfa, 0x3f, 0x59, 0xb2, 0x12, 0x76, 0xe8, 0xaf, 0x98, 0xd6, 0x18, 0x3d, 0x80,
-	0x2b, 0x21, 0x4e, 0xf6, 0x71, 0x8c, 0xa2, 0x63, 0xe1, 0xaf, 0x68, 0x81, 0x88, 0x0e, 0xbd, 0xc6,
-	0x04, 0x38, 0x94, 0x21, 0x7b, 0xbd, 0x0b, 0xb0, 0x38, 0xd4, 0x62, 0x00, 0x5a, 0xe8, 0x2c, 0x0f,
-	0x40, 0xd2, 0xd2, 0x00, 0xf4, 0x49, 0xfe, 0x91, 0xb4, 0xbe, 0x0f, 0x6b, 0xa9, 0x93, 0xfd, 0xaf,
-	0xc6, 0xd4, 0x16, 0x94, 0x18, 0xcc, 0x51, 0x4e, 0xad, 0x42, 0xfd, 0x60, 0xd0, 0x7e, 0xde, 0xee,
-	0xf5, 0xdb, 0x3b, 0x7d, 0x4d, 0x96, 0xd4, 0x7f, 0xe6, 0xa1, 0xce, 0xdd, 0x41, 0x3b, 0xd1, 0x1b,
-	0xc5, 0xc3, 0x4d, 0xa8, 0x2c, 0x02, 0x61, 0x21, 0x51, 0x76, 0x58, 0x08, 0xdc, 0x86, 0x9a, 0x1d,
-	0x8c, 0x59, 0x54, 0xd0, 0x12, 0x12, 0x0d, 0x56, 0x55, 0x3b, 0xe8, 0x53, 0x2a, 0x7a, 0x18, 0x45,
-	0x09, 0x03, 0xf9, 0x76, 0x1a, 0x9c, 0x70, 0x37, 0x19, 0x41, 0x72, 0x01, 0x34, 0xa5, 0xd7, 0x42,
-	0x83, 0xee, 0x00, 0xd8, 0xce, 0xc4, 0x9d, 0x79, 0x53, 0x4c, 0x70, 0x62, 0xd6, 0x13, 0xe8, 0x61,
-	0xa7, 0xf0, 0xb0, 0x63, 0xd9, 0xce, 0xb1, 0x52, 0x11, 0x44, 0x22, 0xe2, 0xdb, 0x01, 0x58, 0x7d,
-	0x0a, 0x95, 0xbe, 0x7b, 0xfc, 0xb6, 0x9c, 0xad, 0x3e, 0x81, 0xa6, 0x18, 0xce, 0x01, 0x7a, 0x08,
-	0x30, 0x89, 0x8b, 0x2c, 0x1f, 0xd9, 0x6f, 0x5c, 0xd4, 0xd4, 0x04, 0x51, 0xf5, 0xdf, 0x79, 0x68,
-	0x88, 0x93, 0x0a, 0xfa, 0x21, 0x34, 0x7c, 0xf3, 0x88, 0x8c, 0xa3, 0xf6, 0x29, 0x0e, 0xf3, 0xf5,
-	0x90, 0xc3, 0xa3, 0x14, 0x7d, 0x0a, 0x8d, 0xf8, 0x1c, 0xe1, 0x04, 0xc4, 0xfa, 0xcc, 0xf5, 0x6c,
-	0x50, 0x23, 0x03, 0x13, 0x21, 0xea, 0x32, 0xca, 0x57, 0x21, 0x6b, 0x48, 0xb8, 0x0d, 0xb5, 0xa9,
-	0x19, 0x90, 0x71, 0x6a, 0xfe, 0xaf, 0x86, 0x64, 0x23, 0xbc, 0x03, 0x6c, 0x03, 0xf2, 0xa6, 0xee,
-	0x31, 0xdd, 0xc8, 0xd8, 0x76, 0x26, 0xd3, 0xb9, 0x85, 0x59, 0x0d, 0x8e, 0xf0, 0x94, 0x43, 0x7e,
-	0xb8, 0x6c, 0x8f, 0x73, 0xd1, 0x8f, 0xa0, 0x16, 0xeb, 0x28, 0xe5, 0x54, 0x93, 0xe4, 0x70, 0x45,
-	0x4b, 0x45, 0xea, 0xe8, 0xdd, 0xf8, 0x22, 0x57, 0x11, 0xa0, 0x8e, 0xae, 0x73, 0x77, 0x00, 0x16,
-	0x89, 0xac, 0x54, 0x05, 0x09, 0x81, 0xae, 0xce, 0x00, 0x45, 0x3e, 0x17, 0x2e, 0x5d, 0x8f, 0x2f,
-	0xc1, 0x30, 0x63, 0xa0, 0x14, 0x14, 0xe2, 0x1b, 0x52, 0x7e, 0xf9, 0x86, 0xa4, 0xfe, 0x41, 0x82,
-	0x6b, 0x9d, 0xe5, 0xf4, 0xa0, 0xae, 0xdd, 0x83, 0x0a, 0x4d, 0x25, 0x1c, 0x5d, 0xf3, 0x3e, 0x12,
-	0xe1, 0xcb, 0x52, 0xd9, 0xea, 0x31, 0x79, 0x96, 0x9f, 0x91, 0xf6, 0xfa, 0x2e, 0x34, 0x44, 0xc6,
-	0x9b, 0xe5, 0x49, 0x31, 0x9d, 0x27, 0x3f, 0x85, 0x95, 0x2e, 0xf6, 0xa6, 0xee, 0xf9, 0x0c, 0x3b,
-	0x2c, 0x1e, 0x3f, 0x80, 0xa6, 0x15, 0x53, 0x96, 0x33, 0xa6, 0xb1, 0x60, 0xf5, 0x2c, 0xb5, 0x02,
-	0x25, 0x6d, 0xe6, 0x91, 0x73, 0xf5, 0x3f, 0x79, 0x58, 0x5d, 0x1a, 0x41, 0xd0, 0xfd, 0xa5, 0x6b,
-	0x04, 0x4a, 0xcf, 0x0d, 0x4b, 0xf7, 0x88, 0x16, 0x34, 0x78, 0x0e, 0x8a, 0x13, 0x2c, 0xb0, 0x14,
-	0xe4, 0x91, 0xd9, 0xe0, 0x69, 0xb2, 0x08, 0xdf, 0x9a, 0x5e, 0xe7, 0x34, 0x2a, 0xf2, 0x00, 0xae,
-	0xb0, 0x9b, 0x29, 0xb1, 0x4d, 0x82, 0xe3, 0x56, 0x2a, 0x86, 0xf1, 0x9a, 0x20, 0xc0, 0xfb, 0xe9,
-	0x72, 0x12, 0x8a, 0x33, 0x6c, 0x22, 0x09, 0x15, 0x28, 0xfe, 0xca, 0xb5, 0x9d, 0x44, 0x75, 0xa3,
-	0x94, 0xb0, 0xae, 0xf9, 0x38, 0x20, 0xae, 0x8f, 0x93, 0x75, 0x8d, 0x13, 0x13, 0x33, 0x4d, 0x35,
-	0x6b, 0xa6, 0x09, 0xef, 0x5d, 0x34, 0x02, 0x94, 0x5a, 0xfa, 0xde, 0x45, 0x19, 0xb1, 0xbf, 0xe8,
-	0x97, 0xfa, 0x25, 0xbc, 0x73, 0xe1, 0xdc, 0x87, 0x7e, 0x26, 0xcc, 0x8b, 0x2c, 0xd4, 0xd6, 0x2f,
-	0x9e, 0x17, 0x53, 0x53, 0xe2, 0x01, 0x5c, 0xed, 0x32, 0x61, 0xb6, 0x72, 0x04, 0xaa, 0x50, 0x26,
-	0xa5, 0x8c, 0x9e, 0x24, 0xdc, 0x02, 0xf2, 0x19, 0xb7, 0x00, 0xf5, 0x8f, 0x25, 0x28, 0x33, 0x83,
-	0xe8, 0x7d, 0xa8, 0x6b, 0x7c, 0xaf, 0xba, 0x61, 0x24, 0x02, 0x57, 0x64, 0xa0, 0x4d, 0x68, 0x3c,
-	0xc1, 0xa6, 0x4f, 0x0e, 0xb1, 0x49, 0x42, 0xc1, 0xc4, 0x4c, 0x24, 0x72, 0x42, 0x8b, 0x9d, 0x13,
-	0x3c, 0x39, 0xfd, 0x7c, 0xee, 0xfa, 0xf3, 0x59, 0xa2, 0x18, 0x89, 0x0c, 0xf4, 0x00, 0x50, 0xc7,
-	0x9d, 0x79, 0x26, 0x5d, 0x62, 0x78, 0x86, 0xfd, 0x13, 0x6c, 0x5a, 0x89, 0xb1, 0x23, 0x83, 0x8f,
-	0xb6, 0x60, 0x75, 0xe4, 0x98, 0x5e, 0x70, 0xe2, 0x92, 0x30, 0xe3, 0x6c, 0x1c, 0x50, 0x98, 0x23,
-	0x95, 0x65, 0x26, 0x7a, 0x04, 0x57, 0x75, 0xf3, 0x88, 0xf0, 0x92, 0xbc, 0x98, 0xb7, 0x44, 0xe8,
-	0x33, 0x25, 0xd0, 0x87, 0xb0, 0xc2, 0x7d, 0x1f, 0x95, 0xb5, 0x9a, 0xa0, 0xb3, 0xc4, 0x43, 0x77,
-	0xa1, 0xc9, 0x29, 0x34, 0x94, 0xbb, 0x0a, 0x88, 0x6f, 0x31, 0x09, 0x16, 0xfa, 0x0c, 0x14, 0x81,
-	0x10, 0xe2, 0xdf, 0xb5, 0x7d, 0x3c, 0x21, 0xae, 0x7f, 0xae, 0xd4, 0x85, 0x35, 0x2e, 0x94, 0x42,
-	0x3f, 0x86, 0x2b, 0x9c, 0xf7, 0xa2, 0xdd, 0x5f, 0x28, 0x37, 0x04, 0xe5, 0x2c, 0x01, 0xa4, 0x42,
-	0xed, 0xe9, 0x9c, 0xcc, 0xcd, 0xa9, 0xd1, 0x1f, 0x29, 0x4d, 0xf1, 0x15, 0x28, 0x26, 0x87, 0x85,
-	0xbe, 0xd3, 0xde, 0xb5, 0xa7, 0x58, 0x59, 0x11, 0x0b, 0x3d, 0xa3, 0xa1, 0x16, 0x54, 0x3b, 0xd8,
-	0x27, 0x94, 0xbf, 0x2a, 0xf0, 0x63, 0x6a, 0x18, 0x7c, 0xfb, 0xf8, 0x9c, 0x0a, 0xc8, 0x62, 0xf0,
-	0x71, 0x62, 0x88, 0xe0, 0x53, 0xf3, 0xab, 0x9e, 0xf3, 0x14, 0xcf, 0xfa, 0xee, 0xf1, 0xc8, 0xfe,
-	0x1a, 0x2b, 0x6b, 0x22, 0x82, 0x4b, 0xcc, 0xed, 0x3f, 0x97, 0xa1, 0xc2, 0xcf, 0x82, 0xf6, 0x40,
-	0x6e, 0x5b, 0x16, 0xff, 0x1a, 0x61, 0xff, 0x0c, 0xfb, 0xe8, 0x96, 0x90, 0x4f, 0x59, 0xc9, 0xb2,
-	0x2e, 0x0b, 0x02, 0xac, 0x4e, 0xe6, 0xd0, 0x2f, 0xe0, 0x8a, 0x8e, 0x67, 0xee, 0x19, 0x7e, 0x0b,
-	0xb6, 0x76, 0x60, 0x6d, 0x0f, 0x93, 0xa5, 0xf2, 0x9d, 0x12, 0x5c, 0x7f, 0x47, 0xb4, 0x9d, 0x10,
-	0x56, 0x73, 0xe8, 0x05, 0xdc, 0xda, 0xc3, 0x24, 0x7e, 0x90, 0xcc, 0xea, 0x59, 0x69, 0x8b, 0xad,
-	0xcb, 0x9a, 0x96, 0x9a, 0x43, 0xbf, 0x84, 0x1b, 0x3a, 0xf6, 0x5c, 0x9f, 0xb4, 0xcf, 0x4c, 0x7b,
-	0x6a, 0x1e, 0x4e, 0x71, 0x14, 0x4d, 0xe8, 0xa2, 0x1e, 0xbb, 0xfe, 0x46, 0x37, 0x5a, 0xea, 0xc4,
-	0x6b, 0x7b, 0x98, 0x64, 0x74, 0xf4, 0xf4, 0x56, 0x6f, 0x66, 0x98, 0x4c, 0xd8, 0xfa, 0x14, 0xea,
-	0x0b, 0x07, 0x04, 0x19, 0x16, 0xde, 0x4d, 0x0f, 0x58, 0x09, 0x03, 0x3f, 0x87, 0xc6, 0x68, 0x7e,
-	0x38, 0xb3, 0x09, 0x7f, 0xc1, 0x4c, 0x3f, 0x97, 0x25, 0x10, 0x48, 0xbe, 0x06, 0xaa, 0x39, 0xf4,
-	0x19, 0xac, 0x8e, 0x30, 0xd9, 0x71, 0x5d, 0x12, 0x10, 0xdf, 0xf4, 0x3c, 0x6c, 0x5d, 0x82, 0x61,
-	0xca, 0xc2, 0x63, 0x80, 0x11, 0x26, 0xd1, 0xc3, 0x77, 0xb2, 0xcf, 0x52, 0xda, 0xeb, 0xd5, 0x07,
-	0x20, 0x2f, 0x3c, 0xc0, 0xc7, 0xdb, 0x4b, 0xde, 0x67, 0xd6, 0x95, 0x0b, 0xf8, 0x81, 0x9a, 0xdb,
-	0x51, 0xbe, 0x79, 0xb9, 0x21, 0x7d, 0xfb, 0x72, 0x43, 0xfa, 0xc7, 0xcb, 0x0d, 0xe9, 0x77, 0xaf,
-	0x36, 0x72, 0xdf, 0xbe, 0xda, 0xc8, 0xfd, 0xed, 0xd5, 0x46, 0xee, 0xbf, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0xb0, 0x64, 0x33, 0x01, 0xe8, 0x17, 0x00, 0x00,
+func init() { proto.RegisterFile("drummer.proto", fileDescriptor_drummer_217d22f7def87e80) }
+
+var fileDescriptor_drummer_217d22f7def87e80 = []byte{
+	// 2223 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x39, 0x4d, 0x73, 0xdb, 0xc6,
+	0xd9, 0x04, 0xbf, 0xf9, 0x90, 0x92, 0xa8, 0x55, 0x6c, 0xc3, 0x9c, 0x58, 0xa6, 0x91, 0x8f, 0x57,
+	0xf1, 0x24, 0xb2, 0xa3, 0xf1, 0x5b, 0xbb, 0x49, 0xdd, 0x84, 0x22, 0x21, 0x99, 0x15, 0x4d, 0x3a,
+	0x20, 0x64, 0x27, 0xb9, 0x70, 0x20, 0x62, 0x25, 0xa1, 0x02, 0x01, 0x14, 0x58, 0x6a, 0xac, 0x1c,
+	0xfb, 0x03, 0x3a, 0xbd, 0xf7, 0x0f, 0x74, 0x7a, 0xeb, 0xa1, 0xff, 0x21, 0x33, 0x3d, 0x34, 0xc7,
+	0x9e, 0x3a, 0x1d, 0xfb, 0x07, 0x74, 0x3a, 0x9d, 0xc9, 0x25, 0x97, 0xce, 0x2e, 0x16, 0xe4, 0x82,
+	0x00, 0x2d, 0x37, 0x75, 0x2f, 0x1a, 0xe2, 0xf9, 0xda, 0x67, 0x9f, 0xef, 0x67, 0x05, 0x2b, 0xa6,
+	0x3f, 0x9d, 0x4c, 0xb0, 0xbf, 0xed, 0xf9, 0x2e, 0x71, 0x51, 0x85, 0x7f, 0x7a, 0x47, 0x8d, 0x8f,
+	0x4e, 0x2c, 0x72, 0x3a, 0x3d, 0xda, 0x1e, 0xbb, 0x93, 0x3b, 0x27, 0xee, 0x89, 0x7b, 0x87, 0x51,
+	0x1c, 0x4d, 0x8f, 0xd9, 0x17, 0xfb, 0x60, 0xbf, 0x42, 0x4e, 0xe5, 0x3e, 0x94, 0x34, 0x7c, 0x62,
+	0xb9, 0x4e, 0x80, 0xae, 0x42, 0xd1, 0x67, 0x3f, 0x65, 0xa9, 0x99, 0xdb, 0xaa, 0x68, 0xfc, 0x0b,
+	0xbd, 0x05, 0x85, 0xb1, 0x3b, 0x75, 0x88, 0x9c, 0x6d, 0xe6, 0xb6, 0xf2, 0x5a, 0xf8, 0xa1, 0x7c,
+	0x0d, 0x1b, 0x4f, 0x6c, 0x63, 0x8c, 0x27, 0xd8, 0x21, 0x6d, 0xd7, 0x09, 0x88, 0x6f, 0x58, 0x0e,
+	0x41, 0x0d, 0x28, 0xd8, 0xc6, 0x11, 0xb6, 0x65, 0xa9, 0x29, 0x6d, 0x55, 0x76, 0xf3, 0xdf, 0xfe,
+	0xed, 0x66, 0x46, 0x0b, 0x41, 0xe8, 0xff, 0xa0, 0x36, 0x31, 0x9e, 0x8f, 0x7c, 0xec, 0xd9, 0xd6,
+	0xd8, 0x08, 0xe4, 0x6c, 0x53, 0xda, 0xca, 0x73, 0x92, 0xea, 0xc4, 0x78, 0xae, 0x71, 0x84, 0xf2,
+	0x47, 0x09, 0x4a, 0x6d, 0x7b, 0x1a, 0x10, 0xec, 0x23, 0x19, 0x4a, 0x13, 0x3c, 0x39, 0xc2, 0x7e,
+	0xc0, 0xd4, 0xca, 0x6b, 0xd1, 0x27, 0x7a, 0x07, 0x60, 0x1c, 0x12, 0x8d, 0x2c, 0x33, 0x26, 0xac,
+	0xc2, 0xe1, 0x5d, 0x13, 0xdd, 0x84, 0xb2, 0xe1, 0x79, 0x23, 0xc7, 0x98, 0x60, 0x39, 0x27, 0xa8,
+	0x54, 0x32, 0x3c, 0xaf, 0x6f, 0x4c, 0x30, 0xda, 0x83, 0xea, 0x78, 0xa6, 0x7e, 0x20, 0xe7, 0x9b,
+	0xb9, 0xad, 0xea, 0xce, 0xe6, 0xf6, 0xcc, 0xa0, 0xdb, 0x29, 0xb7, 0x8c, 0x74, 0x16, 0x18, 0x95,
+	0x36, 0xac, 0x73, 0x95, 0xdb, 0xae, 0x6d, 0xe3, 0x31, 0xa1, 0xa6, 0xdb, 0x86, 0x32, 0x57, 0x25,
+	0xd4, 0xbe, 0xba, 0x83, 0x04, 0xc9, 0x9c, 0x5e, 0x9b, 0xd1, 0x28, 0x7f, 0x96, 0x20, 0x7b, 0xf0,
+	0x14, 0x5d, 0x85, 0xdc, 0x19, 0xbe, 0x90, 0xa5, 0x66, 0x76, 0xa6, 0x2f, 0x05, 0x50, 0xe3, 0x9e,
+	0x1b, 0xf6, 0x14, 0xcb, 0x59, 0x01, 0x13, 0x82, 0xd0, 0x7b, 0x50, 0xb5, 0x9c, 0x80, 0x18, 0xce,
+	0x18, 0x53, 0x73, 0xe4, 0x04, 0x73, 0x40, 0x84, 0xe8, 0x9a, 0x48, 0x86, 0x3c, 0xb1, 0xc6, 0x67,
+	0x72, 0x5e, 0xc0, 0x33, 0x08, 0xfa, 0x10, 0xd6, 0x5c, 0xdb, 0x1c, 0x89, 0x42, 0x0a, 0x02, 0xd1,
+	0x8a, 0x6b, 0x9b, 0xdd, 0xb9, 0x1c, 0x05, 0x2a, 0xc7, 0x96, 0x63, 0xd8, 0xd6, 0x37, 0xd8, 0x94,
+	0x8b, 0x4d, 0x69, 0xab, 0x1c, 0xd9, 0x7e, 0x06, 0x56, 0xbe, 0x97, 0xa0, 0xd8, 0x3e, 0x35, 0x9c,
+	0x13, 0x8c, 0xee, 0x42, 0x9e, 0x5c, 0x78, 0x98, 0x5d, 0x69, 0x75, 0xe7, 0xaa, 0x68, 0x04, 0x46,
+	0xb0, 0xad, 0x5f, 0x78, 0x78, 0xa6, 0xce, 0x85, 0x87, 0x13, 0xde, 0xcd, 0xa6, 0x79, 0x57, 0x08,
+	0x8e, 0x5c, 0x3c, 0x38, 0x44, 0xbf, 0xe7, 0x5f, 0xc3, 0xef, 0x85, 0x1f, 0xeb, 0x77, 0x04, 0x79,
+	0xaa, 0x3b, 0x02, 0x28, 0xb6, 0x35, 0xb5, 0xa5, 0xab, 0xf5, 0x8c, 0xf2, 0x17, 0x09, 0x56, 0xc3,
+	0x7b, 0x69, 0x38, 0xf0, 0x5c, 0x27, 0xc0, 0xe8, 0x01, 0xe4, 0xc7, 0xae, 0x19, 0x19, 0x60, 0x33,
+	0x61, 0x80, 0x88, 0x70, 0xbb, 0xed, 0x9a, 0x33, 0x43, 0x50, 0x0e, 0xe5, 0xd7, 0x12, 0xe4, 0x29,
+	0x10, 0x15, 0x21, 0x3b, 0x38, 0xa8, 0x67, 0xd0, 0x15, 0x58, 0x6f, 0xf7, 0x0e, 0x87, 0xba, 0xaa,
+	0x8d, 0xfa, 0x03, 0x7d, 0xb4, 0x37, 0x38, 0xec, 0x77, 0xea, 0x12, 0x42, 0xb0, 0xda, 0x1e, 0xf4,
+	0xf7, 0x7a, 0xdd, 0x76, 0x04, 0xcb, 0xa2, 0x75, 0x58, 0x39, 0xec, 0x1f, 0xf4, 0x07, 0xcf, 0xfa,
+	0x23, 0x4d, 0xd5, 0xb5, 0xaf, 0xea, 0x39, 0x0a, 0x8a, 0xb8, 0xd5, 0x2f, 0xbb, 0x43, 0xbd, 0x9e,
+	0x47, 0x75, 0xa8, 0xed, 0x0e, 0x06, 0xfa, 0x50, 0xd7, 0x5a, 0x4f, 0x9e, 0xa8, 0x9d, 0x7a, 0x01,
+	0xad, 0x41, 0x55, 0x53, 0xf7, 0xbb, 0x83, 0xfe, 0x70, 0x34, 0x54, 0xf5, 0x7a, 0x51, 0xf9, 0x67,
+	0x16, 0x8a, 0x87, 0x9e, 0x69, 0x10, 0x8c, 0xee, 0x40, 0x71, 0xcc, 0x54, 0x66, 0x29, 0x5e, 0xdd,
+	0x59, 0x4f, 0xdc, 0x85, 0xab, 0x5f, 0x1c, 0xc7, 0x7d, 0x9f, 0x4d, 0xf8, 0x3e, 0x94, 0x98, 0xf4,
+	0xfd, 0x5d, 0xa8, 0x9c, 0x9d, 0x8f, 0xa6, 0x0c, 0xcb, 0x22, 0xb9, 0xba, 0xb3, 0x22, 0xb0, 0x1d,
+	0x3c, 0xe5, 0xd4, 0xe5, 0xb3, 0x73, 0xae, 0xd4, 0x2e, 0xac, 0x38, 0xae, 0x89, 0x4f, 0xdd, 0x80,
+	0x8c, 0x2c, 0xe7, 0xd8, 0x65, 0x3e, 0xaf, 0xee, 0x5c, 0x13, 0xb8, 0xfa, 0xae, 0x89, 0x1f, 0xb9,
+	0x01, 0xe9, 0x3a, 0xc7, 0x2e, 0xe7, 0xaf, 0x45, 0x3c, 0x14, 0x86, 0xf6, 0xa0, 0xec, 0xe3, 0x5f,
+	0x4d, 0x71, 0xc0, 0xc2, 0x81, 0xb2, 0xbf, 0x9b, 0xc2, 0xae, 0x85, 0x24, 0xf3, 0x24, 0x8f, 0x74,
+	0x89, 0x78, 0x95, 0x3d, 0x1e, 0x11, 0x55, 0x28, 0x71, 0x4b, 0xd7, 0x33, 0xd4, 0x79, 0x07, 0x4f,
+	0xeb, 0x12, 0x2a, 0x43, 0x5e, 0xef, 0xb6, 0x0f, 0x42, 0xdf, 0xf4, 0x07, 0x1d, 0xf5, 0xd1, 0x60,
+	0xa8, 0x8f, 0xba, 0xfd, 0xbd, 0x41, 0x3d, 0x87, 0x6a, 0x50, 0xd6, 0xd4, 0x2f, 0x0e, 0xd5, 0xa1,
+	0x3e, 0xac, 0xe7, 0x95, 0x1f, 0xb2, 0xb0, 0xd2, 0x73, 0xdd, 0xb3, 0xa9, 0xc7, 0xcf, 0x44, 0xf7,
+	0x63, 0x59, 0x74, 0x43, 0xd0, 0x2e, 0x46, 0x97, 0x34, 0xe8, 0xfb, 0xb0, 0x36, 0x4f, 0xa6, 0x91,
+	0x6d, 0x05, 0x51, 0x31, 0x5f, 0x99, 0xe5, 0x52, 0xcf, 0x0a, 0x08, 0x37, 0xbc, 0xcd, 0x84, 0x5d,
+	0x62, 0xf8, 0xf0, 0x44, 0x5a, 0x76, 0xa2, 0x8b, 0xd3, 0x3c, 0x15, 0xcb, 0x0a, 0x44, 0x88, 0xae,
+	0x89, 0x36, 0xa1, 0x64, 0x98, 0xa6, 0x8f, 0x83, 0xd0, 0xb4, 0xf3, 0x6c, 0x0c, 0x81, 0xe8, 0x13,
+	0x28, 0x04, 0xc4, 0x20, 0x01, 0x2b, 0x25, 0xf1, 0x3c, 0xe4, 0x55, 0x72, 0x48, 0x0c, 0x82, 0xf9,
+	0x05, 0xa3, 0xca, 0xc7, 0x58, 0x14, 0xfd, 0x55, 0xf6, 0xbe, 0x02, 0xeb, 0xc3, 0xf6, 0x23, 0xb5,
+	0x73, 0xd8, 0x53, 0xb5, 0x51, 0x7b, 0xd0, 0xd7, 0xd5, 0x2f, 0xf5, 0x45, 0x4b, 0xb3, 0xd4, 0xe1,
+	0x39, 0x31, 0xd4, 0x5b, 0xba, 0x3a, 0xac, 0x17, 0x94, 0xdf, 0x65, 0x61, 0x35, 0xb2, 0x6a, 0x22,
+	0x87, 0xa5, 0x85, 0x1c, 0x8e, 0x13, 0x26, 0x72, 0x38, 0xd6, 0x07, 0xb2, 0x97, 0xf7, 0x01, 0xee,
+	0x07, 0x1f, 0x07, 0x53, 0x9b, 0x5c, 0xe2, 0x07, 0x8d, 0x11, 0xc5, 0x82, 0x37, 0xff, 0x5f, 0x04,
+	0xef, 0x7b, 0xaf, 0x55, 0x6c, 0x94, 0x1f, 0x24, 0x3a, 0x37, 0x84, 0x51, 0xf9, 0x71, 0x2c, 0x2a,
+	0xc5, 0x94, 0x5b, 0x1a, 0x8f, 0xff, 0xeb, 0xe2, 0x7e, 0x1b, 0x56, 0xc7, 0xae, 0x73, 0x3c, 0x0a,
+	0x2b, 0xd0, 0x62, 0x2b, 0xab, 0x51, 0x5c, 0x58, 0xaa, 0xba, 0xa6, 0xf2, 0x71, 0xb2, 0x80, 0xd3,
+	0xdf, 0x1d, 0xb5, 0xa7, 0xea, 0x6a, 0x5d, 0x42, 0x25, 0xc8, 0xb5, 0x3a, 0xb4, 0x98, 0x96, 0x21,
+	0x7f, 0xd0, 0xed, 0xf5, 0xea, 0x39, 0xe5, 0x21, 0x6c, 0xa4, 0x44, 0x65, 0x5a, 0x96, 0x49, 0x29,
+	0x59, 0xa6, 0xfc, 0x26, 0x0f, 0x35, 0x91, 0x7f, 0xc1, 0x1c, 0x52, 0xba, 0x39, 0x6e, 0xc3, 0xaa,
+	0x8d, 0x0d, 0x13, 0xfb, 0x23, 0x5a, 0xb5, 0x16, 0x47, 0x9e, 0x5a, 0x88, 0xa3, 0x8e, 0xee, 0x9a,
+	0xe8, 0x01, 0x14, 0x28, 0x51, 0x68, 0xb8, 0xea, 0x8e, 0xb2, 0x24, 0x9d, 0x58, 0x5c, 0x04, 0xaa,
+	0x43, 0xfc, 0x0b, 0x2d, 0x64, 0x40, 0x8f, 0xa1, 0xa6, 0x3d, 0x69, 0xb7, 0xc2, 0xb4, 0xc4, 0xd1,
+	0x3c, 0xf4, 0xc1, 0x32, 0x01, 0x22, 0x6d, 0x28, 0x27, 0xc6, 0x8e, 0x7e, 0x1a, 0xe6, 0x35, 0x66,
+	0xf6, 0x8f, 0x97, 0xac, 0x98, 0x1c, 0xf6, 0x57, 0x4c, 0x6b, 0x8c, 0xee, 0xc1, 0x06, 0xf5, 0x93,
+	0x75, 0x32, 0xf3, 0xa2, 0x63, 0xe2, 0xe7, 0x72, 0x51, 0xb8, 0xf4, 0x7a, 0x48, 0xc0, 0x5d, 0x49,
+	0xd1, 0x8d, 0x0e, 0xc0, 0xfc, 0x52, 0xf3, 0x41, 0x6a, 0xce, 0xb3, 0x38, 0x48, 0x49, 0x0b, 0x83,
+	0xd4, 0x27, 0xd9, 0x07, 0x52, 0xe3, 0x00, 0xd6, 0x13, 0x37, 0xfb, 0xb1, 0xc2, 0x94, 0x26, 0x14,
+	0x42, 0x37, 0x47, 0x39, 0xb5, 0x06, 0xd5, 0xc3, 0x7e, 0xeb, 0x69, 0xab, 0xdb, 0x6b, 0xed, 0xf6,
+	0xd4, 0xba, 0xa4, 0xfc, 0x23, 0x0b, 0x55, 0x6e, 0x0e, 0xd6, 0x89, 0x5e, 0x2b, 0x1e, 0x6e, 0x40,
+	0x69, 0x1e, 0x08, 0x73, 0x8a, 0xa2, 0x13, 0x86, 0xc0, 0x2d, 0xa8, 0x58, 0xc1, 0x28, 0x8c, 0x0a,
+	0x39, 0x27, 0x0c, 0x68, 0x65, 0x2b, 0xe8, 0x31, 0x28, 0xba, 0x1f, 0x45, 0x49, 0xe8, 0xe4, 0x5b,
+	0x49, 0xe7, 0x50, 0x6d, 0x52, 0x82, 0x64, 0x89, 0x6b, 0x0a, 0xaf, 0x74, 0x0d, 0x7a, 0x17, 0xc0,
+	0x72, 0xc6, 0xee, 0xc4, 0xb3, 0x31, 0xc1, 0xb1, 0x99, 0x51, 0x80, 0xd3, 0x4e, 0xe1, 0x61, 0xc7,
+	0xb4, 0x9c, 0x13, 0xb9, 0x24, 0x90, 0x44, 0xc0, 0x37, 0xe3, 0x60, 0xe5, 0x31, 0x94, 0x7a, 0xee,
+	0xc9, 0x9b, 0x32, 0xb6, 0xf2, 0x08, 0x56, 0xc4, 0x70, 0x0e, 0xd0, 0x7d, 0x80, 0xf1, 0xac, 0xc8,
+	0xf2, 0xd1, 0xff, 0xda, 0xb2, 0xa6, 0x26, 0x90, 0x2a, 0xdf, 0xe7, 0xa0, 0x26, 0x4e, 0x2a, 0x74,
+	0x69, 0xf2, 0x8d, 0x63, 0x32, 0x8a, 0xda, 0xa7, 0xb8, 0x14, 0x54, 0x29, 0x86, 0x47, 0x29, 0xfa,
+	0x0c, 0x6a, 0xb3, 0x7b, 0xd0, 0x09, 0x28, 0xec, 0x33, 0x57, 0xd3, 0x9d, 0x3a, 0x9b, 0x64, 0xe7,
+	0xa0, 0xb4, 0xf2, 0x95, 0x4b, 0x1b, 0x12, 0x6e, 0x41, 0xc5, 0x36, 0x02, 0x32, 0x4a, 0xec, 0x11,
+	0x65, 0x0a, 0xd6, 0xe9, 0x2e, 0xb1, 0x03, 0xc8, 0xb3, 0xdd, 0x13, 0xa6, 0xc8, 0xc8, 0x72, 0xc6,
+	0xf6, 0xd4, 0xc4, 0x61, 0x0d, 0x8e, 0xfc, 0x59, 0xa7, 0x78, 0x7a, 0x6c, 0x97, 0x63, 0xd1, 0xff,
+	0x43, 0x65, 0xc6, 0x23, 0x17, 0x13, 0x4d, 0x92, 0xbb, 0x2b, 0x3a, 0x2a, 0x62, 0x47, 0x6f, 0xcf,
+	0xb6, 0xd6, 0x92, 0xe0, 0x6a, 0x0e, 0xa3, 0x31, 0x37, 0x4f, 0x64, 0xb9, 0x2c, 0x50, 0x08, 0x70,
+	0xf4, 0x29, 0x14, 0xd9, 0x86, 0x1a, 0xc8, 0x15, 0x76, 0xee, 0x3b, 0x4b, 0xc6, 0xc6, 0xed, 0x1e,
+	0xa3, 0x0a, 0x73, 0x81, 0xb3, 0x34, 0x54, 0xa8, 0x0a, 0x60, 0x31, 0x22, 0x2b, 0xff, 0x49, 0x44,
+	0x4e, 0x00, 0x45, 0x47, 0x09, 0x0b, 0xe4, 0xc3, 0x4b, 0xe2, 0x28, 0x65, 0xa8, 0x15, 0x18, 0x66,
+	0xdb, 0x5e, 0x76, 0x71, 0xdb, 0x53, 0x7e, 0x2f, 0xc1, 0x95, 0xf6, 0x62, 0x8a, 0x32, 0xf7, 0xee,
+	0x43, 0x89, 0xa5, 0x33, 0x8e, 0x56, 0xd6, 0x8f, 0xc4, 0x10, 0x4a, 0x63, 0xd9, 0xee, 0x86, 0xf4,
+	0xa1, 0x5d, 0x22, 0xee, 0xc6, 0x1e, 0xd4, 0x44, 0xc4, 0xeb, 0xe5, 0x6a, 0x3e, 0x69, 0x99, 0x4f,
+	0x61, 0xb5, 0x83, 0x3d, 0xdb, 0xbd, 0xa0, 0xcb, 0x18, 0xf3, 0xf9, 0x07, 0xb0, 0x62, 0xce, 0x20,
+	0x8b, 0x59, 0x5b, 0x9b, 0xa3, 0xba, 0xa6, 0x52, 0x82, 0x82, 0x3a, 0xf1, 0xc8, 0x85, 0xf2, 0xaf,
+	0x2c, 0xac, 0x2d, 0x8c, 0x41, 0xe8, 0xee, 0xc2, 0x2a, 0x83, 0x92, 0xb3, 0xcb, 0xc2, 0x2e, 0xd3,
+	0x84, 0x1a, 0xaf, 0x03, 0xe2, 0x14, 0x0d, 0x61, 0x19, 0xe0, 0xd9, 0x51, 0xe3, 0xa9, 0x3a, 0x4f,
+	0xa1, 0x8a, 0x56, 0xe5, 0x30, 0x46, 0x72, 0x0f, 0x36, 0xc2, 0x2d, 0x9b, 0x58, 0x06, 0xc1, 0xb3,
+	0x76, 0x2e, 0xa6, 0xd2, 0xba, 0x40, 0xc0, 0x7b, 0xfa, 0x62, 0x21, 0x10, 0xe7, 0xe8, 0x58, 0x21,
+	0x90, 0x21, 0xff, 0x4b, 0xd7, 0x72, 0x62, 0x15, 0x96, 0x41, 0x68, 0x6d, 0xf5, 0x71, 0x40, 0x5c,
+	0x1f, 0xc7, 0x6b, 0x2b, 0x07, 0xc6, 0xe6, 0xaa, 0x72, 0xda, 0x5c, 0x45, 0x77, 0x3f, 0x16, 0x01,
+	0x72, 0x25, 0xb9, 0xfb, 0x31, 0xc4, 0xcc, 0x5e, 0xec, 0x4b, 0xf9, 0x0a, 0xae, 0x2f, 0x9d, 0x3d,
+	0xd1, 0xcf, 0x84, 0x99, 0x35, 0x0c, 0xb5, 0xc6, 0xf2, 0x99, 0x35, 0x31, 0xa9, 0x1e, 0xc2, 0x5b,
+	0x9d, 0x90, 0x38, 0x3c, 0x39, 0x72, 0xaa, 0x50, 0xaa, 0xa5, 0x94, 0xbe, 0x28, 0x6c, 0x22, 0xd9,
+	0x94, 0x4d, 0x44, 0xf9, 0x43, 0x01, 0x8a, 0xa1, 0x40, 0xf4, 0x3e, 0x54, 0x55, 0xae, 0xab, 0xa6,
+	0xeb, 0xb1, 0xc0, 0x15, 0x11, 0x68, 0x0b, 0x6a, 0x8f, 0xb0, 0xe1, 0x93, 0x23, 0x6c, 0x10, 0x4a,
+	0x18, 0x9b, 0xcb, 0x44, 0x0c, 0x95, 0xd8, 0x3e, 0xc5, 0xe3, 0xb3, 0x2f, 0xa6, 0xae, 0x3f, 0x9d,
+	0xc4, 0x0a, 0xa2, 0x88, 0x40, 0xf7, 0x00, 0xb5, 0xdd, 0x89, 0x67, 0xb0, 0x23, 0x06, 0xe7, 0xd8,
+	0x3f, 0xc5, 0x86, 0x19, 0x1b, 0x7d, 0x52, 0xf0, 0x68, 0x1b, 0xd6, 0x86, 0x8e, 0xe1, 0x05, 0xa7,
+	0x2e, 0xa1, 0x19, 0x67, 0xe1, 0x40, 0x2e, 0x09, 0x2c, 0x8b, 0x48, 0xf4, 0x00, 0xde, 0xd2, 0x8c,
+	0x63, 0xc2, 0xdb, 0xc2, 0x7c, 0xe6, 0x13, 0x5d, 0x9f, 0x4a, 0x81, 0x3e, 0x84, 0x55, 0x6e, 0x7b,
+	0x0e, 0x93, 0x2b, 0x02, 0xcf, 0x02, 0x0e, 0xdd, 0x86, 0x15, 0x0e, 0x61, 0xa1, 0xdc, 0x91, 0x41,
+	0x7c, 0x57, 0x8a, 0xa1, 0xd0, 0xe7, 0x20, 0x0b, 0x00, 0xea, 0xff, 0x8e, 0xe5, 0xe3, 0x31, 0x71,
+	0xfd, 0x0b, 0xb9, 0x2a, 0x9c, 0xb1, 0x94, 0x0a, 0xfd, 0x04, 0x36, 0x38, 0xee, 0x59, 0xab, 0x37,
+	0x67, 0xae, 0x09, 0xcc, 0x69, 0x04, 0xf4, 0x45, 0xeb, 0xf1, 0x94, 0x4c, 0x0d, 0x5b, 0xef, 0x0d,
+	0xe5, 0x15, 0xf1, 0x45, 0x6b, 0x06, 0xa6, 0xcd, 0xa6, 0xdd, 0xda, 0xb3, 0x6c, 0x2c, 0xaf, 0x8a,
+	0xcd, 0x26, 0x84, 0xa1, 0x26, 0x94, 0xdb, 0xd8, 0x27, 0x0c, 0xbf, 0x26, 0xe0, 0x67, 0x50, 0x1a,
+	0x7c, 0x07, 0xf8, 0x82, 0x11, 0xd4, 0xc5, 0xe0, 0xe3, 0x40, 0xea, 0xc1, 0xc7, 0xc6, 0xf3, 0xae,
+	0xf3, 0x18, 0x4f, 0x7a, 0xee, 0xc9, 0xd0, 0xfa, 0x06, 0xcb, 0xeb, 0xa2, 0x07, 0x17, 0x90, 0x3b,
+	0x7f, 0x2a, 0x42, 0x89, 0xdf, 0x05, 0xed, 0x43, 0xbd, 0x65, 0x9a, 0xfc, 0x6b, 0x88, 0xfd, 0x73,
+	0xec, 0xa3, 0x9b, 0x42, 0x3e, 0xa5, 0x25, 0x4b, 0xa3, 0x2e, 0x10, 0x84, 0x75, 0x32, 0x83, 0x7e,
+	0x01, 0x1b, 0x1a, 0x9e, 0xb8, 0xe7, 0xf8, 0x0d, 0xc8, 0xda, 0x85, 0xf5, 0x7d, 0x4c, 0x16, 0xca,
+	0x77, 0x82, 0xb0, 0x71, 0x5d, 0x94, 0x1d, 0x23, 0x56, 0x32, 0xe8, 0x19, 0xdc, 0xdc, 0xc7, 0x64,
+	0xf6, 0xb8, 0x9a, 0xd6, 0xb3, 0x92, 0x12, 0x9b, 0x97, 0x35, 0x2d, 0x25, 0x83, 0xbe, 0x86, 0x6b,
+	0x1a, 0xf6, 0x5c, 0x9f, 0xb4, 0xce, 0x0d, 0xcb, 0x36, 0x8e, 0x6c, 0x1c, 0x45, 0x13, 0x5a, 0xd6,
+	0x63, 0x1b, 0xaf, 0xb5, 0x55, 0x33, 0x23, 0x5e, 0xd9, 0xc7, 0x24, 0xa5, 0xa3, 0x27, 0x55, 0xbd,
+	0x91, 0x22, 0x32, 0x26, 0xeb, 0x33, 0xa8, 0xce, 0x0d, 0x10, 0xa4, 0x48, 0x78, 0x3b, 0x39, 0xe4,
+	0xc5, 0x04, 0xfc, 0x1c, 0x6a, 0xc3, 0xe9, 0xd1, 0xc4, 0x22, 0xfc, 0x35, 0x36, 0xf9, 0x64, 0xd7,
+	0xb8, 0x9e, 0x00, 0x45, 0xaf, 0x19, 0x4a, 0x06, 0x7d, 0x0e, 0x6b, 0x43, 0x4c, 0x76, 0x5d, 0x97,
+	0xd0, 0x67, 0x4f, 0xcf, 0xc3, 0xe6, 0x25, 0x3e, 0x4c, 0x48, 0x78, 0x08, 0x30, 0xc4, 0x24, 0xfa,
+	0x4f, 0x43, 0xbc, 0xcf, 0x32, 0xd8, 0xab, 0xd9, 0xfb, 0x50, 0x9f, 0x5b, 0x80, 0x8f, 0xd8, 0x97,
+	0xbc, 0x11, 0x35, 0xe4, 0x25, 0xf8, 0x40, 0xc9, 0xec, 0xca, 0xdf, 0xbe, 0xd8, 0x94, 0xbe, 0x7b,
+	0xb1, 0x29, 0xfd, 0xfd, 0xc5, 0xa6, 0xf4, 0xdb, 0x97, 0x9b, 0x99, 0xef, 0x5e, 0x6e, 0x66, 0xfe,
+	0xfa, 0x72, 0x33, 0xf3, 0xef, 0x01, 0x00, 0x29, 0x6d, 0xb4, 0x2e, 0x59, 0x19, 0x00, 0x00,
 }
@@ -14,6 +14,11 @@
 
 package drummer
 
+import (
+	"github.com/lni/dragonboat/config"
+	pb "github.com/lni/dragonboat/drummer/drummerpb"
+)
+
 type nodeHostFilter interface {
 	filter([]*nodeHostSpec) []*nodeHostSpec
 }
@@ -73,6 +78,54 @@ func (rf *regionFilter) filter(input []*nodeHostSpec) []*nodeHostSpec {
 	return result
 }
 
+type placementFilter struct {
+	constraints []config.PlacementConstraint
+	clusterSize int
+	placed      []*nodeHostSpec
+}
+
+func newPlacementFilter(constraints []pb.PlacementConstraint,
+	clusterSize int, placed []*nodeHostSpec) *placementFilter {
+	return &placementFilter{
+		constraints: toPlacementConstraints(constraints),
+		clusterSize: clusterSize,
+		placed:      placed,
+	}
+}
+
+func (pf *placementFilter) filter(input []*nodeHostSpec) []*nodeHostSpec {
+	labels := make([]map[string]string, 0)
+	placedAddress := make(map[string]struct{})
+	for _, v := range pf.placed {
+		labels = append(labels, v.Labels)
+		placedAddress[v.Address] = struct{}{}
+	}
+	result := make([]*nodeHostSpec, 0)
+	for _, v := range input {
+		if _, ok := placedAddress[v.Address]; ok {
+			continue
+		}
+		l := append(labels[:len(labels):len(labels)], v.Labels)
+		if config.CheckPlacement(pf.constraints, pf.clusterSize, l) == nil {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func toPlacementConstraints(
+	input []pb.PlacementConstraint) []config.PlacementConstraint {
+	result := make([]config.PlacementConstraint, 0)
+	for _, v := range input {
+		pc := config.PlacementConstraint{
+			Label:       v.Label,
+			MaxReplicas: int(v.MaxReplicas),
+		}
+		result = append(result, pc)
+	}
+	return result
+}
+
 type combinedFilter struct {
 	filters []nodeHostFilter
 }
@@ -18,7 +18,10 @@
 package drummer
 
 import (
+	"fmt"
 	"testing"
+
+	pb "github.com/lni/dragonboat/drummer/drummerpb"
 )
 
 func getTestnodeHostSpecList() []*nodeHostSpec {
@@ -112,3 +115,37 @@ func TestDrummerRegionFilter(t *testing.T) {
 		t.Errorf("got node %s, want node a2", r[0].Address)
 	}
 }
+
+func TestPlacementFilter(t *testing.T) {
+	l := getTestnodeHostSpecList()
+	for idx, v := range l {
+		v.Labels = map[string]string{"zone": fmt.Sprintf("zone-%d", idx%2)}
+	}
+	constraints := []pb.PlacementConstraint{{Label: "zone", MaxReplicas: 1}}
+	pf := newPlacementFilter(constraints, 3, nil)
+	r := pf.filter(l)
+	if len(r) != 4 {
+		t.Errorf("len(r)=%d, want 4", len(r))
+	}
+	pf = newPlacementFilter(constraints, 3, []*nodeHostSpec{l[0]})
+	r = pf.filter(l)
+	if len(r) != 2 {
+		t.Fatalf("len(r)=%d, want 2", len(r))
+	}
+	for _, v := range r {
+		if v.Address != "a2" && v.Address != "a4" {
+			t.Errorf("unexpected node %s", v.Address)
+		}
+	}
+	pf = newPlacementFilter(constraints, 3, []*nodeHostSpec{l[0], l[1]})
+	r = pf.filter(l)
+	if len(r) != 0 {
+		t.Errorf("len(r)=%d, want 0", len(r))
+	}
+	l[3].Labels = nil
+	pf = newPlacementFilter(constraints, 3, []*nodeHostSpec{l[0]})
+	r = pf.filter(l)
+	if len(r) != 1 || r[0].Address != "a2" {
+		t.Errorf("unexpected result %v", r)
+	}
+}
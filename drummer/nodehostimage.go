@@ -23,6 +23,7 @@ type nodeHostSpec struct {
 	Address          string
 	RPCAddress       string
 	Region           string
+	Labels           map[string]string
 	Tick             uint64
 	PersistentLog    []pb.LogInfo
 	Clusters         map[uint64]struct{}
@@ -35,6 +36,12 @@ func (spec *nodeHostSpec) deepCopy() *nodeHostSpec {
 		Region:  spec.Region,
 		Tick:    spec.Tick,
 	}
+	if spec.Labels != nil {
+		ns.Labels = make(map[string]string)
+		for k, v := range spec.Labels {
+			ns.Labels[k] = v
+		}
+	}
 	ns.PersistentLog = make([]pb.LogInfo, 0)
 	for _, v := range spec.PersistentLog {
 		ns.PersistentLog = append(ns.PersistentLog, v)
@@ -132,6 +139,7 @@ func (m *multiNodeHost) toNodeHostSpec(nhi pb.NodeHostInfo) *nodeHostSpec {
 		Address:    nhi.RaftAddress,
 		RPCAddress: nhi.RPCAddress,
 		Region:     nhi.Region,
+		Labels:     nhi.Labels,
 		Tick:       nhi.LastTick,
 	}
 	n.PersistentLog = make([]pb.LogInfo, 0)
@@ -153,6 +161,7 @@ func (m *multiNodeHost) syncNodeHostSpec(nhi pb.NodeHostInfo) {
 		panic("nodeHostSpec not found")
 	}
 	spec.Region = nhi.Region
+	spec.Labels = nhi.Labels
 	spec.Tick = nhi.LastTick
 	if nhi.PlogInfoIncluded {
 		if len(nhi.PlogInfo) == 0 && len(spec.PersistentLog) > 0 {
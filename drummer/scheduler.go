@@ -125,9 +125,7 @@ func (s *scheduler) getLaunchRequests(clusters []*pb.Cluster,
 		selected := make([]*nodeHostSpec, 0)
 		for idx, reg := range regions.Region {
 			cnt := int(regions.Count[idx])
-			randSelector := newRandomRegionSelector(reg,
-				cluster.ClusterId, s.tick, nodeHostTTL, s.randomSrc)
-			regionNodes := randSelector.findSuitableNodeHost(s.nodeHostList, cnt)
+			regionNodes := s.getLaunchNodeHosts(cluster, reg, cnt, selected)
 			if len(regionNodes) != cnt {
 				plog.Errorf("failed to get enough node host for cluster %d region %s",
 					cluster.ClusterId, reg)
@@ -168,6 +166,35 @@ func (s *scheduler) getLaunchRequests(clusters []*pb.Cluster,
 	return result, nil
 }
 
+func (s *scheduler) getLaunchNodeHosts(cluster *pb.Cluster, region string,
+	count int, selected []*nodeHostSpec) []*nodeHostSpec {
+	if len(cluster.Constraints) == 0 {
+		randSelector := newRandomRegionSelector(region,
+			cluster.ClusterId, s.tick, nodeHostTTL, s.randomSrc)
+		return randSelector.findSuitableNodeHost(s.nodeHostList, count)
+	}
+	// nodehosts are picked one by one so each pick is checked against the
+	// placement constraints together with all previous picks
+	placed := make([]*nodeHostSpec, 0)
+	placed = append(placed, selected...)
+	result := make([]*nodeHostSpec, 0)
+	for i := 0; i < count; i++ {
+		pf := newPlacementFilter(cluster.Constraints,
+			len(cluster.Members), placed)
+		randSelector := newRandomRegionSelector(region,
+			cluster.ClusterId, s.tick, nodeHostTTL, s.randomSrc).withFilter(pf)
+		picked := randSelector.findSuitableNodeHost(s.nodeHostList, 1)
+		if len(picked) != 1 {
+			plog.Warningf("no nodehost in region %s satisfies placement "+
+				"constraints of cluster %d", region, cluster.ClusterId)
+			break
+		}
+		placed = append(placed, picked[0])
+		result = append(result, picked[0])
+	}
+	return result
+}
+
 //
 // Repair clusters related
 //
@@ -230,7 +257,7 @@ func (s *scheduler) repairClusters(restored map[uint64]struct{}) ([]pb.NodeHostR
 // for an identified failed node, get an ADD request to add a new node
 func (s *scheduler) getRepairAddRequest(failedNode node,
 	ctr clusterRepair) ([]pb.NodeHostRequest, error) {
-	selected := s.getReplacementNode(failedNode)
+	selected := s.getReplacementNode(failedNode, ctr)
 	if len(selected) != 1 {
 		plog.Warningf("failed to find a replacement node for the failed node %s",
 			failedNode.describe())
@@ -254,7 +281,8 @@ func (s *scheduler) getRepairAddRequest(failedNode node,
 	return []pb.NodeHostRequest{req}, nil
 }
 
-func (s *scheduler) getReplacementNode(failedNode node) []*nodeHostSpec {
+func (s *scheduler) getReplacementNode(failedNode node,
+	ctr clusterRepair) []*nodeHostSpec {
 	// see whether we can find one in the same region
 	var region string
 	nodeHostSpec, ok := s.multiNodeHost.Nodehosts[failedNode.Address]
@@ -264,8 +292,12 @@ func (s *scheduler) getReplacementNode(failedNode node) []*nodeHostSpec {
 	} else {
 		region = nodeHostSpec.Region
 	}
+	pf := s.getRepairPlacementFilter(ctr)
 	regionSelector := newRandomRegionSelector(region,
 		failedNode.ClusterID, s.tick, nodeHostTTL, s.randomSrc)
+	if pf != nil {
+		regionSelector = regionSelector.withFilter(pf)
+	}
 	selected := regionSelector.findSuitableNodeHost(s.nodeHostList, 1)
 	if len(selected) == 1 {
 		return selected
@@ -274,9 +306,34 @@ func (s *scheduler) getReplacementNode(failedNode node) []*nodeHostSpec {
 	// get a random one
 	randSelector := newRandomSelector(failedNode.ClusterID,
 		s.tick, nodeHostTTL, s.randomSrc)
+	if pf != nil {
+		randSelector = randSelector.withFilter(pf)
+	}
 	return randSelector.findSuitableNodeHost(s.nodeHostList, 1)
 }
 
+// the placement filter used for selecting the replacement node, it has nodes
+// other than those failed ones as already placed. nil is returned when there
+// is no placement constraint defined for the cluster.
+func (s *scheduler) getRepairPlacementFilter(
+	ctr clusterRepair) *placementFilter {
+	c := s.getCluster(ctr.clusterID)
+	if c == nil || len(c.Constraints) == 0 {
+		return nil
+	}
+	placed := make([]*nodeHostSpec, 0)
+	nodes := append(ctr.okNodes[:len(ctr.okNodes):len(ctr.okNodes)],
+		ctr.nodesToStart...)
+	for _, n := range nodes {
+		if spec, ok := s.multiNodeHost.Nodehosts[n.Address]; ok {
+			placed = append(placed, spec)
+		} else {
+			placed = append(placed, &nodeHostSpec{Address: n.Address})
+		}
+	}
+	return newPlacementFilter(c.Constraints, len(c.Members), placed)
+}
+
 // start the node which was previously added to the raft cluster
 func (s *scheduler) getRepairCreateRequest(newNode node,
 	ci cluster, appName string) ([]pb.NodeHostRequest, error) {
@@ -464,6 +521,15 @@ func (s *scheduler) getClusterSize(clusterID uint64) int {
 	panic("failed to locate the cluster")
 }
 
+func (s *scheduler) getCluster(clusterID uint64) *pb.Cluster {
+	for _, c := range s.clusters {
+		if c.ClusterId == clusterID {
+			return c
+		}
+	}
+	return nil
+}
+
 func (s *scheduler) getAppName(clusterID uint64) string {
 	for _, c := range s.clusters {
 		if c.ClusterId == clusterID {
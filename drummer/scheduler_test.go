@@ -273,6 +273,50 @@ func TestSchedulerLaunchRequest(t *testing.T) {
 	}
 }
 
+func TestSchedulerLaunchRequestWithPlacementConstraints(t *testing.T) {
+	config := GetClusterConfig()
+	tick := uint64(100)
+	nhList := getNodeHostInfoListWithoutAnyCluster()
+	zones := []string{"zone-1", "zone-1", "zone-2", "zone-3"}
+	for idx := range nhList {
+		nhList[idx].Labels = map[string]string{"zone": zones[idx]}
+	}
+	mnh := getTestMultiNodeHost(nhList)
+	mc := getTestMultiCluster(nhList)
+	clusters := getCluster()
+	clusters[0].Constraints = []pb.PlacementConstraint{{Label: "zone"}}
+	regions := pb.Regions{
+		Region: []string{unknownRegion},
+		Count:  []uint64{3},
+	}
+	for idx := range nhList {
+		mnh.Nodehosts[nhList[idx].RaftAddress].Region = unknownRegion
+	}
+	for i := 0; i < 16; i++ {
+		s := newSchedulerWithContext(nil, config, tick, clusters, mc, mnh)
+		reqs, err := s.getLaunchRequests(clusters, &regions)
+		if err != nil {
+			t.Fatalf("failed to get launch request %v", err)
+		}
+		if len(reqs) != 3 {
+			t.Fatalf("len(reqs)=%d, want 3", len(reqs))
+		}
+		selected := make(map[string]struct{})
+		for _, req := range reqs {
+			z := mnh.Nodehosts[req.RaftAddress].Labels["zone"]
+			if _, ok := selected[z]; ok {
+				t.Fatalf("zone %s selected twice", z)
+			}
+			selected[z] = struct{}{}
+		}
+	}
+	clusters[0].Constraints = []pb.PlacementConstraint{{Label: "rack"}}
+	s := newSchedulerWithContext(nil, config, tick, clusters, mc, mnh)
+	if _, err := s.getLaunchRequests(clusters, &regions); err == nil {
+		t.Errorf("error not returned when constraints can not be satisfied")
+	}
+}
+
 func TestSchedulerAddRequestDuringRepair(t *testing.T) {
 	config := GetClusterConfig()
 	tick := uint64(500)
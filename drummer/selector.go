@@ -51,6 +51,11 @@ func newRandomRegionSelector(region string, clusterID uint64,
 	}
 }
 
+func (rs *randomSelector) withFilter(f nodeHostFilter) *randomSelector {
+	rs.filter = newCombinedFilter(rs.filter, f)
+	return rs
+}
+
 func (rs *randomSelector) findSuitableNodeHost(input []*nodeHostSpec,
 	count int) []*nodeHostSpec {
 	filtered := rs.filter.filter(input)
@@ -385,6 +385,9 @@ func (s *execEngine) execSMs(workerID uint64,
 			s.reportAvailableSnapshot(node, *rec)
 			continue
 		}
+		if node.applyThrottled() {
+			continue
+		}
 		commit, snapshotRequired := node.handleCommit(batch, entries)
 		// batched last applied might updated, give the node work a chance to run
 		s.setNodeReady(node.clusterID)
@@ -0,0 +1,461 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package gateway implements an optional HTTP+JSON gateway that exposes the
+propose, read and membership operations of selected Raft clusters, it allows
+non-Go clients to talk to a Dragonboat based service without a custom RPC
+layer.
+
+The Gateway type is a http.Handler, it serves the following endpoints, all
+request and response bodies are JSON documents and all []byte fields are
+base64 encoded as defined by the encoding/json package.
+
+	POST   /clusters/{clusterID}/propose     {"cmd": ..., "session": ...}
+	POST   /clusters/{clusterID}/read        {"query": ...}
+	GET    /clusters/{clusterID}/membership
+	POST   /clusters/{clusterID}/sessions
+	DELETE /clusters/{clusterID}/sessions    {"session": ...}
+
+The session field of a propose request is optional, a NO-OP client session is
+used when it is omitted. When a client session obtained from the sessions
+endpoint is used, the updated session returned in the propose response should
+be used for the next proposal. Proposals that failed with a timeout can be
+retried using the same session.
+
+When the GatewayAddresses field of Config is set, propose and session requests
+received by a gateway not co-located with the leader are redirected to the
+gateway of the leader using the 307 Temporary Redirect status code.
+*/
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/config"
+	"github.com/lni/dragonboat/logger"
+)
+
+var (
+	plog = logger.GetLogger("gateway")
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+	maxRequestSize = 64 * 1024 * 1024
+	clustersPrefix = "/clusters/"
+)
+
+var (
+	// ErrClusterNotExposed indicates that the requested Raft cluster is not
+	// exposed by the gateway.
+	ErrClusterNotExposed = errors.New("cluster not exposed by the gateway")
+	errInvalidRequest    = errors.New("invalid request")
+	errMethodNotAllowed  = errors.New("method not allowed")
+	errNotFound          = errors.New("not found")
+)
+
+// INodeHost is the interface used by the Gateway for accessing Raft clusters,
+// it adds the session and membership methods of NodeHost to ISyncRequester.
+type INodeHost interface {
+	dragonboat.ISyncRequester
+	GetClusterMembership(ctx context.Context,
+		clusterID uint64) (*dragonboat.Membership, error)
+	GetLeaderID(clusterID uint64) (uint64, bool, error)
+	GetNewSession(ctx context.Context, clusterID uint64) (*client.Session, error)
+	CloseSession(ctx context.Context, session *client.Session) error
+	RaftAddress() string
+	ID() string
+	NodeHostConfig() config.NodeHostConfig
+}
+
+// Config is the configuration of the Gateway.
+type Config struct {
+	// ClusterIDs is the list of Raft clusters exposed by the gateway.
+	ClusterIDs []uint64
+	// Timeout is the timeout applied to each request. The default value of 5
+	// seconds is used when it is not set.
+	Timeout time.Duration
+	// GatewayAddresses is a map of NodeHost RaftAddress values to the base URL
+	// of the gateway co-located with the NodeHost, e.g. http://host1:8080. It
+	// is used for redirecting requests to the gateway of the leader. When the
+	// AddressByNodeHostID field of the NodeHostConfig is set, keys are NodeHost
+	// IDs rather than RaftAddress values. Requests are never redirected when
+	// GatewayAddresses is empty.
+	GatewayAddresses map[string]string
+}
+
+// ProposeRequest is the request body of the propose endpoint.
+type ProposeRequest struct {
+	Cmd     []byte          `json:"cmd"`
+	Session *client.Session `json:"session,omitempty"`
+}
+
+// ProposeResponse is the response body of the propose endpoint.
+type ProposeResponse struct {
+	Result  uint64          `json:"result"`
+	Session *client.Session `json:"session,omitempty"`
+}
+
+// ReadRequest is the request body of the read endpoint.
+type ReadRequest struct {
+	Query []byte `json:"query"`
+}
+
+// ReadResponse is the response body of the read endpoint.
+type ReadResponse struct {
+	Data []byte `json:"data"`
+}
+
+// SessionRequest is the request body used for closing a client session.
+type SessionRequest struct {
+	Session *client.Session `json:"session"`
+}
+
+// SessionResponse is the response body returned when a client session is
+// created.
+type SessionResponse struct {
+	Session *client.Session `json:"session"`
+}
+
+// MembershipResponse is the response body of the membership endpoint.
+type MembershipResponse struct {
+	ConfigChangeID uint64            `json:"config_change_id"`
+	Nodes          map[uint64]string `json:"nodes"`
+	Observers      map[uint64]string `json:"observers"`
+	Removed        []uint64          `json:"removed"`
+}
+
+// ErrorResponse is the response body returned when the request failed.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Gateway is a http.Handler that serves requests for the exposed Raft
+// clusters.
+type Gateway struct {
+	nh       INodeHost
+	timeout  time.Duration
+	clusters map[uint64]struct{}
+	gateways map[string]string
+	mu       sync.Mutex
+	// addrs caches the address of nodes of each exposed Raft cluster, it
+	// never goes stale as NodeIDs are never reused.
+	addrs map[uint64]map[uint64]string
+	// byID indicates whether node addresses are NodeHost IDs
+	byID bool
+}
+
+// NewGateway returns a new Gateway instance.
+func NewGateway(nh INodeHost, cfg Config) *Gateway {
+	g := &Gateway{
+		nh:       nh,
+		timeout:  cfg.Timeout,
+		clusters: make(map[uint64]struct{}),
+		gateways: make(map[string]string),
+		addrs:    make(map[uint64]map[uint64]string),
+		byID:     nh.NodeHostConfig().AddressByNodeHostID,
+	}
+	if g.timeout == 0 {
+		g.timeout = defaultTimeout
+	}
+	for _, clusterID := range cfg.ClusterIDs {
+		g.clusters[clusterID] = struct{}{}
+	}
+	for addr, url := range cfg.GatewayAddresses {
+		g.gateways[addr] = strings.TrimSuffix(url, "/")
+	}
+	return g
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	clusterID, op, err := parsePath(r.URL.Path)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if _, ok := g.clusters[clusterID]; !ok {
+		writeError(w, ErrClusterNotExposed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), g.timeout)
+	defer cancel()
+	switch {
+	case op == "propose" && r.Method == http.MethodPost:
+		if !g.redirect(w, r, clusterID) {
+			g.propose(ctx, w, r, clusterID)
+		}
+	case op == "read" && r.Method == http.MethodPost:
+		g.read(ctx, w, r, clusterID)
+	case op == "membership" && r.Method == http.MethodGet:
+		g.membership(ctx, w, clusterID)
+	case op == "sessions" && r.Method == http.MethodPost:
+		if !g.redirect(w, r, clusterID) {
+			g.newSession(ctx, w, clusterID)
+		}
+	case op == "sessions" && r.Method == http.MethodDelete:
+		if !g.redirect(w, r, clusterID) {
+			g.closeSession(ctx, w, r, clusterID)
+		}
+	case op == "propose" || op == "read" ||
+		op == "membership" || op == "sessions":
+		writeError(w, errMethodNotAllowed)
+	default:
+		writeError(w, errNotFound)
+	}
+}
+
+func (g *Gateway) propose(ctx context.Context,
+	w http.ResponseWriter, r *http.Request, clusterID uint64) {
+	var req ProposeRequest
+	if err := readRequest(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	session := req.Session
+	if session == nil {
+		session = g.nh.GetNoOPSession(clusterID)
+	} else if !validForProposal(session, clusterID) {
+		writeError(w, dragonboat.ErrInvalidSession)
+		return
+	}
+	result, err := g.nh.SyncPropose(ctx, session, req.Cmd)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp := ProposeResponse{Result: result}
+	if req.Session != nil {
+		session.ProposalCompleted()
+		resp.Session = session
+	}
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) read(ctx context.Context,
+	w http.ResponseWriter, r *http.Request, clusterID uint64) {
+	var req ReadRequest
+	if err := readRequest(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	data, err := g.nh.SyncRead(ctx, clusterID, req.Query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, http.StatusOK, ReadResponse{Data: data})
+}
+
+func (g *Gateway) membership(ctx context.Context,
+	w http.ResponseWriter, clusterID uint64) {
+	m, err := g.nh.GetClusterMembership(ctx, clusterID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp := MembershipResponse{
+		ConfigChangeID: m.ConfigChangeID,
+		Nodes:          m.Nodes,
+		Observers:      m.Observers,
+		Removed:        make([]uint64, 0, len(m.Removed)),
+	}
+	for nodeID := range m.Removed {
+		resp.Removed = append(resp.Removed, nodeID)
+	}
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) newSession(ctx context.Context,
+	w http.ResponseWriter, clusterID uint64) {
+	session, err := g.nh.GetNewSession(ctx, clusterID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, http.StatusOK, SessionResponse{Session: session})
+}
+
+func (g *Gateway) closeSession(ctx context.Context,
+	w http.ResponseWriter, r *http.Request, clusterID uint64) {
+	var req SessionRequest
+	if err := readRequest(w, r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.Session == nil || !validForProposal(req.Session, clusterID) {
+		writeError(w, dragonboat.ErrInvalidSession)
+		return
+	}
+	if err := g.nh.CloseSession(ctx, req.Session); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, http.StatusOK, struct{}{})
+}
+
+// redirect redirects the request to the gateway of the leader when the leader
+// is known and it is not co-located with this gateway. It returns a boolean
+// value indicating whether the request has been redirected.
+func (g *Gateway) redirect(w http.ResponseWriter,
+	r *http.Request, clusterID uint64) bool {
+	if len(g.gateways) == 0 {
+		return false
+	}
+	url, ok := g.getLeaderGateway(r.Context(), clusterID)
+	if !ok {
+		return false
+	}
+	http.Redirect(w, r, url+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	return true
+}
+
+func (g *Gateway) getLeaderGateway(ctx context.Context,
+	clusterID uint64) (string, bool) {
+	leaderID, valid, err := g.nh.GetLeaderID(clusterID)
+	if err != nil || !valid {
+		return "", false
+	}
+	addr, ok := g.getNodeAddress(clusterID, leaderID)
+	if !ok {
+		g.updateNodeAddresses(ctx, clusterID)
+		if addr, ok = g.getNodeAddress(clusterID, leaderID); !ok {
+			return "", false
+		}
+	}
+	if addr == g.localAddress() {
+		return "", false
+	}
+	url, ok := g.gateways[addr]
+	return url, ok
+}
+
+// localAddress returns the address of the local NodeHost as recorded in the
+// membership of Raft clusters.
+func (g *Gateway) localAddress() string {
+	if g.byID {
+		return g.nh.ID()
+	}
+	return g.nh.RaftAddress()
+}
+
+func (g *Gateway) getNodeAddress(clusterID uint64,
+	nodeID uint64) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	addr, ok := g.addrs[clusterID][nodeID]
+	return addr, ok
+}
+
+// updateNodeAddresses updates the cached node addresses of the specified
+// Raft cluster using its latest membership.
+func (g *Gateway) updateNodeAddresses(ctx context.Context, clusterID uint64) {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+	m, err := g.nh.GetClusterMembership(ctx, clusterID)
+	if err != nil {
+		plog.Warningf("failed to get membership of cluster %d, %v",
+			clusterID, err)
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	addrs, ok := g.addrs[clusterID]
+	if !ok {
+		addrs = make(map[uint64]string)
+		g.addrs[clusterID] = addrs
+	}
+	for nodeID, addr := range m.Nodes {
+		addrs[nodeID] = addr
+	}
+}
+
+// validForProposal checks whether the session provided by the client is a
+// regular client session ready to be used for making proposals. Client
+// provided sessions are validated here as methods of the client.Session type
+// panic on invalid values.
+func validForProposal(session *client.Session, clusterID uint64) bool {
+	if session.ClientID == client.NotSessionManagedClientID ||
+		session.SeriesID == client.NoOPSeriesID ||
+		session.SeriesID != session.RespondedTo+1 {
+		return false
+	}
+	return session.ValidForProposal(clusterID)
+}
+
+func parsePath(path string) (uint64, string, error) {
+	if !strings.HasPrefix(path, clustersPrefix) {
+		return 0, "", errNotFound
+	}
+	parts := strings.Split(strings.TrimPrefix(path, clustersPrefix), "/")
+	if len(parts) != 2 {
+		return 0, "", errNotFound
+	}
+	clusterID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", errNotFound
+	}
+	return clusterID, parts[1], nil
+}
+
+func readRequest(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	d := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err := d.Decode(v); err != nil {
+		return errInvalidRequest
+	}
+	return nil
+}
+
+func writeResponse(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		plog.Warningf("failed to write response, %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeResponse(w, getStatusCode(err), ErrorResponse{Error: err.Error()})
+}
+
+func getStatusCode(err error) int {
+	switch err {
+	case errInvalidRequest:
+		return http.StatusBadRequest
+	case errNotFound, ErrClusterNotExposed, dragonboat.ErrClusterNotFound:
+		return http.StatusNotFound
+	case errMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	case dragonboat.ErrInvalidSession, dragonboat.ErrRejected:
+		return http.StatusConflict
+	case dragonboat.ErrPayloadTooBig:
+		return http.StatusRequestEntityTooLarge
+	case dragonboat.ErrTimeout:
+		return http.StatusGatewayTimeout
+	}
+	if dragonboat.IsTempError(err) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
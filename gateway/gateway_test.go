@@ -0,0 +1,304 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/config"
+	"github.com/lni/dragonboat/internal/utils/random"
+)
+
+type testNodeHost struct {
+	byID            bool
+	leaderID        uint64
+	membershipReads int
+	proposals       [][]byte
+	sessions        map[uint64]bool
+}
+
+func newTestNodeHost() *testNodeHost {
+	return &testNodeHost{leaderID: 1, sessions: make(map[uint64]bool)}
+}
+
+func (nh *testNodeHost) SyncPropose(ctx context.Context,
+	session *client.Session, cmd []byte) (uint64, error) {
+	if session.SeriesID != client.NoOPSeriesID &&
+		!nh.sessions[session.ClientID] {
+		return 0, dragonboat.ErrInvalidSession
+	}
+	nh.proposals = append(nh.proposals, cmd)
+	return uint64(len(cmd)), nil
+}
+
+func (nh *testNodeHost) SyncRead(ctx context.Context,
+	clusterID uint64, query []byte) ([]byte, error) {
+	return append([]byte("result-"), query...), nil
+}
+
+func (nh *testNodeHost) GetClusterMembership(ctx context.Context,
+	clusterID uint64) (*dragonboat.Membership, error) {
+	nh.membershipReads++
+	nodes := map[uint64]string{1: "a1:1", 2: "a2:2"}
+	if nh.byID {
+		nodes = map[uint64]string{1: "id1", 2: "id2"}
+	}
+	return &dragonboat.Membership{
+		ConfigChangeID: 10,
+		Nodes:          nodes,
+		Observers:      map[uint64]string{},
+		Removed:        map[uint64]struct{}{3: {}},
+	}, nil
+}
+
+func (nh *testNodeHost) GetLeaderID(clusterID uint64) (uint64, bool, error) {
+	return nh.leaderID, true, nil
+}
+
+func (nh *testNodeHost) GetNoOPSession(clusterID uint64) *client.Session {
+	return client.NewNoOPSession(clusterID, random.LockGuardedRand)
+}
+
+func (nh *testNodeHost) GetNewSession(ctx context.Context,
+	clusterID uint64) (*client.Session, error) {
+	cs := client.NewSession(clusterID, random.LockGuardedRand)
+	cs.PrepareForPropose()
+	nh.sessions[cs.ClientID] = true
+	return cs, nil
+}
+
+func (nh *testNodeHost) CloseSession(ctx context.Context,
+	session *client.Session) error {
+	if !nh.sessions[session.ClientID] {
+		return dragonboat.ErrRejected
+	}
+	delete(nh.sessions, session.ClientID)
+	return nil
+}
+
+func (nh *testNodeHost) RaftAddress() string {
+	return "a1:1"
+}
+
+func (nh *testNodeHost) ID() string {
+	return "id1"
+}
+
+func (nh *testNodeHost) NodeHostConfig() config.NodeHostConfig {
+	return config.NodeHostConfig{AddressByNodeHostID: nh.byID}
+}
+
+func doRequest(t *testing.T, h http.Handler,
+	method string, path string, req interface{}, resp interface{}) int {
+	var body bytes.Buffer
+	if req != nil {
+		if err := json.NewEncoder(&body).Encode(req); err != nil {
+			t.Fatalf("failed to encode request %v", err)
+		}
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, &body))
+	if resp != nil && w.Code == http.StatusOK {
+		if err := json.NewDecoder(w.Body).Decode(resp); err != nil {
+			t.Fatalf("failed to decode response %v", err)
+		}
+	}
+	return w.Code
+}
+
+func TestProposeAndReadWithNoOPSession(t *testing.T) {
+	nh := newTestNodeHost()
+	g := NewGateway(nh, Config{ClusterIDs: []uint64{1}})
+	var presp ProposeResponse
+	code := doRequest(t, g, http.MethodPost, "/clusters/1/propose",
+		ProposeRequest{Cmd: []byte("test-data")}, &presp)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected code %d", code)
+	}
+	if presp.Result != 9 || presp.Session != nil {
+		t.Errorf("unexpected response %+v", presp)
+	}
+	var rresp ReadResponse
+	code = doRequest(t, g, http.MethodPost, "/clusters/1/read",
+		ReadRequest{Query: []byte("q")}, &rresp)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected code %d", code)
+	}
+	if string(rresp.Data) != "result-q" {
+		t.Errorf("unexpected data %s", rresp.Data)
+	}
+}
+
+func TestProposeWithClientSession(t *testing.T) {
+	nh := newTestNodeHost()
+	g := NewGateway(nh, Config{ClusterIDs: []uint64{1}})
+	var sresp SessionResponse
+	code := doRequest(t, g, http.MethodPost, "/clusters/1/sessions", nil, &sresp)
+	if code != http.StatusOK || sresp.Session == nil {
+		t.Fatalf("failed to get session, code %d", code)
+	}
+	session := sresp.Session
+	for i := uint64(0); i < 3; i++ {
+		var presp ProposeResponse
+		code := doRequest(t, g, http.MethodPost, "/clusters/1/propose",
+			ProposeRequest{Cmd: []byte("test-data"), Session: session}, &presp)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected code %d", code)
+		}
+		if presp.Session.SeriesID != session.SeriesID+1 ||
+			presp.Session.RespondedTo != session.SeriesID {
+			t.Errorf("session not updated, %+v", presp.Session)
+		}
+		session = presp.Session
+	}
+	// reusing an outdated session is rejected by the gateway
+	stale := *session
+	stale.RespondedTo = stale.SeriesID
+	code = doRequest(t, g, http.MethodPost, "/clusters/1/propose",
+		ProposeRequest{Cmd: []byte("test-data"), Session: &stale}, nil)
+	if code != http.StatusConflict {
+		t.Errorf("unexpected code %d", code)
+	}
+	code = doRequest(t, g, http.MethodDelete, "/clusters/1/sessions",
+		SessionRequest{Session: session}, nil)
+	if code != http.StatusOK {
+		t.Errorf("failed to close session, code %d", code)
+	}
+	code = doRequest(t, g, http.MethodPost, "/clusters/1/propose",
+		ProposeRequest{Cmd: []byte("test-data"), Session: session}, nil)
+	if code != http.StatusConflict {
+		t.Errorf("unexpected code %d", code)
+	}
+}
+
+func TestMembershipCanBeQueried(t *testing.T) {
+	g := NewGateway(newTestNodeHost(), Config{ClusterIDs: []uint64{1}})
+	var resp MembershipResponse
+	code := doRequest(t, g, http.MethodGet, "/clusters/1/membership", nil, &resp)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected code %d", code)
+	}
+	if resp.ConfigChangeID != 10 || len(resp.Nodes) != 2 ||
+		resp.Nodes[2] != "a2:2" || len(resp.Removed) != 1 ||
+		resp.Removed[0] != 3 {
+		t.Errorf("unexpected membership %+v", resp)
+	}
+}
+
+func TestInvalidRequestsAreRejected(t *testing.T) {
+	g := NewGateway(newTestNodeHost(), Config{ClusterIDs: []uint64{1}})
+	tests := []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{http.MethodPost, "/clusters/2/propose", http.StatusNotFound},
+		{http.MethodPost, "/clusters/x/propose", http.StatusNotFound},
+		{http.MethodPost, "/clusters/1/unknown", http.StatusNotFound},
+		{http.MethodPost, "/nodes/1/propose", http.StatusNotFound},
+		{http.MethodGet, "/clusters/1/propose", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/clusters/1/propose", http.StatusBadRequest},
+	}
+	for idx, tt := range tests {
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path,
+			bytes.NewBufferString("{")))
+		if w.Code != tt.code {
+			t.Errorf("%d, code %d, want %d", idx, w.Code, tt.code)
+		}
+	}
+}
+
+func TestRequestsAreRedirectedToLeaderGateway(t *testing.T) {
+	nh := newTestNodeHost()
+	cfg := Config{
+		ClusterIDs: []uint64{1},
+		GatewayAddresses: map[string]string{
+			"a1:1": "http://gw1:8080",
+			"a2:2": "http://gw2:8080/",
+		},
+	}
+	g := NewGateway(nh, cfg)
+	code := doRequest(t, g, http.MethodPost, "/clusters/1/propose",
+		ProposeRequest{Cmd: []byte("test-data")}, nil)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected code %d", code)
+	}
+	nh.leaderID = 2
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost,
+		"/clusters/1/propose", bytes.NewBufferString("{}")))
+	if w.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("unexpected code %d", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "http://gw2:8080/clusters/1/propose" {
+		t.Errorf("unexpected location %s", loc)
+	}
+	// node addresses are cached
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost,
+		"/clusters/1/propose", bytes.NewBufferString("{}")))
+	if w.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("unexpected code %d", w.Code)
+	}
+	if nh.membershipReads != 1 {
+		t.Errorf("membership read %d times, want 1", nh.membershipReads)
+	}
+	// reads are always served locally
+	code = doRequest(t, g, http.MethodPost, "/clusters/1/read",
+		ReadRequest{Query: []byte("q")}, nil)
+	if code != http.StatusOK {
+		t.Errorf("unexpected code %d", code)
+	}
+}
+
+func TestRequestsAreRedirectedByNodeHostID(t *testing.T) {
+	nh := newTestNodeHost()
+	nh.byID = true
+	cfg := Config{
+		ClusterIDs: []uint64{1},
+		GatewayAddresses: map[string]string{
+			"id1": "http://gw1:8080",
+			"id2": "http://gw2:8080",
+		},
+	}
+	g := NewGateway(nh, cfg)
+	code := doRequest(t, g, http.MethodPost, "/clusters/1/propose",
+		ProposeRequest{Cmd: []byte("test-data")}, nil)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected code %d", code)
+	}
+	nh.leaderID = 2
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost,
+		"/clusters/1/propose", bytes.NewBufferString("{}")))
+	if w.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("unexpected code %d", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "http://gw2:8080/clusters/1/propose" {
+		t.Errorf("unexpected location %s", loc)
+	}
+}
+
+func TestNodeHostImplementsINodeHost(t *testing.T) {
+	var _ INodeHost = (*dragonboat.NodeHost)(nil)
+}
@@ -0,0 +1,251 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dragonboat
+
+import (
+	"os"
+	"sync"
+
+	"github.com/lni/dragonboat/config"
+	"github.com/lni/dragonboat/internal/utils/logutil"
+	"github.com/lni/dragonboat/raftio"
+)
+
+// ghostReplicas tracks ghost replicas, i.e. replicas no longer members of
+// their Raft clusters, and stopped nodes that might still be accessed by the
+// execution engine.
+type ghostReplicas struct {
+	mu sync.Mutex
+	// stopped nodes not yet offloaded from all execution engine workers
+	stopped map[raftio.NodeInfo]*node
+	// local ghost replicas with data to be removed
+	pending map[raftio.NodeInfo]struct{}
+	// ghost replicas already reported
+	reported map[raftio.NodeInfo]struct{}
+	// ghost replicas to be passed to the GhostReplicaHandler
+	events []config.GhostReplica
+	// nodes with their data being removed
+	removing map[raftio.NodeInfo]struct{}
+}
+
+func newGhostReplicas() *ghostReplicas {
+	return &ghostReplicas{
+		stopped:  make(map[raftio.NodeInfo]*node),
+		pending:  make(map[raftio.NodeInfo]struct{}),
+		reported: make(map[raftio.NodeInfo]struct{}),
+		removing: make(map[raftio.NodeInfo]struct{}),
+	}
+}
+
+func (g *ghostReplicas) addStopped(n *node) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.stopped[raftio.GetNodeInfo(n.clusterID, n.nodeID)] = n
+}
+
+// offloaded returns a boolean value indicating whether the specified node is
+// no longer accessed by the execution engine.
+func (g *ghostReplicas) offloaded(clusterID uint64, nodeID uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ni := raftio.GetNodeInfo(clusterID, nodeID)
+	n, ok := g.stopped[ni]
+	if !ok {
+		return true
+	}
+	if !n.offloaded() {
+		return false
+	}
+	delete(g.stopped, ni)
+	return true
+}
+
+// removeOffloaded stops tracking stopped nodes that are no longer accessed by
+// the execution engine so they can be garbage collected.
+func (g *ghostReplicas) removeOffloaded() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for ni, n := range g.stopped {
+		if n.offloaded() {
+			delete(g.stopped, ni)
+		}
+	}
+}
+
+// report records the specified ghost replica, it returns a boolean value
+// indicating whether it is the first time the ghost replica is reported.
+func (g *ghostReplicas) report(gr config.GhostReplica) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ni := raftio.GetNodeInfo(gr.ClusterID, gr.NodeID)
+	if _, ok := g.reported[ni]; ok {
+		return false
+	}
+	g.reported[ni] = struct{}{}
+	g.events = append(g.events, gr)
+	return true
+}
+
+func (g *ghostReplicas) getEvents() []config.GhostReplica {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	events := g.events
+	g.events = nil
+	return events
+}
+
+func (g *ghostReplicas) addPending(clusterID uint64, nodeID uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pending[raftio.GetNodeInfo(clusterID, nodeID)] = struct{}{}
+}
+
+func (g *ghostReplicas) getPending() []raftio.NodeInfo {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	result := make([]raftio.NodeInfo, 0, len(g.pending))
+	for ni := range g.pending {
+		result = append(result, ni)
+	}
+	return result
+}
+
+func (g *ghostReplicas) removePending(ni raftio.NodeInfo) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.pending, ni)
+}
+
+// startRemoving marks the data of the specified node as being removed, it
+// returns a boolean value indicating whether it is not already being removed.
+func (g *ghostReplicas) startRemoving(clusterID uint64, nodeID uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ni := raftio.GetNodeInfo(clusterID, nodeID)
+	if _, ok := g.removing[ni]; ok {
+		return false
+	}
+	g.removing[ni] = struct{}{}
+	return true
+}
+
+func (g *ghostReplicas) removed(clusterID uint64, nodeID uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.removing, raftio.GetNodeInfo(clusterID, nodeID))
+}
+
+func (g *ghostReplicas) isRemoving(clusterID uint64, nodeID uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.removing[raftio.GetNodeInfo(clusterID, nodeID)]
+	return ok
+}
+
+// RemoveData removes all data associated with the specified node from the
+// NodeHost, including its Raft log, snapshots and bootstrap info. The node
+// must have been stopped, ErrClusterNotStopped is returned when the node is
+// still running or when it is still being stopped, in which case RemoveData
+// can be retried later. StartCluster fails with ErrRemovingNodeData while the
+// data of the node is being removed. ErrRemoveDataNotSupported is returned
+// when the Log DB does not implement the raftio.INodeDataRemover interface.
+//
+// RemoveData is typically used to clean up ghost replicas, i.e. replicas that
+// are no longer members of their Raft clusters. A replica removed while its
+// NodeHost was down has no local record of its removal, it is reported as a
+// remote ghost replica by the NodeHost instances of the remaining members,
+// the application is expected to call RemoveData on the NodeHost managing
+// it. Removed data can not be recovered.
+func (nh *NodeHost) RemoveData(clusterID uint64, nodeID uint64) error {
+	remover, ok := nh.logdb.(raftio.INodeDataRemover)
+	if !ok {
+		return ErrRemoveDataNotSupported
+	}
+	if err := nh.startRemovingData(clusterID, nodeID); err != nil {
+		return err
+	}
+	defer nh.ghosts.removed(clusterID, nodeID)
+	plog.Infof("removing data of %s", logutil.DescribeNode(clusterID, nodeID))
+	if err := remover.RemoveNodeData(clusterID, nodeID); err != nil {
+		return err
+	}
+	return os.RemoveAll(nh.serverCtx.GetSnapshotDir(nh.deploymentID,
+		clusterID, nodeID))
+}
+
+// startRemovingData checks whether the data of the specified node can be
+// removed. clusterMu is not held when removing the data, the node is marked
+// as being removed instead so it can not be started again in the meantime.
+func (nh *NodeHost) startRemovingData(clusterID uint64, nodeID uint64) error {
+	nh.clusterMu.Lock()
+	defer nh.clusterMu.Unlock()
+	if v, ok := nh.getClusterNotLocked(clusterID); ok && v.nodeID == nodeID {
+		return ErrClusterNotStopped
+	}
+	if !nh.ghosts.offloaded(clusterID, nodeID) {
+		return ErrClusterNotStopped
+	}
+	if !nh.ghosts.startRemoving(clusterID, nodeID) {
+		return ErrRemovingNodeData
+	}
+	return nil
+}
+
+func (nh *NodeHost) onLocalGhostReplica(n *node) {
+	plog.Warningf("%s is a ghost replica", n.describe())
+	nh.ghosts.report(config.GhostReplica{
+		ClusterID: n.clusterID,
+		NodeID:    n.nodeID,
+		Address:   nh.RaftAddress(),
+		Local:     true,
+	})
+	if nh.nhConfig.RemoveGhostReplica {
+		nh.ghosts.addPending(n.clusterID, n.nodeID)
+	}
+}
+
+func (nh *NodeHost) onRemoteGhostReplica(clusterID uint64,
+	nodeID uint64, addr string) {
+	if nh.ghosts.report(config.GhostReplica{
+		ClusterID: clusterID,
+		NodeID:    nodeID,
+		Address:   addr,
+	}) {
+		plog.Warningf("%s on %s is a ghost replica, its messages are dropped",
+			logutil.DescribeNode(clusterID, nodeID), addr)
+	}
+}
+
+// handleGhostReplicas passes detected ghost replicas to the
+// GhostReplicaHandler and removes data of pending local ghost replicas.
+func (nh *NodeHost) handleGhostReplicas() {
+	nh.ghosts.removeOffloaded()
+	for _, gr := range nh.ghosts.getEvents() {
+		if nh.nhConfig.GhostReplicaHandler != nil {
+			nh.nhConfig.GhostReplicaHandler(gr)
+		}
+	}
+	for _, ni := range nh.ghosts.getPending() {
+		err := nh.RemoveData(ni.ClusterID, ni.NodeID)
+		if err == ErrClusterNotStopped || err == ErrRemovingNodeData {
+			continue
+		}
+		if err != nil {
+			plog.Errorf("failed to remove data of %s, %v",
+				logutil.DescribeNode(ni.ClusterID, ni.NodeID), err)
+		}
+		nh.ghosts.removePending(ni)
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !dragonboat_slowtest && !dragonboat_errorinjectiontest
+// +build !dragonboat_slowtest,!dragonboat_errorinjectiontest
+
+package dragonboat
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/lni/dragonboat/config"
+	"github.com/lni/dragonboat/internal/utils/leaktest"
+	pb "github.com/lni/dragonboat/raftpb"
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+func ghostReplicaTest(t *testing.T, remove bool,
+	tf func(t *testing.T, nh *NodeHost, gc chan config.GhostReplica)) {
+	defer leaktest.AfterTest(t)()
+	os.RemoveAll(singleNodeHostTestDir)
+	defer os.RemoveAll(singleNodeHostTestDir)
+	gc := make(chan config.GhostReplica, 16)
+	nhc := config.NodeHostConfig{
+		WALDir:         singleNodeHostTestDir,
+		NodeHostDir:    singleNodeHostTestDir,
+		RTTMillisecond: 50,
+		RaftAddress:    singleNodeHostTestAddr,
+		GhostReplicaHandler: func(gr config.GhostReplica) {
+			gc <- gr
+		},
+		RemoveGhostReplica: remove,
+	}
+	rc := config.Config{
+		NodeID:       1,
+		ClusterID:    2,
+		ElectionRTT:  5,
+		HeartbeatRTT: 1,
+		CheckQuorum:  true,
+	}
+	nh := NewNodeHost(nhc)
+	defer nh.Stop()
+	newPST := func(clusterID uint64, nodeID uint64) sm.IStateMachine {
+		return &PST{}
+	}
+	peers := map[uint64]string{1: singleNodeHostTestAddr}
+	if err := nh.StartCluster(peers, false, newPST, rc); err != nil {
+		t.Fatalf("failed to start cluster %v", err)
+	}
+	waitForLeaderToBeElected(t, nh, 2)
+	tf(t, nh, gc)
+}
+
+func waitForGhostReplica(t *testing.T,
+	gc chan config.GhostReplica) config.GhostReplica {
+	select {
+	case gr := <-gc:
+		return gr
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ghost replica not reported")
+	}
+	return config.GhostReplica{}
+}
+
+func TestMessagesFromRemoteGhostReplicaAreDropped(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost, gc chan config.GhostReplica) {
+		rs, err := nh.RequestAddObserver(2, 2, "localhost:25000", 0, time.Second)
+		if err != nil {
+			t.Fatalf("failed to add observer %v", err)
+		}
+		if v := <-rs.CompletedC; !v.Completed() {
+			t.Fatalf("failed to complete add observer")
+		}
+		rs, err = nh.RequestDeleteNode(2, 2, 0, time.Second)
+		if err != nil {
+			t.Fatalf("failed to delete node %v", err)
+		}
+		if v := <-rs.CompletedC; !v.Completed() {
+			t.Fatalf("failed to complete delete node")
+		}
+		mb := pb.MessageBatch{
+			SourceAddress: "localhost:25000",
+			Requests: []pb.Message{
+				{Type: pb.RequestVote, ClusterId: 2, To: 1, From: 2, Term: 100},
+			},
+		}
+		for i := 0; i < 3; i++ {
+			nh.msgHandler.HandleMessageBatch(mb)
+		}
+		gr := waitForGhostReplica(t, gc)
+		if gr.ClusterID != 2 || gr.NodeID != 2 ||
+			gr.Address != "localhost:25000" || gr.Local {
+			t.Errorf("unexpected ghost replica %+v", gr)
+		}
+		select {
+		case gr := <-gc:
+			t.Errorf("ghost replica reported again %+v", gr)
+		case <-time.After(300 * time.Millisecond):
+		}
+		if leaderID, ok, err := nh.GetLeaderID(2); err != nil ||
+			!ok || leaderID != 1 {
+			t.Errorf("leader changed, %d, %t, %v", leaderID, ok, err)
+		}
+	}
+	ghostReplicaTest(t, false, tf)
+}
+
+func TestLocalGhostReplicaDataCanBeRemoved(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	os.RemoveAll(singleNodeHostTestDir)
+	defer os.RemoveAll(singleNodeHostTestDir)
+	gc := make(chan config.GhostReplica, 16)
+	peers := map[uint64]string{1: nodeHostTestAddr1, 2: nodeHostTestAddr2}
+	nhs := make([]*NodeHost, 0)
+	for nodeID := uint64(1); nodeID <= 2; nodeID++ {
+		dir := path.Join(singleNodeHostTestDir, fmt.Sprintf("nh%d", nodeID))
+		nhc := config.NodeHostConfig{
+			WALDir:         dir,
+			NodeHostDir:    dir,
+			RTTMillisecond: 50,
+			RaftAddress:    peers[nodeID],
+			GhostReplicaHandler: func(gr config.GhostReplica) {
+				if gr.Local {
+					gc <- gr
+				}
+			},
+			RemoveGhostReplica: true,
+		}
+		nh := NewNodeHost(nhc)
+		defer nh.Stop()
+		rc := config.Config{
+			NodeID:       nodeID,
+			ClusterID:    2,
+			ElectionRTT:  5,
+			HeartbeatRTT: 1,
+			CheckQuorum:  true,
+			// the removed node only learns that the removal is committed when
+			// it is the leader
+			PreferredLeaderID: 2,
+		}
+		newPST := func(clusterID uint64, nodeID uint64) sm.IStateMachine {
+			return &PST{}
+		}
+		if err := nh.StartCluster(peers, false, newPST, rc); err != nil {
+			t.Fatalf("failed to start cluster %v", err)
+		}
+		nhs = append(nhs, nh)
+	}
+	nh := nhs[1]
+	for i := 0; ; i++ {
+		if i == 100 {
+			t.Fatalf("failed to elect node 2 as the leader")
+		}
+		leaderID, ok, err := nh.GetLeaderID(2)
+		if err == nil && ok && leaderID == 2 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	rs, err := nh.RequestDeleteNode(2, 2, 0, 3*time.Second)
+	if err != nil {
+		t.Fatalf("failed to delete node %v", err)
+	}
+	if v := <-rs.CompletedC; !v.Completed() {
+		t.Fatalf("failed to complete delete node")
+	}
+	gr := waitForGhostReplica(t, gc)
+	if gr.ClusterID != 2 || gr.NodeID != 2 ||
+		gr.Address != nodeHostTestAddr2 || !gr.Local {
+		t.Errorf("unexpected ghost replica %+v", gr)
+	}
+	for i := 0; i < 50; i++ {
+		if !nh.HasNodeInfo(2, 2) {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Errorf("ghost replica data not removed")
+}
+
+func TestRemoveDataRequiresStoppedNode(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost, gc chan config.GhostReplica) {
+		if err := nh.RemoveData(2, 1); err != ErrClusterNotStopped {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if err := nh.StopCluster(2); err != nil {
+			t.Fatalf("failed to stop cluster %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			err := nh.RemoveData(2, 1)
+			if err == nil {
+				if nh.HasNodeInfo(2, 1) {
+					t.Errorf("node info not removed")
+				}
+				return
+			}
+			if err != ErrClusterNotStopped {
+				t.Fatalf("unexpected error %v", err)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Errorf("failed to remove data")
+	}
+	ghostReplicaTest(t, false, tf)
+}
+
+func TestOffloadedStoppedNodesAreNotTracked(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost, gc chan config.GhostReplica) {
+		if err := nh.StopCluster(2); err != nil {
+			t.Fatalf("failed to stop cluster %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			nh.ghosts.mu.Lock()
+			count := len(nh.ghosts.stopped)
+			nh.ghosts.mu.Unlock()
+			if count == 0 {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Errorf("stopped node still tracked")
+	}
+	ghostReplicaTest(t, false, tf)
+}
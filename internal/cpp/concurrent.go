@@ -0,0 +1,171 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cpp
+
+/*
+#include <stdlib.h>
+#include "wrapper.h"
+*/
+import "C"
+import (
+	"io"
+	"unsafe"
+
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+// ConcurrentStateMachineWrapper is the rsm.IStateMachine adapter for C++ state
+// machines inherited from the dragonboat::ConcurrentStateMachine class. It is
+// expected to be managed by a rsm.NativeStateMachine instance which takes
+// care of client sessions and the life cycle of the state machine.
+type ConcurrentStateMachineWrapper struct {
+	dataStore *C.CPPConcurrentStateMachine
+}
+
+// NewConcurrentStateMachineWrapper creates and returns a new
+// ConcurrentStateMachineWrapper instance.
+func NewConcurrentStateMachineWrapper(clusterID uint64,
+	nodeID uint64, dsname string) *ConcurrentStateMachineWrapper {
+	cDSName := C.CString(getCPPSOFileName(dsname))
+	defer C.free(unsafe.Pointer(cDSName))
+	ds := C.CreateConcurrentDBStateMachine(C.uint64_t(clusterID),
+		C.uint64_t(nodeID), cDSName)
+	return &ConcurrentStateMachineWrapper{dataStore: ds}
+}
+
+// Update updates the C++ state machine using the specified entries. Entries
+// and their Cmd payloads are copied to C memory before passed to the C++
+// state machine.
+func (ds *ConcurrentStateMachineWrapper) Update(entries []sm.Entry) []sm.Entry {
+	if len(entries) == 0 {
+		return entries
+	}
+	count := len(entries)
+	total := 0
+	for _, e := range entries {
+		total += len(e.Cmd)
+	}
+	ep := C.malloc(C.size_t(count) * C.size_t(C.sizeof_Entry))
+	defer C.free(ep)
+	// allocate one extra byte so the buffer is never empty
+	cp := C.malloc(C.size_t(total + 1))
+	defer C.free(cp)
+	ents := (*[1 << 28]C.Entry)(ep)[:count:count]
+	cmds := (*[1 << 30]byte)(cp)[: total+1 : total+1]
+	offset := 0
+	for idx, e := range entries {
+		copy(cmds[offset:], e.Cmd)
+		ents[idx].index = C.uint64_t(e.Index)
+		ents[idx].result = 0
+		ents[idx].cmd = (*C.uchar)(unsafe.Pointer(&cmds[offset]))
+		ents[idx].cmdLen = C.size_t(len(e.Cmd))
+		offset += len(e.Cmd)
+	}
+	C.BatchedUpdateConcurrentDBStateMachine(ds.dataStore,
+		(*C.Entry)(ep), C.size_t(count))
+	for idx := range entries {
+		entries[idx].Result = uint64(ents[idx].result)
+	}
+	return entries
+}
+
+// Lookup queries the C++ state machine.
+func (ds *ConcurrentStateMachineWrapper) Lookup(query []byte) ([]byte, error) {
+	var dp *C.uchar
+	if len(query) > 0 {
+		dp = (*C.uchar)(unsafe.Pointer(&query[0]))
+	}
+	r := C.LookupConcurrentDBStateMachine(ds.dataStore,
+		dp, C.size_t(len(query)))
+	result := C.GoBytes(unsafe.Pointer(r.result), C.int(r.size))
+	C.FreeLookupResultConcurrentDBStateMachine(ds.dataStore, r)
+	return result, nil
+}
+
+// PrepareSnapshot makes preparations for taking concurrent snapshot.
+func (ds *ConcurrentStateMachineWrapper) PrepareSnapshot() (interface{}, error) {
+	r := C.PrepareSnapshotConcurrentDBStateMachine(ds.dataStore)
+	if err := getErrorFromErrNo(int(r.error)); err != nil {
+		return nil, err
+	}
+	return r.result, nil
+}
+
+// SaveSnapshot saves the point in time state identified by the context
+// returned by PrepareSnapshot. The context is released once the snapshot is
+// saved.
+func (ds *ConcurrentStateMachineWrapper) SaveSnapshot(ctx interface{},
+	w io.Writer, fc sm.ISnapshotFileCollection,
+	stopc <-chan struct{}) (uint64, error) {
+	context := ctx.(unsafe.Pointer)
+	defer C.FreePrepareSnapshotResultConcurrentDBStateMachine(ds.dataStore,
+		context)
+	writerOID := AddManagedObject(w)
+	collectionOID := AddManagedObject(fc)
+	doneChOID := AddManagedObject(stopc)
+	defer func() {
+		RemoveManagedObject(writerOID)
+		RemoveManagedObject(collectionOID)
+		RemoveManagedObject(doneChOID)
+	}()
+	r := C.SaveSnapshotConcurrentDBStateMachine(ds.dataStore, context,
+		C.uint64_t(writerOID), C.uint64_t(collectionOID), C.uint64_t(doneChOID))
+	if err := getErrorFromErrNo(int(r.error)); err != nil {
+		plog.Errorf("save snapshot failed, %v", err)
+		return 0, err
+	}
+	return uint64(r.size), nil
+}
+
+// RecoverFromSnapshot recovers the state of the C++ state machine from the
+// snapshot reader.
+func (ds *ConcurrentStateMachineWrapper) RecoverFromSnapshot(r io.Reader,
+	files []sm.SnapshotFile, stopc <-chan struct{}) error {
+	cf := getCollectedFiles(files)
+	defer C.FreeCollectedFile(cf)
+	readerOID := AddManagedObject(r)
+	doneChOID := AddManagedObject(stopc)
+	defer func() {
+		RemoveManagedObject(readerOID)
+		RemoveManagedObject(doneChOID)
+	}()
+	v := C.RecoverFromSnapshotConcurrentDBStateMachine(ds.dataStore,
+		cf, C.uint64_t(readerOID), C.uint64_t(doneChOID))
+	return getErrorFromErrNo(int(v))
+}
+
+// Close closes the C++ state machine.
+func (ds *ConcurrentStateMachineWrapper) Close() {
+	C.DestroyConcurrentDBStateMachine(ds.dataStore)
+}
+
+// GetHash returns the uint64 hash value representing the state of the C++
+// state machine.
+func (ds *ConcurrentStateMachineWrapper) GetHash() uint64 {
+	return uint64(C.GetHashConcurrentDBStateMachine(ds.dataStore))
+}
+
+// ConcurrentSnapshot returns a boolean flag indicating whether the state
+// machine is capable of taking concurrent snapshot.
+func (ds *ConcurrentStateMachineWrapper) ConcurrentSnapshot() bool {
+	return true
+}
+
+// Busy returns a boolean value indicating whether the state machine asked for
+// committed entries to be delivered at a lower rate. It is not supported by
+// C++ state machines.
+func (ds *ConcurrentStateMachineWrapper) Busy() bool {
+	return false
+}
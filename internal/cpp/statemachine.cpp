@@ -60,4 +60,59 @@ void StateMachine::FreeLookupResult(LookupResult r) noexcept
   freeLookupResult(r);
 }
 
+ConcurrentStateMachine::ConcurrentStateMachine(uint64_t clusterID,
+  uint64_t nodeID) noexcept
+  : cluster_id_(clusterID), node_id_(nodeID)
+{
+}
+
+ConcurrentStateMachine::~ConcurrentStateMachine()
+{
+}
+
+void ConcurrentStateMachine::BatchedUpdate(Entry *entries,
+  size_t size) noexcept
+{
+  batchedUpdate(entries, size);
+}
+
+LookupResult ConcurrentStateMachine::Lookup(const Byte *data,
+  size_t size) const noexcept
+{
+  return lookup(data, size);
+}
+
+uint64_t ConcurrentStateMachine::GetHash() const noexcept
+{
+  return getHash();
+}
+
+PrepareSnapshotResult ConcurrentStateMachine::PrepareSnapshot() const noexcept
+{
+  return prepareSnapshot();
+}
+
+SnapshotResult ConcurrentStateMachine::SaveSnapshot(const void *context,
+  SnapshotWriter *writer, SnapshotFileCollection *collection,
+  const DoneChan &done) const noexcept
+{
+  return saveSnapshot(context, writer, collection, done);
+}
+
+int ConcurrentStateMachine::RecoverFromSnapshot(SnapshotReader *reader,
+  const std::vector<SnapshotFile> &files, const DoneChan &done) noexcept
+{
+  return recoverFromSnapshot(reader, files, done);
+}
+
+void ConcurrentStateMachine::FreePrepareSnapshotResult(void *context) noexcept
+{
+  freePrepareSnapshotResult(context);
+}
+
+void ConcurrentStateMachine::FreeLookupResult(LookupResult r) noexcept
+{
+  freeLookupResult(r);
+}
+
 }  // namespace dragonboat
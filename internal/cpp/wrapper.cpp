@@ -22,6 +22,8 @@
 #include "dragonboat/snapshotio.h"
 
 const char createStateMachineFuncName[] = "CreateDragonboatPluginStateMachine";
+const char createConcurrentStateMachineFuncName[] =
+  "CreateDragonboatPluginConcurrentStateMachine";
 
 
 typedef struct CollectedFiles {
@@ -46,6 +48,19 @@ int IsValidDragonboatPlugin(char *soFilename)
   return 0;
 }
 
+int IsConcurrentDragonboatPlugin(char *soFilename)
+{
+  void *handle;
+  void *fn;
+  handle = ::dlopen(soFilename, RTLD_LAZY);
+  if (!handle) {
+    return 1;
+  }
+  fn = ::dlsym(handle, createConcurrentStateMachineFuncName);
+  dlclose(handle);
+  return fn ? 0 : 1;
+}
+
 CPPStateMachine *CreateDBStateMachine(uint64_t clusterID,
   uint64_t nodeID, char *soFilename)
 {
@@ -111,6 +126,85 @@ void FreeLookupResult(CPPStateMachine *ds, LookupResult r)
   ds->sm->FreeLookupResult(r);
 }
 
+CPPConcurrentStateMachine *CreateConcurrentDBStateMachine(uint64_t clusterID,
+  uint64_t nodeID, char *soFilename)
+{
+  void *handle;
+  CPPConcurrentStateMachine *(*fn)(uint64_t, uint64_t);
+  handle = ::dlopen(soFilename, RTLD_LAZY);
+  if (!handle) {
+    fputs(dlerror(), stderr);
+    exit(1);
+  }
+  fn = (CPPConcurrentStateMachine *(*)(uint64_t, uint64_t))::dlsym(handle,
+    createConcurrentStateMachineFuncName);
+  if (!fn) {
+    fputs(dlerror(), stderr);
+    exit(1);
+  }
+  CPPConcurrentStateMachine *ds = (*fn)(clusterID, nodeID);
+  return ds;
+}
+
+void DestroyConcurrentDBStateMachine(CPPConcurrentStateMachine *ds)
+{
+  delete ds->sm;
+  delete ds;
+}
+
+void BatchedUpdateConcurrentDBStateMachine(CPPConcurrentStateMachine *ds,
+  Entry *entries, size_t size)
+{
+  ds->sm->BatchedUpdate(entries, size);
+}
+
+LookupResult LookupConcurrentDBStateMachine(CPPConcurrentStateMachine *ds,
+  const unsigned char *data, size_t size)
+{
+  return ds->sm->Lookup(data, size);
+}
+
+uint64_t GetHashConcurrentDBStateMachine(CPPConcurrentStateMachine *ds)
+{
+  return ds->sm->GetHash();
+}
+
+PrepareSnapshotResult PrepareSnapshotConcurrentDBStateMachine(
+  CPPConcurrentStateMachine *ds)
+{
+  return ds->sm->PrepareSnapshot();
+}
+
+SnapshotResult SaveSnapshotConcurrentDBStateMachine(
+  CPPConcurrentStateMachine *ds, void *context,
+  uint64_t writerOID, uint64_t collectionOID, uint64_t doneChOID)
+{
+  dragonboat::ProxySnapshotWriter writer(writerOID);
+  dragonboat::DoneChan done(doneChOID);
+  dragonboat::SnapshotFileCollection collection(collectionOID);
+  return ds->sm->SaveSnapshot(context, &writer, &collection, done);
+}
+
+int RecoverFromSnapshotConcurrentDBStateMachine(CPPConcurrentStateMachine *ds,
+  CollectedFiles *cf, uint64_t readerOID, uint64_t doneChOID)
+{
+  dragonboat::ProxySnapshotReader reader(readerOID);
+  dragonboat::DoneChan done(doneChOID);
+  return ds->sm->RecoverFromSnapshot(&reader, cf->cf->GetFiles(), done);
+}
+
+void FreePrepareSnapshotResultConcurrentDBStateMachine(
+  CPPConcurrentStateMachine *ds, void *context)
+{
+  ds->sm->FreePrepareSnapshotResult(context);
+}
+
+void FreeLookupResultConcurrentDBStateMachine(CPPConcurrentStateMachine *ds,
+  LookupResult r)
+{
+  ds->sm->FreeLookupResult(r);
+}
+
 CollectedFiles *GetCollectedFile()
 {
   CollectedFiles *result = new CollectedFiles();
@@ -182,10 +182,22 @@ func isValidCPPPlugin(soFilepath string) bool {
 	return C.int(v) == 0
 }
 
+func isConcurrentCPPPlugin(soFilepath string) bool {
+	soName := C.CString(soFilepath)
+	defer C.free(unsafe.Pointer(soName))
+	v := C.IsConcurrentDragonboatPlugin(soName)
+	return C.int(v) == 0
+}
+
 // NewStateMachineWrapper creates and returns the new NewStateMachineWrapper
-// instance.
+// instance. When the C++ plugin provides a concurrent state machine, a
+// NativeStateMachine backed by a ConcurrentStateMachineWrapper is returned.
 func NewStateMachineWrapper(clusterID uint64, nodeID uint64,
 	dsname string, done <-chan struct{}) rsm.IManagedStateMachine {
+	if isConcurrentCPPPlugin(getCPPSOFileName(dsname)) {
+		csm := NewConcurrentStateMachineWrapper(clusterID, nodeID, dsname)
+		return rsm.NewNativeStateMachine(csm, done)
+	}
 	cClusterID := C.uint64_t(clusterID)
 	cNodeID := C.uint64_t(nodeID)
 	cDSName := C.CString(getCPPSOFileName(dsname))
@@ -322,6 +334,13 @@ func (ds *StateMachineWrapper) ConcurrentSnapshot() bool {
 	return false
 }
 
+// Busy returns a boolean value indicating whether the state machine asked for
+// committed entries to be delivered at a lower rate. It is not supported by
+// C++ state machines.
+func (ds *StateMachineWrapper) Busy() bool {
+	return false
+}
+
 // RecoverFromSnapshot recovers the state of the data store from the snapshot
 // file specified by the fp input string.
 func (ds *StateMachineWrapper) RecoverFromSnapshot(fp string,
@@ -341,15 +360,8 @@ func (ds *StateMachineWrapper) RecoverFromSnapshot(fp string,
 	if err != nil {
 		return err
 	}
-	cf := C.GetCollectedFile()
+	cf := getCollectedFiles(files)
 	defer C.FreeCollectedFile(cf)
-	for _, file := range files {
-		fpdata := []byte(file.Filepath)
-		metadata := file.Metadata
-		C.AddToCollectedFile(cf, C.uint64_t(file.FileID),
-			(*C.char)(unsafe.Pointer(&fpdata[0])), C.size_t(len(fpdata)),
-			(*C.uchar)(unsafe.Pointer(&metadata[0])), C.size_t(len(metadata)))
-	}
 	readerOID := AddManagedObject(reader)
 	doneChOID := AddManagedObject(ds.done)
 	r := C.RecoverFromSnapshotDBStateMachine(ds.dataStore,
@@ -363,6 +375,18 @@ func (ds *StateMachineWrapper) RecoverFromSnapshot(fp string,
 	return reader.Close()
 }
 
+func getCollectedFiles(files []sm.SnapshotFile) *C.CollectedFiles {
+	cf := C.GetCollectedFile()
+	for _, file := range files {
+		fpdata := []byte(file.Filepath)
+		metadata := file.Metadata
+		C.AddToCollectedFile(cf, C.uint64_t(file.FileID),
+			(*C.char)(unsafe.Pointer(&fpdata[0])), C.size_t(len(fpdata)),
+			(*C.uchar)(unsafe.Pointer(&metadata[0])), C.size_t(len(metadata)))
+	}
+	return cf
+}
+
 func (ds *StateMachineWrapper) ensureNotDestroyed() {
 	if ds.Destroyed() {
 		panic("using a destroyed data store instance detected")
@@ -23,9 +23,11 @@ extern "C" {
 #endif
 
 typedef struct CPPStateMachine CPPStateMachine;
+typedef struct CPPConcurrentStateMachine CPPConcurrentStateMachine;
 typedef struct CollectedFiles CollectedFiles;
 
 int IsValidDragonboatPlugin(char *soFilename);
+int IsConcurrentDragonboatPlugin(char *soFilename);
 CPPStateMachine *CreateDBStateMachine(uint64_t clusterID,
   uint64_t nodeID, char *soFilename);
 void DestroyDBStateMachine(CPPStateMachine *ds);
@@ -39,6 +41,25 @@ SnapshotResult SaveSnapshotDBStateMachine(CPPStateMachine *ds,
 int RecoverFromSnapshotDBStateMachine(CPPStateMachine *ds,
   CollectedFiles *cf, uint64_t readerOID, uint64_t doneChOID);
 void FreeLookupResult(CPPStateMachine *ds, LookupResult r);
+CPPConcurrentStateMachine *CreateConcurrentDBStateMachine(uint64_t clusterID,
+  uint64_t nodeID, char *soFilename);
+void DestroyConcurrentDBStateMachine(CPPConcurrentStateMachine *ds);
+void BatchedUpdateConcurrentDBStateMachine(CPPConcurrentStateMachine *ds,
+  Entry *entries, size_t size);
+LookupResult LookupConcurrentDBStateMachine(CPPConcurrentStateMachine *ds,
+  const unsigned char *data, size_t size);
+uint64_t GetHashConcurrentDBStateMachine(CPPConcurrentStateMachine *ds);
+PrepareSnapshotResult PrepareSnapshotConcurrentDBStateMachine(
+  CPPConcurrentStateMachine *ds);
+SnapshotResult SaveSnapshotConcurrentDBStateMachine(
+  CPPConcurrentStateMachine *ds, void *context,
+  uint64_t writerOID, uint64_t collectionOID, uint64_t doneChOID);
+int RecoverFromSnapshotConcurrentDBStateMachine(CPPConcurrentStateMachine *ds,
+  CollectedFiles *cf, uint64_t readerOID, uint64_t doneChOID);
+void FreePrepareSnapshotResultConcurrentDBStateMachine(
+  CPPConcurrentStateMachine *ds, void *context);
+void FreeLookupResultConcurrentDBStateMachine(CPPConcurrentStateMachine *ds,
+  LookupResult r);
 CollectedFiles *GetCollectedFile();
 void FreeCollectedFile(CollectedFiles *cf);
 void AddToCollectedFile(CollectedFiles *cf, uint64_t fileID,
@@ -28,6 +28,7 @@ import (
 	"github.com/lni/dragonboat/internal/rsm"
 	"github.com/lni/dragonboat/internal/tests/kvpb"
 	"github.com/lni/dragonboat/internal/utils/leaktest"
+	sm "github.com/lni/dragonboat/statemachine"
 )
 
 func TestManagedObjectCanBeAddedReturnedAndRemoved(t *testing.T) {
@@ -242,3 +243,73 @@ func TestCppSnapshotWorks(t *testing.T) {
 		t.Errorf("session hash does not match")
 	}
 }
+
+func TestConcurrentCppPluginIsDetected(t *testing.T) {
+	if isConcurrentCPPPlugin(getCPPSOFileName("example")) {
+		t.Errorf("regular plugin considered as concurrent")
+	}
+	if !isConcurrentCPPPlugin(getCPPSOFileName("concurrentexample")) {
+		t.Errorf("concurrent plugin not detected")
+	}
+	ds := NewStateMachineWrapper(1, 1, "concurrentexample", nil)
+	if _, ok := ds.(*rsm.NativeStateMachine); !ok {
+		t.Errorf("unexpected type %T", ds)
+	}
+	ds.Offloaded(rsm.FromNodeHost)
+}
+
+func TestConcurrentCppWrapperCanBeUpdatedAndLookedUp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ds := NewConcurrentStateMachineWrapper(1, 1, "concurrentexample")
+	defer ds.Close()
+	entries := []sm.Entry{
+		{Index: 1, Cmd: []byte("test-data-1")},
+		{Index: 2, Cmd: nil},
+		{Index: 3, Cmd: []byte("test-data-3")},
+	}
+	results := ds.Update(entries)
+	for idx, e := range results {
+		if e.Result != uint64(idx+1) {
+			t.Errorf("result %d, want %d", e.Result, idx+1)
+		}
+	}
+	result, err := ds.Lookup([]byte("test-lookup-data"))
+	if err != nil {
+		t.Errorf("failed to lookup")
+	}
+	if v := binary.LittleEndian.Uint32(result); v != 3 {
+		t.Errorf("returned %d, want 3", v)
+	}
+	if ds.GetHash() != 3 {
+		t.Errorf("unexpected hash %d", ds.GetHash())
+	}
+}
+
+func TestConcurrentCppSnapshotWorks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ds := NewConcurrentStateMachineWrapper(1, 1, "concurrentexample")
+	defer ds.Close()
+	ds.Update([]sm.Entry{{Index: 1, Cmd: []byte("test-data-1")}})
+	ctx, err := ds.PrepareSnapshot()
+	if err != nil {
+		t.Fatalf("failed to prepare snapshot %v", err)
+	}
+	// updates after PrepareSnapshot are not included in the snapshot
+	ds.Update([]sm.Entry{{Index: 2, Cmd: []byte("test-data-2")}})
+	buf := bytes.NewBuffer(nil)
+	sz, err := ds.SaveSnapshot(ctx, buf, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to save snapshot %v", err)
+	}
+	if sz != uint64(buf.Len()) {
+		t.Errorf("sz %d, want %d", sz, buf.Len())
+	}
+	ds2 := NewConcurrentStateMachineWrapper(1, 1, "concurrentexample")
+	defer ds2.Close()
+	if err := ds2.RecoverFromSnapshot(buf, nil, nil); err != nil {
+		t.Fatalf("failed to recover from snapshot %v", err)
+	}
+	if ds2.GetHash() != 1 {
+		t.Errorf("hash %d, want 1", ds2.GetHash())
+	}
+}
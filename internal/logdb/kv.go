@@ -45,6 +45,10 @@ type IKvStore interface {
 	// CommitWriteBatch atomically writes everything included in the write batch
 	// to the underlying key-value store.
 	CommitWriteBatch(wb IWriteBatch) error
+	// CommitWriteBatchNoSync is similar to CommitWriteBatch, but it returns
+	// without waiting for the write to be synced to disk. The write becomes
+	// durable once a following synced write completes.
+	CommitWriteBatchNoSync(wb IWriteBatch) error
 	// RemoveEntries removes entries specified by the range [firstKey, lastKey).
 	// RemoveEntries is called in the main execution thread of raft, it is
 	// suppose to immediately return without significant delay.
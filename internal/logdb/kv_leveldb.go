@@ -60,6 +60,7 @@ type leveldbKV struct {
 	opts *levigo.Options
 	ro   *levigo.ReadOptions
 	wo   *levigo.WriteOptions
+	nwo  *levigo.WriteOptions
 }
 
 func openLevelDB(dir string, wal string) (*leveldbKV, error) {
@@ -76,10 +77,12 @@ func openLevelDB(dir string, wal string) (*leveldbKV, error) {
 	ro.SetFillCache(false)
 	ro.SetVerifyChecksums(true)
 	wo.SetSync(true)
+	nwo := levigo.NewWriteOptions()
 	return &leveldbKV{
 		db:   db,
 		ro:   ro,
 		wo:   wo,
+		nwo:  nwo,
 		opts: opts,
 	}, nil
 }
@@ -96,6 +99,9 @@ func (r *leveldbKV) Close() error {
 	if r.wo != nil {
 		r.wo.Close()
 	}
+	if r.nwo != nil {
+		r.nwo.Close()
+	}
 	if r.ro != nil {
 		r.ro.Close()
 	}
@@ -181,6 +187,14 @@ func (r *leveldbKV) CommitWriteBatch(wb IWriteBatch) error {
 	return r.db.Write(r.wo, lwb.wb)
 }
 
+func (r *leveldbKV) CommitWriteBatchNoSync(wb IWriteBatch) error {
+	lwb, ok := wb.(*leveldbWriteBatch)
+	if !ok {
+		panic("unknown type")
+	}
+	return r.db.Write(r.nwo, lwb.wb)
+}
+
 func (r *leveldbKV) RemoveEntries(firstKey []byte, lastKey []byte) error {
 	return nil
 }
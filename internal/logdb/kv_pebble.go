@@ -69,6 +69,7 @@ type pebbleKV struct {
 	opts *db.Options
 	ro   *db.IterOptions
 	wo   *db.WriteOptions
+	nwo  *db.WriteOptions
 }
 
 func openPebbleDB(dir string) (*pebbleKV, error) {
@@ -81,10 +82,12 @@ func openPebbleDB(dir string) (*pebbleKV, error) {
 	}
 	ro := &db.IterOptions{}
 	wo := &db.WriteOptions{Sync: true}
+	nwo := &db.WriteOptions{Sync: false}
 	return &pebbleKV{
 		db:   pdb,
 		ro:   ro,
 		wo:   wo,
+		nwo:  nwo,
 		opts: opts,
 	}, nil
 }
@@ -171,6 +174,14 @@ func (r *pebbleKV) CommitWriteBatch(wb IWriteBatch) error {
 	return r.db.Apply(pwb.wb, r.wo)
 }
 
+func (r *pebbleKV) CommitWriteBatchNoSync(wb IWriteBatch) error {
+	pwb, ok := wb.(*pebbleWriteBatch)
+	if !ok {
+		panic("unknown type")
+	}
+	return r.db.Apply(pwb.wb, r.nwo)
+}
+
 func (r *pebbleKV) RemoveEntries(firstKey []byte, lastKey []byte) error {
 	return nil
 }
@@ -49,6 +49,7 @@ type rocksdbKV struct {
 	db        *gorocksdb.DB
 	ro        *gorocksdb.ReadOptions
 	wo        *gorocksdb.WriteOptions
+	nwo       *gorocksdb.WriteOptions
 	opts      *gorocksdb.Options
 }
 
@@ -155,6 +156,7 @@ func openRocksDB(dir string, wal string) (*rocksdbKV, error) {
 	}
 	wo := gorocksdb.NewDefaultWriteOptions()
 	wo.SetSync(true)
+	nwo := gorocksdb.NewDefaultWriteOptions()
 	ro := gorocksdb.NewDefaultReadOptions()
 	ro.SetFillCache(false)
 	ro.SetTotalOrderSeek(true)
@@ -166,6 +168,7 @@ func openRocksDB(dir string, wal string) (*rocksdbKV, error) {
 		db:        db,
 		ro:        ro,
 		wo:        wo,
+		nwo:       nwo,
 		opts:      opts,
 	}, nil
 }
@@ -191,6 +194,9 @@ func (r *rocksdbKV) Close() error {
 	if r.wo != nil {
 		r.wo.Destroy()
 	}
+	if r.nwo != nil {
+		r.nwo.Destroy()
+	}
 	if r.ro != nil {
 		r.ro.Destroy()
 	}
@@ -277,6 +283,14 @@ func (r *rocksdbKV) CommitWriteBatch(wb IWriteBatch) error {
 	return r.db.Write(r.wo, rocksdbwb)
 }
 
+func (r *rocksdbKV) CommitWriteBatchNoSync(wb IWriteBatch) error {
+	rocksdbwb, ok := wb.(*gorocksdb.WriteBatch)
+	if !ok {
+		panic("unknown type")
+	}
+	return r.db.Write(r.nwo, rocksdbwb)
+}
+
 func (r *rocksdbKV) RemoveEntries(firstKey []byte, lastKey []byte) error {
 	if err := r.db.DeleteFileInRange(firstKey, lastKey); err != nil {
 		return err
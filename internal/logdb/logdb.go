@@ -21,6 +21,8 @@ to import this package.
 package logdb
 
 import (
+	"time"
+
 	"github.com/lni/dragonboat/logger"
 	"github.com/lni/dragonboat/raftio"
 )
@@ -51,6 +53,22 @@ func checkDirs(dirs []string, lldirs []string) {
 
 // OpenLogDB opens a LogDB instance using the default implementation.
 func OpenLogDB(dirs []string, lowLatencyDirs []string) (raftio.ILogDB, error) {
+	return openLogDB(dirs, lowLatencyDirs, 0)
+}
+
+// OpenRelaxedLogDB opens a LogDB instance using the default implementation
+// with relaxed durability. Raft states and entries are saved without waiting
+// for them to be synced to disk, they are synced every syncInterval instead.
+func OpenRelaxedLogDB(dirs []string, lowLatencyDirs []string,
+	syncInterval time.Duration) (raftio.ILogDB, error) {
+	if syncInterval <= 0 {
+		panic("invalid sync interval")
+	}
+	return openLogDB(dirs, lowLatencyDirs, syncInterval)
+}
+
+func openLogDB(dirs []string, lowLatencyDirs []string,
+	syncInterval time.Duration) (raftio.ILogDB, error) {
 	checkDirs(dirs, lowLatencyDirs)
 	llDirRequired := len(lowLatencyDirs) == 1
 	if len(dirs) == 1 {
@@ -61,5 +79,5 @@ func OpenLogDB(dirs []string, lowLatencyDirs []string) (raftio.ILogDB, error) {
 			}
 		}
 	}
-	return OpenShardedRDB(dirs, lowLatencyDirs)
+	return openShardedRDB(dirs, lowLatencyDirs, syncInterval)
 }
@@ -39,6 +39,9 @@ var (
 	snapshotKeyHeader        = [2]byte{0x5, 0x5}
 	bootstrapKeyHeader       = [2]byte{0x6, 0x6}
 	entryBatchKeyHeader      = [2]byte{0x7, 0x7}
+	// syncMarkerKey is the key written with sync enabled to have all previous
+	// unsynced writes synced to disk
+	syncMarkerKey = []byte{0x8, 0x8}
 )
 
 // PooledKey represents keys that are managed by a sync.Pool to be reused.
@@ -18,6 +18,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"math"
+	"sync/atomic"
 
 	"github.com/lni/dragonboat/internal/settings"
 	"github.com/lni/dragonboat/raftio"
@@ -30,23 +31,41 @@ var (
 
 // RDB is the struct used to manage rocksdb backed persistent Log stores.
 type RDB struct {
-	cs   *rdbcache
-	keys *logdbKeyPool
-	kvs  IKvStore
+	// unsynced indicates whether there are writes not yet synced to disk
+	unsynced uint32
+	// relaxed indicates whether raft states are saved without waiting for
+	// them to be synced to disk
+	relaxed bool
+	cs      *rdbcache
+	keys    *logdbKeyPool
+	kvs     IKvStore
 }
 
-func openRDB(dir string, wal string) (*RDB, error) {
+func openRDB(dir string, wal string, relaxed bool) (*RDB, error) {
 	kvs, err := newKVStore(dir, wal)
 	if err != nil {
 		return nil, err
 	}
 	return &RDB{
-		cs:   newRDBCache(),
-		keys: newLogdbKeyPool(),
-		kvs:  kvs,
+		relaxed: relaxed,
+		cs:      newRDBCache(),
+		keys:    newLogdbKeyPool(),
+		kvs:     kvs,
 	}, nil
 }
 
+// sync syncs all previous raft state writes to disk.
+func (r *RDB) sync() error {
+	if atomic.SwapUint32(&r.unsynced, 0) == 0 {
+		return nil
+	}
+	if err := r.kvs.SaveValue(syncMarkerKey, nil); err != nil {
+		atomic.StoreUint32(&r.unsynced, 1)
+		return err
+	}
+	return nil
+}
+
 func (r *RDB) close() {
 	if err := r.kvs.Close(); err != nil {
 		panic(err)
@@ -108,6 +127,13 @@ func (r *RDB) saveRaftState(updates []pb.Update,
 	}
 	r.saveEntries(updates, wb, ctx)
 	if wb.Count() > 0 {
+		if r.relaxed {
+			if err := r.kvs.CommitWriteBatchNoSync(wb); err != nil {
+				return err
+			}
+			atomic.StoreUint32(&r.unsynced, 1)
+			return nil
+		}
 		return r.kvs.CommitWriteBatch(wb)
 	}
 	return nil
@@ -429,6 +455,58 @@ func (r *RDB) compaction(clusterID uint64, nodeID uint64, index uint64) error {
 	return r.rangedEntryOp(clusterID, nodeID, index, op)
 }
 
+func (r *RDB) removeNodeData(clusterID uint64, nodeID uint64) error {
+	firstKey := r.keys.get()
+	lastKey := r.keys.get()
+	defer firstKey.Release()
+	defer lastKey.Release()
+	ranges := []func(){
+		func() {
+			firstKey.SetEntryBatchKey(clusterID, nodeID, 0)
+			lastKey.SetEntryBatchKey(clusterID, nodeID, math.MaxUint64)
+		},
+		func() {
+			firstKey.SetEntryKey(clusterID, nodeID, 0)
+			lastKey.SetEntryKey(clusterID, nodeID, math.MaxUint64)
+		},
+	}
+	for _, setRange := range ranges {
+		setRange()
+		if err := r.kvs.RemoveEntries(firstKey.Key(), lastKey.Key()); err != nil {
+			return err
+		}
+		if err := r.kvs.Compaction(firstKey.Key(), lastKey.Key()); err != nil {
+			return err
+		}
+	}
+	snapshots, err := r.listSnapshots(clusterID, nodeID)
+	if err != nil {
+		return err
+	}
+	for _, ss := range snapshots {
+		if err := r.deleteSnapshot(clusterID, nodeID, ss.Index); err != nil {
+			return err
+		}
+	}
+	ko := r.keys.get()
+	defer ko.Release()
+	keys := []func(){
+		func() { ko.SetStateKey(clusterID, nodeID) },
+		func() { ko.SetMaxIndexKey(clusterID, nodeID) },
+		func() { ko.setNodeInfoKey(clusterID, nodeID) },
+		// the bootstrap key is removed last as it is used for listing nodes
+		func() { ko.setBootstrapKey(clusterID, nodeID) },
+	}
+	for _, setKey := range keys {
+		setKey()
+		if err := r.kvs.DeleteValue(ko.Key()); err != nil {
+			return err
+		}
+	}
+	r.cs.removeNode(clusterID, nodeID)
+	return nil
+}
+
 func (r *RDB) rangedEntryOp(clusterID uint64,
 	nodeID uint64, index uint64,
 	op func(firstKey *PooledKey, lastKey *PooledKey) error) error {
@@ -18,7 +18,9 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/lni/dragonboat/internal/utils/leaktest"
 	"github.com/lni/dragonboat/raftio"
@@ -859,6 +861,91 @@ func TestSaveSnapshot(t *testing.T) {
 	runLogDBTest(t, tf)
 }
 
+func TestNodeDataCanBeRemoved(t *testing.T) {
+	tf := func(t *testing.T, db raftio.ILogDB) {
+		bs := pb.Bootstrap{Addresses: map[uint64]string{4: "address1"}}
+		if err := db.SaveBootstrapInfo(3, 4, bs); err != nil {
+			t.Fatalf("failed to save bootstrap info %v", err)
+		}
+		if err := db.SaveBootstrapInfo(3, 5, bs); err != nil {
+			t.Fatalf("failed to save bootstrap info %v", err)
+		}
+		testSaveRaftState(t, db)
+		ud := pb.Update{
+			ClusterID: 3,
+			NodeID:    4,
+			Snapshot:  pb.Snapshot{Index: 5, Term: 1},
+		}
+		if err := db.SaveSnapshots([]pb.Update{ud}); err != nil {
+			t.Fatalf("failed to save snapshot %v", err)
+		}
+		remover, ok := db.(raftio.INodeDataRemover)
+		if !ok {
+			t.Fatalf("RemoveNodeData not supported")
+		}
+		if err := remover.RemoveNodeData(3, 4); err != nil {
+			t.Fatalf("failed to remove node data %v", err)
+		}
+		if _, err := db.GetBootstrapInfo(3, 4); err != raftio.ErrNoBootstrapInfo {
+			t.Errorf("unexpected error %v", err)
+		}
+		if _, err := db.ReadRaftState(3, 4, 0); err != raftio.ErrNoSavedLog {
+			t.Errorf("unexpected error %v", err)
+		}
+		snapshots, err := db.ListSnapshots(3, 4)
+		if err != nil {
+			t.Fatalf("failed to list snapshots %v", err)
+		}
+		if len(snapshots) != 0 {
+			t.Errorf("snapshot list sz %d, want 0", len(snapshots))
+		}
+		ni, err := db.ListNodeInfo()
+		if err != nil {
+			t.Fatalf("failed to list node info %v", err)
+		}
+		if len(ni) != 1 || ni[0].ClusterID != 3 || ni[0].NodeID != 5 {
+			t.Errorf("unexpected node info list %v", ni)
+		}
+	}
+	runLogDBTest(t, tf)
+}
+
+func TestRelaxedDurabilityRDB(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer deleteTestDB()
+	d := filepath.Join(RDBTestDirectory, "db-dir")
+	lld := filepath.Join(RDBTestDirectory, "wal-db-dir")
+	os.MkdirAll(d, 0777)
+	os.MkdirAll(lld, 0777)
+	db, err := openLogDB([]string{d}, []string{lld}, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to open the db %v", err)
+	}
+	testSaveRaftState(t, db)
+	mw := db.(*ShardedRDB)
+	shard := mw.shards[mw.partitioner.GetPartitionID(3)]
+	if atomic.LoadUint32(&shard.unsynced) != 1 {
+		t.Errorf("raft state unexpectedly synced")
+	}
+	if err := shard.sync(); err != nil {
+		t.Fatalf("sync failed %v", err)
+	}
+	if atomic.LoadUint32(&shard.unsynced) != 0 {
+		t.Errorf("raft state not synced")
+	}
+	testSaveRaftState(t, db)
+	db.Close()
+	db = getNewTestDB("db-dir", "wal-db-dir")
+	defer db.Close()
+	rs, err := db.ReadRaftState(3, 4, 0)
+	if err != nil {
+		t.Fatalf("failed to read raft state %v", err)
+	}
+	if rs.State.Term != 2 || rs.EntryCount != 10 {
+		t.Errorf("unexpected raft state %v", rs)
+	}
+}
+
 func TestParseNodeInfoKeyPanicOnUnexpectedKeySize(t *testing.T) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -114,3 +114,13 @@ func (r *rdbcache) getLastEntryBatch(clusterID uint64,
 	lb.Entries = append(lb.Entries, v.Entries...)
 	return lb, true
 }
+
+func (r *rdbcache) removeNode(clusterID uint64, nodeID uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := raftio.NodeInfo{ClusterID: clusterID, NodeID: nodeID}
+	delete(r.nodeInfo, key)
+	delete(r.ps, key)
+	delete(r.lastEntryBatch, key)
+	delete(r.maxIndex, key)
+}
@@ -19,6 +19,7 @@ import (
 	"math"
 	"path/filepath"
 	"sync/atomic"
+	"time"
 
 	"github.com/lni/dragonboat/internal/server"
 	"github.com/lni/dragonboat/internal/settings"
@@ -42,11 +43,20 @@ type ShardedRDB struct {
 	partitioner          server.IPartitioner
 	compactionCh         chan struct{}
 	compactions          *compactions
+	syncInterval         time.Duration
 	stopper              *syncutil.Stopper
 }
 
 // OpenShardedRDB creates a ShardedRDB instance.
 func OpenShardedRDB(dirs []string, lldirs []string) (*ShardedRDB, error) {
+	return openShardedRDB(dirs, lldirs, 0)
+}
+
+// openShardedRDB creates a ShardedRDB instance. When syncInterval is not
+// zero, raft states are saved without waiting for them to be synced to disk,
+// they are synced every syncInterval instead.
+func openShardedRDB(dirs []string,
+	lldirs []string, syncInterval time.Duration) (*ShardedRDB, error) {
 	shards := make([]*RDB, 0)
 	for i := uint64(0); i < numOfRocksDBInstance; i++ {
 		dir := filepath.Join(dirs[i], fmt.Sprintf("logdb-%d", i))
@@ -54,7 +64,7 @@ func OpenShardedRDB(dirs []string, lldirs []string) (*ShardedRDB, error) {
 		if len(lldirs) > 0 {
 			lldir = filepath.Join(lldirs[i], fmt.Sprintf("logdb-%d", i))
 		}
-		db, err := openRDB(dir, lldir)
+		db, err := openRDB(dir, lldir, syncInterval > 0)
 		if err != nil {
 			return nil, err
 		}
@@ -67,6 +77,7 @@ func OpenShardedRDB(dirs []string, lldirs []string) (*ShardedRDB, error) {
 		partitioner:  partitioner,
 		compactions:  newCompactions(),
 		compactionCh: make(chan struct{}, 1),
+		syncInterval: syncInterval,
 		stopper:      syncutil.NewStopper(),
 	}
 	if useRangeDelete {
@@ -77,6 +88,13 @@ func OpenShardedRDB(dirs []string, lldirs []string) (*ShardedRDB, error) {
 	mw.stopper.RunWorker(func() {
 		mw.compactionWorkerMain()
 	})
+	if syncInterval > 0 {
+		plog.Warningf("relaxed durability is enabled in %s, sync interval %s",
+			mw.Name(), syncInterval)
+		mw.stopper.RunWorker(func() {
+			mw.syncWorkerMain()
+		})
+	}
 	return mw, nil
 }
 
@@ -185,10 +203,19 @@ func (mw *ShardedRDB) RemoveEntriesTo(clusterID uint64,
 	return nil
 }
 
+// RemoveNodeData removes all data associated with the specified raft node.
+func (mw *ShardedRDB) RemoveNodeData(clusterID uint64, nodeID uint64) error {
+	idx := mw.partitioner.GetPartitionID(clusterID)
+	return mw.shards[idx].removeNodeData(clusterID, nodeID)
+}
+
 // Close closes the ShardedRDB instance.
 func (mw *ShardedRDB) Close() {
 	mw.stopper.Stop()
 	for _, v := range mw.shards {
+		if err := v.sync(); err != nil {
+			panic(err)
+		}
 		v.close()
 	}
 }
@@ -227,6 +254,23 @@ func (mw *ShardedRDB) compactionWorkerMain() {
 	}
 }
 
+func (mw *ShardedRDB) syncWorkerMain() {
+	ticker := time.NewTicker(mw.syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-mw.stopper.ShouldStop():
+			return
+		case <-ticker.C:
+			for _, v := range mw.shards {
+				if err := v.sync(); err != nil {
+					panic(err)
+				}
+			}
+		}
+	}
+}
+
 func (mw *ShardedRDB) addCompaction(clusterID uint64,
 	nodeID uint64, index uint64) {
 	task := task{
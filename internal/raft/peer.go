@@ -57,10 +57,16 @@ type PeerAddress struct {
 // protocol implementation.
 type Peer struct {
 	leaderID  uint64
+	leader    atomic.Value
 	raft      *raft
 	prevState pb.State
 }
 
+type leaderInfo struct {
+	leaderID uint64
+	term     uint64
+}
+
 // LaunchPeer starts or restarts a Raft node.
 func LaunchPeer(config *config.Config, logdb ILogDB,
 	addresses []PeerAddress, initial bool, newNode bool) (*Peer, error) {
@@ -277,8 +283,20 @@ func (rc *Peer) NotifyRaftLastApplied(lastApplied uint64) {
 	rc.raft.setApplied(lastApplied)
 }
 
-func (rc *Peer) recordLeader(leaderID uint64) {
+// GetLeaderInfo returns the leader id and the term in which it is known to be
+// the leader.
+func (rc *Peer) GetLeaderInfo() (uint64, uint64) {
+	v := rc.leader.Load()
+	if v == nil {
+		return NoLeader, 0
+	}
+	li := v.(leaderInfo)
+	return li.leaderID, li.term
+}
+
+func (rc *Peer) recordLeader(leaderID uint64, term uint64) {
 	atomic.StoreUint64(&rc.leaderID, leaderID)
+	rc.leader.Store(leaderInfo{leaderID: leaderID, term: term})
 }
 
 func (rc *Peer) entryLog() *entryLog {
@@ -92,6 +92,9 @@ func TestLeaderIDCanBeReportedBackToPeer(t *testing.T) {
 	if p.GetLeaderID() != 1 {
 		t.Errorf("leader id not reported back")
 	}
+	if leaderID, term := p.GetLeaderInfo(); leaderID != 1 || term != 2 {
+		t.Errorf("unexpected leader info %d, %d", leaderID, term)
+	}
 }
 
 func TestRaftAPIRequestLeaderTransfer(t *testing.T) {
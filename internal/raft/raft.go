@@ -206,6 +206,7 @@ type raft struct {
 	readIndex                 *readIndex
 	readyToRead               []pb.ReadyToRead
 	checkQuorum               bool
+	preferredLeaderID         uint64
 	tickCount                 uint64
 	electionTick              uint64
 	heartbeatTick             uint64
@@ -216,7 +217,7 @@ type raft struct {
 	handle                    stepFunc
 	matched                   []uint64
 	hasNotAppliedConfigChange func() bool
-	recordLeader              func(uint64)
+	recordLeader              func(uint64, uint64)
 }
 
 func newRaft(c *config.Config, logdb ILogDB) *raft {
@@ -228,18 +229,19 @@ func newRaft(c *config.Config, logdb ILogDB) *raft {
 	}
 	rl := server.NewRateLimiter(c.MaxInMemLogSize)
 	r := &raft{
-		clusterID:        c.ClusterID,
-		nodeID:           c.NodeID,
-		leaderID:         NoLeader,
-		msgs:             make([]pb.Message, 0),
-		log:              newEntryLog(logdb, rl),
-		remotes:          make(map[uint64]*remote),
-		observers:        make(map[uint64]*remote),
-		electionTimeout:  c.ElectionRTT,
-		heartbeatTimeout: c.HeartbeatRTT,
-		checkQuorum:      c.CheckQuorum,
-		readIndex:        newReadIndex(),
-		rl:               rl,
+		clusterID:         c.ClusterID,
+		nodeID:            c.NodeID,
+		leaderID:          NoLeader,
+		msgs:              make([]pb.Message, 0),
+		log:               newEntryLog(logdb, rl),
+		remotes:           make(map[uint64]*remote),
+		observers:         make(map[uint64]*remote),
+		electionTimeout:   c.ElectionRTT,
+		heartbeatTimeout:  c.HeartbeatRTT,
+		checkQuorum:       c.CheckQuorum,
+		preferredLeaderID: c.PreferredLeaderID,
+		readIndex:         newReadIndex(),
+		rl:                rl,
 	}
 	plog.Infof("raft log rate limit enabled: %t, %d",
 		r.rl.Enabled(), c.MaxInMemLogSize)
@@ -258,7 +260,14 @@ func newRaft(c *config.Config, logdb ILogDB) *raft {
 	if !pb.IsEmptyState(st) {
 		r.loadState(st)
 	}
-	if c.IsObserver {
+	_, promoted := r.remotes[r.nodeID]
+	if c.IsObserver && promoted {
+		// the observer has been promoted to a regular node in the past, keep
+		// it as a voting member even when restarted with IsObserver set
+		plog.Infof("%s restarted as observer but was promoted, not observer",
+			r.describe())
+	}
+	if c.IsObserver && !promoted {
 		r.state = observer
 		r.becomeObserver(r.term, NoLeader)
 	} else {
@@ -310,7 +319,7 @@ func (r *raft) isObserver() bool {
 func (r *raft) setLeaderID(leaderID uint64) {
 	r.leaderID = leaderID
 	if r.recordLeader != nil {
-		r.recordLeader(r.leaderID)
+		r.recordLeader(r.leaderID, r.term)
 	}
 }
 
@@ -502,6 +511,8 @@ func (r *raft) leaderTick() {
 	timeToAbortLeaderTransfer := r.timeToAbortLeaderTransfer()
 	if r.timeForCheckQuorum() {
 		r.electionTick = 0
+		// must be done before the CheckQuorum resets the active flags
+		r.transferToPreferredLeader()
 		if r.checkQuorum {
 			r.Handle(pb.Message{
 				From: r.nodeID,
@@ -1481,6 +1492,35 @@ func (r *raft) handleLeaderTransfer(m pb.Message, rp *remote) {
 	}
 }
 
+// transferToPreferredLeader starts to transfer the leadership to the preferred
+// leader when it responded to the leader during the last election timeout and
+// its log is up to date.
+func (r *raft) transferToPreferredLeader() {
+	target := r.preferredLeaderID
+	if target == NoNode || target == r.nodeID || r.leaderTransfering() {
+		return
+	}
+	rp, ok := r.remotes[target]
+	if !ok {
+		return
+	}
+	active := rp.isActive()
+	if !r.checkQuorum {
+		// active flags are otherwise only reset by CheckQuorum
+		rp.setNotActive()
+	}
+	if !active || rp.match != r.log.lastIndex() {
+		return
+	}
+	plog.Infof("%s transferring leadership to preferred leader %s",
+		r.describe(), NodeID(target))
+	r.Handle(pb.Message{
+		Type: pb.LeaderTransfer,
+		From: target,
+		Hint: target,
+	})
+}
+
 func (r *raft) handleReadIndexLeaderConfirmation(m pb.Message) {
 	ctx := pb.SystemCtx{
 		Low:  m.Hint,
@@ -2243,3 +2243,74 @@ func TestRateLimitMessageIsSentByNonLeader(t *testing.T) {
 	testRateLimitMessageIsSentByNonLeader(2, true, t)
 	testRateLimitMessageIsSentByNonLeader(NoLeader, false, t)
 }
+
+func TestPromotedObserverRestartsAsRegularNode(t *testing.T) {
+	st := NewTestLogDB()
+	ss := pb.Snapshot{
+		Index: 100,
+		Term:  2,
+		Membership: pb.Membership{
+			Addresses: map[uint64]string{1: "a1", 2: "a2"},
+		},
+	}
+	if err := st.ApplySnapshot(ss); err != nil {
+		t.Fatalf("apply snapshot failed %v", err)
+	}
+	p := newTestObserver(2, nil, nil, 10, 1, st)
+	if p.isObserver() {
+		t.Errorf("promoted observer restarted as observer")
+	}
+	if p.state != follower {
+		t.Errorf("state %s, want follower", p.state)
+	}
+}
+
+func tickLeader(nt *network, lead *raft, ticks uint64) {
+	for i := uint64(0); i < ticks; i++ {
+		lead.tick()
+		nt.send(nt.filter(lead.readMessages())...)
+	}
+}
+
+func TestLeadershipIsTransferredToPreferredLeader(t *testing.T) {
+	nt := newNetwork(nil, nil, nil)
+	lead := nt.peers[1].(*raft)
+	lead.preferredLeaderID = 2
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.Election})
+	if lead.state != leader {
+		t.Fatalf("node 1 is not the leader")
+	}
+	tickLeader(nt, lead, lead.electionTimeout)
+	checkLeaderTransferState(t, lead, follower, 2)
+}
+
+func TestLeadershipIsNotTransferredToInactivePreferredLeader(t *testing.T) {
+	nt := newNetwork(nil, nil, nil)
+	lead := nt.peers[1].(*raft)
+	lead.preferredLeaderID = 2
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.Election})
+	nt.isolate(2)
+	// the transfer started at the end of the first election timeout is aborted
+	tickLeader(nt, lead, lead.electionTimeout*2)
+	if lead.state != leader || lead.leaderTransfering() {
+		t.Fatalf("leadership unexpectedly being transferred")
+	}
+	tickLeader(nt, lead, lead.electionTimeout)
+	if lead.leaderTransfering() {
+		t.Errorf("leadership transferred to inactive node")
+	}
+}
+
+func TestLeadershipIsNotTransferredToSlowPreferredLeader(t *testing.T) {
+	nt := newNetwork(nil, nil, nil)
+	lead := nt.peers[1].(*raft)
+	lead.preferredLeaderID = 2
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.Election})
+	nt.ignore(pb.Replicate)
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.Propose, Entries: []pb.Entry{{}}})
+	lead.electionTick = lead.electionTimeout - 1
+	tickLeader(nt, lead, 1)
+	if lead.state != leader || lead.leaderTransfering() {
+		t.Errorf("leadership transferred to slow node")
+	}
+}
@@ -159,6 +159,7 @@ type IManagedStateMachine interface {
 	Offloaded(From)
 	Loaded(From)
 	ConcurrentSnapshot() bool
+	Busy() bool
 }
 
 // ManagedStateMachineFactory is the factory function type for creating an
@@ -327,6 +328,12 @@ func (ds *NativeStateMachine) ConcurrentSnapshot() bool {
 	return ds.sm.ConcurrentSnapshot()
 }
 
+// Busy returns a boolean value indicating whether the managed state machine
+// asked for committed entries to be delivered at a lower rate.
+func (ds *NativeStateMachine) Busy() bool {
+	return ds.sm.Busy()
+}
+
 // Update updates the data store.
 func (ds *NativeStateMachine) Update(session *Session,
 	seriesID uint64, index uint64, term uint64, data []byte) uint64 {
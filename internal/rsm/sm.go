@@ -16,6 +16,7 @@ package rsm
 
 import (
 	"io"
+	"sync"
 
 	sm "github.com/lni/dragonboat/statemachine"
 )
@@ -34,6 +35,7 @@ type IStateMachine interface {
 	Close()
 	GetHash() uint64
 	ConcurrentSnapshot() bool
+	Busy() bool
 }
 
 // RegularStateMachine is a regular state machine not capable of taking
@@ -42,6 +44,16 @@ type RegularStateMachine struct {
 	sm sm.IStateMachine
 }
 
+// NewStateMachineAdapter returns the adapter for the specified IStateMachine
+// instance, a CloneableStateMachine is returned when it implements the
+// ICloneableStateMachine interface.
+func NewStateMachineAdapter(s sm.IStateMachine) IStateMachine {
+	if cs, ok := s.(sm.ICloneableStateMachine); ok {
+		return NewCloneableStateMachine(cs)
+	}
+	return NewRegularStateMachine(s)
+}
+
 // NewRegularStateMachine creates a new RegularStateMachine instance.
 func NewRegularStateMachine(sm sm.IStateMachine) *RegularStateMachine {
 	return &RegularStateMachine{sm: sm}
@@ -100,6 +112,12 @@ func (sm *RegularStateMachine) ConcurrentSnapshot() bool {
 	return false
 }
 
+// Busy returns a boolean value indicating whether the state machine asked for
+// committed entries to be delivered at a lower rate.
+func (sm *RegularStateMachine) Busy() bool {
+	return isBusy(sm.sm)
+}
+
 // ConcurrentStateMachine is an IStateMachine type capable of taking concurrent
 // snapshots.
 type ConcurrentStateMachine struct {
@@ -155,3 +173,104 @@ func (sm *ConcurrentStateMachine) GetHash() uint64 {
 func (sm *ConcurrentStateMachine) ConcurrentSnapshot() bool {
 	return true
 }
+
+// Busy returns a boolean value indicating whether the state machine asked for
+// committed entries to be delivered at a lower rate.
+func (sm *ConcurrentStateMachine) Busy() bool {
+	return isBusy(sm.sm)
+}
+
+// CloneableStateMachine is an IStateMachine type capable of taking concurrent
+// snapshots by saving snapshots from copies of a regular state machine. The
+// underlying state machine is guarded by a sync.RWMutex as concurrent lookups
+// are not supported by regular state machines.
+type CloneableStateMachine struct {
+	mu sync.RWMutex
+	sm sm.ICloneableStateMachine
+}
+
+// NewCloneableStateMachine creates a new CloneableStateMachine instance.
+func NewCloneableStateMachine(
+	sm sm.ICloneableStateMachine) *CloneableStateMachine {
+	return &CloneableStateMachine{sm: sm}
+}
+
+// Update updates the state machine.
+func (sm *CloneableStateMachine) Update(entries []sm.Entry) []sm.Entry {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for idx := range entries {
+		entries[idx].Result = sm.sm.Update(entries[idx].Cmd)
+	}
+	return entries
+}
+
+// Lookup queries the state machine.
+func (sm *CloneableStateMachine) Lookup(query []byte) ([]byte, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.sm.Lookup(query), nil
+}
+
+// PrepareSnapshot makes preparations for taking concurrent snapshot by
+// creating a copy of the state machine.
+func (sm *CloneableStateMachine) PrepareSnapshot() (interface{}, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.sm.Clone(), nil
+}
+
+// SaveSnapshot saves the snapshot from the copy created by PrepareSnapshot.
+func (sm *CloneableStateMachine) SaveSnapshot(ctx interface{},
+	w io.Writer, fc sm.ISnapshotFileCollection,
+	stopc <-chan struct{}) (uint64, error) {
+	return saveClonedSnapshot(ctx, w, fc, stopc)
+}
+
+func saveClonedSnapshot(ctx interface{},
+	w io.Writer, fc sm.ISnapshotFileCollection,
+	stopc <-chan struct{}) (uint64, error) {
+	return ctx.(sm.IStateMachine).SaveSnapshot(w, fc, stopc)
+}
+
+// RecoverFromSnapshot recovers the state machine from a snapshot.
+func (sm *CloneableStateMachine) RecoverFromSnapshot(r io.Reader,
+	fs []sm.SnapshotFile, stopc <-chan struct{}) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.sm.RecoverFromSnapshot(r, fs, stopc)
+}
+
+// Close closes the state machine.
+func (sm *CloneableStateMachine) Close() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.sm.Close()
+}
+
+// GetHash returns the uint64 hash value representing the state of a state
+// machine.
+func (sm *CloneableStateMachine) GetHash() uint64 {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.sm.GetHash()
+}
+
+// ConcurrentSnapshot returns a boolean flag indicating whether the state
+// machine is capable of taking concurrent snapshot.
+func (sm *CloneableStateMachine) ConcurrentSnapshot() bool {
+	return true
+}
+
+// Busy returns a boolean value indicating whether the state machine asked for
+// committed entries to be delivered at a lower rate.
+func (sm *CloneableStateMachine) Busy() bool {
+	return isBusy(sm.sm)
+}
+
+func isBusy(s interface{}) bool {
+	if b, ok := s.(sm.IBusyStateMachine); ok {
+		return b.Busy()
+	}
+	return false
+}
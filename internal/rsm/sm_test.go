@@ -0,0 +1,139 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rsm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+type counterSM struct {
+	count uint64
+}
+
+func (s *counterSM) Update(data []byte) uint64 {
+	s.count++
+	return s.count
+}
+
+func (s *counterSM) Lookup(query []byte) []byte {
+	result := make([]byte, 8)
+	binary.BigEndian.PutUint64(result, s.count)
+	return result
+}
+
+func (s *counterSM) SaveSnapshot(w io.Writer,
+	fc sm.ISnapshotFileCollection, done <-chan struct{}) (uint64, error) {
+	data := s.Lookup(nil)
+	_, err := w.Write(data)
+	return uint64(len(data)), err
+}
+
+func (s *counterSM) RecoverFromSnapshot(r io.Reader,
+	files []sm.SnapshotFile, done <-chan struct{}) error {
+	data := make([]byte, 8)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	s.count = binary.BigEndian.Uint64(data)
+	return nil
+}
+
+func (s *counterSM) Close() {}
+
+func (s *counterSM) GetHash() uint64 {
+	return s.count
+}
+
+type busyCloneableSM struct {
+	counterSM
+	busy bool
+}
+
+func (s *busyCloneableSM) Busy() bool {
+	return s.busy
+}
+
+func cloneCounterSM(s sm.IStateMachine) sm.IStateMachine {
+	return &counterSM{count: s.(*counterSM).count}
+}
+
+func TestStateMachineAdapterIsSelectedByType(t *testing.T) {
+	if _, ok := NewStateMachineAdapter(&counterSM{}).(*RegularStateMachine); !ok {
+		t.Errorf("regular state machine adapter not used")
+	}
+	s := sm.NewCloneableStateMachine(&counterSM{}, cloneCounterSM)
+	a := NewStateMachineAdapter(s)
+	if _, ok := a.(*CloneableStateMachine); !ok {
+		t.Errorf("cloneable state machine adapter not used")
+	}
+	if !a.ConcurrentSnapshot() {
+		t.Errorf("concurrent snapshot not supported")
+	}
+}
+
+func TestCloneableStateMachineSavesSnapshotFromClone(t *testing.T) {
+	a := NewCloneableStateMachine(
+		sm.NewCloneableStateMachine(&counterSM{}, cloneCounterSM))
+	entries := a.Update([]sm.Entry{{Index: 1}, {Index: 2}, {Index: 3}})
+	for idx, e := range entries {
+		if e.Result != uint64(idx+1) {
+			t.Errorf("unexpected result %d", e.Result)
+		}
+	}
+	ctx, err := a.PrepareSnapshot()
+	if err != nil {
+		t.Fatalf("prepare snapshot failed %v", err)
+	}
+	// updates made after PrepareSnapshot are not included in the snapshot
+	a.Update([]sm.Entry{{Index: 4}})
+	buf := bytes.NewBuffer(nil)
+	if _, err := a.SaveSnapshot(ctx, buf, nil, nil); err != nil {
+		t.Fatalf("save snapshot failed %v", err)
+	}
+	if a.GetHash() != 4 {
+		t.Errorf("unexpected hash %d", a.GetHash())
+	}
+	r := NewCloneableStateMachine(
+		sm.NewCloneableStateMachine(&counterSM{}, cloneCounterSM))
+	if err := r.RecoverFromSnapshot(buf, nil, nil); err != nil {
+		t.Fatalf("recover from snapshot failed %v", err)
+	}
+	result, err := r.Lookup(nil)
+	if err != nil || binary.BigEndian.Uint64(result) != 3 {
+		t.Errorf("unexpected lookup result %v, %v", result, err)
+	}
+}
+
+func TestCloneableStateMachineForwardsBusy(t *testing.T) {
+	s := &busyCloneableSM{}
+	a := NewCloneableStateMachine(sm.NewCloneableStateMachine(s, cloneCounterSM))
+	if a.Busy() {
+		t.Errorf("unexpectedly busy")
+	}
+	s.busy = true
+	if !a.Busy() {
+		t.Errorf("busy not forwarded")
+	}
+	a = NewCloneableStateMachine(
+		sm.NewCloneableStateMachine(&counterSM{}, cloneCounterSM))
+	if a.Busy() {
+		t.Errorf("unexpectedly busy")
+	}
+}
@@ -355,7 +355,14 @@ func (s *StateMachine) GetMembershipHash() uint64 {
 	return binary.LittleEndian.Uint64(md5sum[:8])
 }
 
+// Busy returns a boolean value indicating whether the state machine asked for
+// committed entries to be delivered at a lower rate.
+func (s *StateMachine) Busy() bool {
+	return s.sm.Busy()
+}
+
 // Handle pulls the committed record and apply it if there is any available.
+// Only one committed record is applied when the state machine is busy.
 func (s *StateMachine) Handle(batch []Commit, entries []sm.Entry) (Commit, bool) {
 	processed := 0
 	batch = batch[:0]
@@ -367,7 +374,7 @@ func (s *StateMachine) Handle(batch []Commit, entries []sm.Entry) (Commit, bool)
 		}
 		batch = append(batch, rec)
 		processed++
-		done := false
+		done := s.Busy()
 		for !done {
 			select {
 			case rec := <-s.commitC:
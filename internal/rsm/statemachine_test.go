@@ -268,6 +268,56 @@ func TestUpdatesCanBeBatched(t *testing.T) {
 	}
 }
 
+type busyCounterSM struct {
+	counterSM
+	busy bool
+}
+
+func (s *busyCounterSM) Busy() bool {
+	return s.busy
+}
+
+func TestBusyStateMachineHandlesOneCommitAtATime(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	createTestDir()
+	defer removeTestDir()
+	store := &busyCounterSM{busy: true}
+	ds := NewNativeStateMachine(&RegularStateMachine{sm: store}, make(chan struct{}))
+	nodeProxy := newTestNodeProxy()
+	snapshotter := newTestSnapshotter()
+	sm := NewStateMachine(ds, snapshotter, false, nodeProxy)
+	if !sm.Busy() {
+		t.Fatalf("busy state not reported")
+	}
+	sm.index = 234
+	for i := uint64(235); i < 238; i++ {
+		sm.CommitC() <- Commit{
+			Entries: []pb.Entry{
+				{
+					ClientID: 123,
+					SeriesID: client.NoOPSeriesID,
+					Index:    i,
+					Term:     1,
+					Cmd:      []byte("test-data"),
+				},
+			},
+		}
+	}
+	batch := make([]Commit, 0, 8)
+	sm.Handle(batch, nil)
+	if sm.GetLastApplied() != 235 {
+		t.Errorf("last applied %d, want 235", sm.GetLastApplied())
+	}
+	store.busy = false
+	sm.Handle(batch, nil)
+	if sm.GetLastApplied() != 237 {
+		t.Errorf("last applied %d, want 237", sm.GetLastApplied())
+	}
+	if store.count != 3 {
+		t.Errorf("update count %d, want 3", store.count)
+	}
+}
+
 func TestUpdatesNotBatchedWhenNotAllNoOPUpdates(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	createTestDir()
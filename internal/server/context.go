@@ -15,8 +15,11 @@
 package server
 
 import (
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -35,7 +38,9 @@ var (
 )
 
 const (
-	dragonboatAddressFilename = "dragonboat.address"
+	dragonboatAddressFilename    = "dragonboat.address"
+	dragonboatNodeHostIDFilename = "dragonboat.nodehostid"
+	nodeHostIDLength             = 16
 )
 
 // Context is the server context for NodeHost.
@@ -58,9 +63,34 @@ func NewContext(nhConfig config.NodeHostConfig) *Context {
 		plog.Panicf("failed to get hostname %v", err)
 	}
 	s.hostname = hostname
+	if nhConfig.AddressByNodeHostID && len(nhConfig.NodeHostID) > 0 {
+		s.hostname = nhConfig.NodeHostID
+		s.checkHostnameDirs(hostname)
+	}
 	return s
 }
 
+// checkHostnameDirs panics when data dirs named after the hostname exist but
+// those named after the NodeHost ID do not, as starting with empty dirs would
+// silently discard all existing data.
+func (sc *Context) checkHostnameDirs(hostname string) {
+	if hostname == sc.hostname {
+		return
+	}
+	dirs := strings.Split(sc.nhConfig.NodeHostDir, ":")
+	if len(sc.nhConfig.WALDir) > 0 {
+		dirs = append(dirs, strings.Split(sc.nhConfig.WALDir, ":")...)
+	}
+	for _, dir := range dirs {
+		hd := filepath.Join(dir, hostname)
+		nd := filepath.Join(dir, sc.hostname)
+		if fileutil.Exist(hd) && !fileutil.Exist(nd) {
+			plog.Panicf("found data dir %s named after the hostname, rename it "+
+				"to %s to keep using its data", hd, nd)
+		}
+	}
+}
+
 // Stop stops the context.
 func (sc *Context) Stop() {
 }
@@ -175,6 +205,48 @@ func (sc *Context) CheckNodeHostDir(did uint64, addr string) {
 	}
 }
 
+// GetNodeHostID returns the NodeHost ID saved in the NodeHost dirs. When no
+// NodeHost ID has been saved yet, the NodeHostID specified in the NodeHost
+// config or a randomly generated one is saved and returned.
+func (sc *Context) GetNodeHostID(did uint64) string {
+	dirs, lldirs := sc.GetLogDBDirs(did)
+	all := make([]string, 0, len(dirs)+len(lldirs))
+	all = append(all, dirs...)
+	all = append(all, lldirs...)
+	id := ""
+	for _, dir := range all {
+		v, err := readNodeHostID(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			panic(err)
+		}
+		if len(id) > 0 && v != id {
+			plog.Panicf("inconsistent NodeHost IDs, %s vs %s", id, v)
+		}
+		id = v
+	}
+	if len(sc.nhConfig.NodeHostID) > 0 {
+		if len(id) > 0 && id != sc.nhConfig.NodeHostID {
+			plog.Panicf("nodehost data dirs belong to different nodehost %s", id)
+		}
+		id = sc.nhConfig.NodeHostID
+	}
+	if len(id) == 0 {
+		id = newNodeHostID()
+	}
+	for _, dir := range all {
+		fp := filepath.Join(dir, dragonboatNodeHostIDFilename)
+		if !fileutil.Exist(fp) {
+			if err := saveNodeHostID(dir, id); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return id
+}
+
 func (sc *Context) getDeploymentIDSubDirName(did uint64) string {
 	return fmt.Sprintf("%020d", did)
 }
@@ -203,8 +275,17 @@ func (sc *Context) checkDirAddressMatch(dir string,
 			panic(err)
 		}
 		if !se(string(status.Address), addr) {
-			plog.Panicf("nodehost data dirs belong to different nodehost %s",
-				strings.TrimSpace(status.Address))
+			if !sc.nhConfig.AddressByNodeHostID {
+				plog.Panicf("nodehost data dirs belong to different nodehost %s",
+					strings.TrimSpace(status.Address))
+			}
+			plog.Warningf("RaftAddress changed from %s to %s",
+				strings.TrimSpace(status.Address), addr)
+			status.Address = addr
+			err := fileutil.CreateFlagFile(dir, dragonboatAddressFilename, &status)
+			if err != nil {
+				panic(err)
+			}
 		}
 		if status.BinVer != raftio.LogDBBinVersion {
 			plog.Panicf("binary compatibility version, data dir %d, software %d",
@@ -215,3 +296,48 @@ func (sc *Context) checkDirAddressMatch(dir string,
 		}
 	}
 }
+
+func newNodeHostID() string {
+	v := make([]byte, nodeHostIDLength)
+	if _, err := rand.Read(v); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(v)
+}
+
+func readNodeHostID(dir string) (string, error) {
+	fp := filepath.Join(dir, dragonboatNodeHostIDFilename)
+	data, err := ioutil.ReadFile(filepath.Clean(fp))
+	if err != nil {
+		return "", err
+	}
+	id := strings.TrimSpace(string(data))
+	if len(id) == 0 {
+		panic("corrupted nodehost id file")
+	}
+	return id, nil
+}
+
+func saveNodeHostID(dir string, id string) error {
+	fp := filepath.Join(dir, dragonboatNodeHostIDFilename)
+	tmp := fp + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(id); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, fp); err != nil {
+		return err
+	}
+	return fileutil.SyncDir(dir)
+}
@@ -16,6 +16,7 @@ package server
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/lni/dragonboat/config"
@@ -92,3 +93,90 @@ func testNodeHostDirectoryDetectsMismatches(t *testing.T, addr string, binVer ui
 	}
 	ctx.CheckNodeHostDir(testDeploymentID, testAddress)
 }
+
+func TestNodeHostDirectoryAllowsAddressChangeWhenAddressByNodeHostID(t *testing.T) {
+	defer os.RemoveAll(singleNodeHostTestDir)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("panic not expected")
+		}
+	}()
+	c := getTestNodeHostConfig()
+	c.AddressByNodeHostID = true
+	ctx := NewContext(c)
+	ctx.CreateNodeHostDir(testDeploymentID)
+	ctx.CheckNodeHostDir(testDeploymentID, "localhost:2222")
+	ctx.CheckNodeHostDir(testDeploymentID, testAddress)
+	dirs, _ := ctx.GetLogDBDirs(testDeploymentID)
+	status := raftpb.RaftDataStatus{}
+	err := fileutil.GetFlagFileContent(dirs[0], dragonboatAddressFilename, &status)
+	if err != nil {
+		t.Fatalf("failed to get flag file content %v", err)
+	}
+	if status.Address != testAddress {
+		t.Errorf("address not updated, %s", status.Address)
+	}
+}
+
+func TestNodeHostIDIsPersistent(t *testing.T) {
+	defer os.RemoveAll(singleNodeHostTestDir)
+	c := getTestNodeHostConfig()
+	ctx := NewContext(c)
+	ctx.CreateNodeHostDir(testDeploymentID)
+	id := ctx.GetNodeHostID(testDeploymentID)
+	if len(id) != 2*nodeHostIDLength {
+		t.Errorf("unexpected NodeHost ID %s", id)
+	}
+	c.RaftAddress = "localhost:2222"
+	ctx = NewContext(c)
+	if v := ctx.GetNodeHostID(testDeploymentID); v != id {
+		t.Errorf("NodeHost ID changed, %s vs %s", v, id)
+	}
+}
+
+func TestSpecifiedNodeHostIDIsUsed(t *testing.T) {
+	defer os.RemoveAll(singleNodeHostTestDir)
+	c := getTestNodeHostConfig()
+	c.NodeHostID = "nodehost-1"
+	c.AddressByNodeHostID = true
+	ctx := NewContext(c)
+	ctx.CreateNodeHostDir(testDeploymentID)
+	if v := ctx.GetNodeHostID(testDeploymentID); v != c.NodeHostID {
+		t.Errorf("got %s, want %s", v, c.NodeHostID)
+	}
+	dirs, _ := ctx.GetLogDBDirs(testDeploymentID)
+	if !strings.Contains(dirs[0], c.NodeHostID) {
+		t.Errorf("NodeHost ID not used in dir name, %s", dirs[0])
+	}
+}
+
+func TestHostnameNamedDirIsDetectedWhenUsingNodeHostID(t *testing.T) {
+	defer os.RemoveAll(singleNodeHostTestDir)
+	c := getTestNodeHostConfig()
+	ctx := NewContext(c)
+	ctx.CreateNodeHostDir(testDeploymentID)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("didn't panic when hostname named dir exists")
+		}
+	}()
+	c.NodeHostID = "nodehost-1"
+	c.AddressByNodeHostID = true
+	NewContext(c)
+}
+
+func TestNodeHostIDMismatchIsDetected(t *testing.T) {
+	defer os.RemoveAll(singleNodeHostTestDir)
+	c := getTestNodeHostConfig()
+	ctx := NewContext(c)
+	ctx.CreateNodeHostDir(testDeploymentID)
+	ctx.GetNodeHostID(testDeploymentID)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("didn't panic when NodeHost ID does not match")
+		}
+	}()
+	c.NodeHostID = "nodehost-1"
+	ctx = NewContext(c)
+	ctx.GetNodeHostID(testDeploymentID)
+}
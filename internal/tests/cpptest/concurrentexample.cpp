@@ -0,0 +1,106 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+#include <cstddef>
+#include <cstring>
+#include "concurrentexample.h"
+
+HelloWorldConcurrentStateMachine::HelloWorldConcurrentStateMachine(
+  uint64_t clusterID, uint64_t nodeID) noexcept
+  : dragonboat::ConcurrentStateMachine(clusterID, nodeID), update_count_(0)
+{
+}
+
+HelloWorldConcurrentStateMachine::~HelloWorldConcurrentStateMachine()
+{
+}
+
+void HelloWorldConcurrentStateMachine::batchedUpdate(Entry *entries,
+  size_t size) noexcept
+{
+  // increase the update_count_ value for each entry
+  for (size_t i = 0; i < size; i++) {
+    entries[i].result = (uint64_t)(++update_count_);
+  }
+}
+
+LookupResult HelloWorldConcurrentStateMachine::lookup(
+  const dragonboat::Byte *data, size_t sz) const noexcept
+{
+  // return the update_count_ value
+  LookupResult r;
+  r.result = new char[sizeof(int)];
+  r.size = sizeof(int);
+  *((int *)r.result) = update_count_.load();
+  return r;
+}
+
+uint64_t HelloWorldConcurrentStateMachine::getHash() const noexcept
+{
+  return (uint64_t)update_count_.load();
+}
+
+PrepareSnapshotResult HelloWorldConcurrentStateMachine::prepareSnapshot()
+  const noexcept
+{
+  // the context is a copy of the update_count_ value
+  PrepareSnapshotResult r;
+  r.result = new int(update_count_.load());
+  r.error = SNAPSHOT_OK;
+  return r;
+}
+
+SnapshotResult HelloWorldConcurrentStateMachine::saveSnapshot(
+  const void *context, dragonboat::SnapshotWriter *writer,
+  dragonboat::SnapshotFileCollection *collection,
+  const dragonboat::DoneChan &done) const noexcept
+{
+  SnapshotResult r;
+  dragonboat::IOResult ret;
+  r.error = SNAPSHOT_OK;
+  r.size = 0;
+  ret = writer->Write((const dragonboat::Byte *)context, sizeof(int));
+  if (ret.size != sizeof(int)) {
+    r.error = FAILED_TO_SAVE_SNAPSHOT;
+    return r;
+  }
+  r.size = sizeof(int);
+  return r;
+}
+
+int HelloWorldConcurrentStateMachine::recoverFromSnapshot(
+  dragonboat::SnapshotReader *reader,
+  const std::vector<dragonboat::SnapshotFile> &files,
+  const dragonboat::DoneChan &done) noexcept
+{
+  dragonboat::IOResult ret;
+  int count;
+  ret = reader->Read((dragonboat::Byte *)&count, sizeof(int));
+  if (ret.size != sizeof(int)) {
+    return FAILED_TO_RECOVER_FROM_SNAPSHOT;
+  }
+  update_count_ = count;
+  return SNAPSHOT_OK;
+}
+
+void HelloWorldConcurrentStateMachine::freePrepareSnapshotResult(
+  void *context) noexcept
+{
+  delete (int *)context;
+}
+
+void HelloWorldConcurrentStateMachine::freeLookupResult(LookupResult r) noexcept
+{
+  delete[] r.result;
+}
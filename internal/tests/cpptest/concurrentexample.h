@@ -0,0 +1,55 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+#ifndef DRAGONBOAT_CONCURRENT_EXAMPLE_STATEMACHINE_H
+#define DRAGONBOAT_CONCURRENT_EXAMPLE_STATEMACHINE_H
+
+#include <atomic>
+#include "dragonboat/statemachine.h"
+
+// HelloWorldConcurrentStateMachine is an example CPP ConcurrentStateMachine.
+// Similar to the HelloWorldStateMachine, it increases the update_count_ member
+// variable for each incoming update request. update_count_ is an atomic
+// integer so lookup() and saveSnapshot() can be safely invoked concurrently
+// with batchedUpdate().
+//
+// See statemachine.h for more details about the ConcurrentStateMachine
+// interface.
+class HelloWorldConcurrentStateMachine : public dragonboat::ConcurrentStateMachine
+{
+  public:
+    HelloWorldConcurrentStateMachine(uint64_t clusterID,
+      uint64_t nodeID) noexcept;
+    ~HelloWorldConcurrentStateMachine();
+  protected:
+    void batchedUpdate(Entry *entries, size_t size) noexcept override;
+    LookupResult lookup(const dragonboat::Byte *data,
+      size_t size) const noexcept override;
+    uint64_t getHash() const noexcept override;
+    PrepareSnapshotResult prepareSnapshot() const noexcept override;
+    SnapshotResult saveSnapshot(const void *context,
+      dragonboat::SnapshotWriter *writer,
+      dragonboat::SnapshotFileCollection *collection,
+      const dragonboat::DoneChan &done) const noexcept override;
+    int recoverFromSnapshot(dragonboat::SnapshotReader *reader,
+      const std::vector<dragonboat::SnapshotFile> &files,
+      const dragonboat::DoneChan &done) noexcept override;
+    void freePrepareSnapshotResult(void *context) noexcept override;
+    void freeLookupResult(LookupResult r) noexcept override;
+  private:
+    DISALLOW_COPY_MOVE_AND_ASSIGN(HelloWorldConcurrentStateMachine);
+    std::atomic<int> update_count_;
+};
+
+#endif // DRAGONBOAT_CONCURRENT_EXAMPLE_STATEMACHINE_H
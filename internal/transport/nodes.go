@@ -22,6 +22,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/lni/dragonboat/config"
 	"github.com/lni/dragonboat/internal/server"
 	"github.com/lni/dragonboat/internal/utils/logutil"
 	"github.com/lni/dragonboat/raftio"
@@ -70,9 +71,13 @@ type record struct {
 }
 
 // Nodes is used to manage all known node addresses in the multi raft system.
-// The transport layer uses this address registry to locate nodes.
+// The transport layer uses this address registry to locate nodes. When a
+// NodeHost ID resolver is specified, node addresses added to the registry are
+// NodeHost IDs, they are resolved to Raft addresses each time the nodes are
+// located.
 type Nodes struct {
 	partitioner server.IPartitioner
+	idResolver  config.NodeHostIDResolverFunc
 	mu          struct {
 		sync.Mutex
 		addr map[raftio.NodeInfo]record
@@ -85,7 +90,14 @@ type Nodes struct {
 
 // NewNodes returns a new Nodes object.
 func NewNodes(streamConnections uint64) *Nodes {
-	n := &Nodes{}
+	return NewNodesWithNodeHostIDResolver(streamConnections, nil)
+}
+
+// NewNodesWithNodeHostIDResolver returns a new Nodes object that uses the
+// specified function to resolve NodeHost IDs to Raft addresses.
+func NewNodesWithNodeHostIDResolver(streamConnections uint64,
+	resolver config.NodeHostIDResolverFunc) *Nodes {
+	n := &Nodes{idResolver: resolver}
 	if streamConnections > 1 {
 		n.partitioner = server.NewFixedPartitioner(streamConnections)
 	}
@@ -111,6 +123,15 @@ func (n *Nodes) AddRemoteAddress(clusterID uint64,
 		n.nmu.nodes[key] = address
 	} else {
 		if v != address {
+			if n.idResolver != nil {
+				// remote NodeHosts are allowed to change their Raft addresses when
+				// they are identified by their NodeHost IDs
+				plog.Infof("address of %s changed, %s:%s",
+					logutil.DescribeNode(clusterID, nodeID), v, address)
+				n.nmu.nodes[key] = address
+				n.nmu.Unlock()
+				return
+			}
 			plog.Panicf("inconsistent addr for %s, %s:%s",
 				logutil.DescribeNode(clusterID, nodeID), v, address)
 		}
@@ -143,6 +164,12 @@ func (n *Nodes) AddNode(clusterID uint64, nodeID uint64, url string) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 	key := raftio.GetNodeInfo(clusterID, nodeID)
+	if n.idResolver != nil {
+		if _, ok := n.mu.addr[key]; !ok {
+			n.mu.addr[key] = record{address: strings.TrimSpace(url)}
+		}
+		return
+	}
 	if v, err := newAddr(url); err != nil {
 		panic(err)
 	} else {
@@ -197,7 +224,16 @@ func (n *Nodes) Resolve(clusterID uint64, nodeID uint64) (string, string, error)
 		if err != nil {
 			return "", "", errors.New("cluster id/node id not found")
 		}
-		n.AddNode(clusterID, nodeID, na)
+		if n.idResolver == nil {
+			n.AddNode(clusterID, nodeID, na)
+		}
+		return na, n.getConnectionKey(na, clusterID), nil
+	}
+	if n.idResolver != nil {
+		na, ok := n.idResolver(addr.address)
+		if !ok {
+			return "", "", errors.New("nodehost id not resolved")
+		}
 		return na, n.getConnectionKey(na, clusterID), nil
 	}
 	return addr.address, addr.key, nil
@@ -210,6 +246,11 @@ func (n *Nodes) ReverseResolve(addr string) []raftio.NodeInfo {
 	defer n.mu.Unlock()
 	affected := make([]raftio.NodeInfo, 0)
 	for k, v := range n.mu.addr {
+		if n.idResolver != nil {
+			if na, ok := n.idResolver(v.address); ok {
+				v.address = na
+			}
+		}
 		altV := ""
 		u, err := url.Parse(v.address)
 		if err == nil {
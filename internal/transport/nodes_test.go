@@ -131,3 +131,51 @@ func TestInvalidAddressWillPanic(t *testing.T) {
 	testInvalidAddressWillPanic(t, "abc")
 	testInvalidAddressWillPanic(t, "abc:67890")
 }
+
+func TestNodeHostIDCanBeResolved(t *testing.T) {
+	addrs := map[string]string{"nh1": "a1:1"}
+	resolver := func(id string) (string, bool) {
+		v, ok := addrs[id]
+		return v, ok
+	}
+	nodes := NewNodesWithNodeHostIDResolver(settings.Soft.StreamConnections,
+		resolver)
+	nodes.AddNode(100, 2, "nh1")
+	nodes.AddNode(100, 3, "nh2")
+	url, _, err := nodes.Resolve(100, 2)
+	if err != nil {
+		t.Fatalf("failed to resolve address %v", err)
+	}
+	if url != "a1:1" {
+		t.Errorf("got %s, want %s", url, "a1:1")
+	}
+	if _, _, err := nodes.Resolve(100, 3); err == nil {
+		t.Errorf("unknown NodeHost ID not reported")
+	}
+	addrs["nh1"] = "a1:2"
+	url, _, err = nodes.Resolve(100, 2)
+	if err != nil {
+		t.Fatalf("failed to resolve address %v", err)
+	}
+	if url != "a1:2" {
+		t.Errorf("got %s, want %s", url, "a1:2")
+	}
+	if v := nodes.ReverseResolve("a1:2"); len(v) != 1 || v[0].NodeID != 2 {
+		t.Errorf("unexpected reverse resolve result %v", v)
+	}
+}
+
+func TestRemoteAddressCanChangeWhenUsingNodeHostID(t *testing.T) {
+	resolver := func(id string) (string, bool) { return "", false }
+	nodes := NewNodesWithNodeHostIDResolver(settings.Soft.StreamConnections,
+		resolver)
+	nodes.AddRemoteAddress(100, 2, "a2:2")
+	nodes.AddRemoteAddress(100, 2, "a2:3")
+	url, _, err := nodes.Resolve(100, 2)
+	if err != nil {
+		t.Fatalf("failed to resolve address %v", err)
+	}
+	if url != "a2:3" {
+		t.Errorf("got %s, want %s", url, "a2:3")
+	}
+}
@@ -17,13 +17,17 @@ package transport
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/tls"
 	"encoding/binary"
 	"errors"
+	"hash"
 	"hash/crc32"
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/lni/dragonboat/config"
@@ -38,6 +42,7 @@ var (
 	// ErrBadMessage is the error returned to indicate the incoming message is
 	// corrupted.
 	ErrBadMessage       = errors.New("invalid message")
+	errBadChecksum      = errors.New("invalid payload checksum")
 	magicNumber         = [2]byte{0xAE, 0x7D}
 	payloadBufferSize   = settings.SnapshotChunkSize + 1024*128
 	tlsHandshackTimeout = 10 * time.Second
@@ -56,6 +61,10 @@ const (
 	requestHeaderSize        = 14
 	raftType          uint16 = 100
 	snapshotType      uint16 = 200
+	// payloads of authenticated messages are followed by HMAC-SHA256 tags
+	authRaftType     uint16 = 101
+	authSnapshotType uint16 = 201
+	authTagSize             = sha256.Size
 )
 
 type requestHeader struct {
@@ -90,7 +99,8 @@ func (h *requestHeader) decode(buf []byte) bool {
 	}
 	binary.BigEndian.PutUint32(buf[6:], incoming)
 	method := binary.BigEndian.Uint16(buf)
-	if method != raftType && method != snapshotType {
+	if method != raftType && method != snapshotType &&
+		method != authRaftType && method != authSnapshotType {
 		plog.Errorf("invalid method type")
 		return false
 	}
@@ -197,7 +207,7 @@ func readMessage(conn net.Conn,
 	}
 	if crc32.ChecksumIEEE(buf) != rheader.crc {
 		plog.Errorf("invalid payload checksum")
-		return requestHeader{}, nil, ErrBadMessage
+		return requestHeader{}, nil, errBadChecksum
 	}
 	return rheader, buf, nil
 }
@@ -217,12 +227,84 @@ func readMagicNumber(conn net.Conn, magicNum []byte) error {
 	return nil
 }
 
+// messageAuth computes and verifies HMAC-SHA256 tags of messages using the
+// TransportSecret shared by all NodeHost instances. Tags don't cover any
+// nonce or sequence number, replayed messages are thus not detected. It is
+// not thread safe.
+type messageAuth struct {
+	mac hash.Hash
+	tag []byte
+}
+
+func newMessageAuth(secret []byte) *messageAuth {
+	if len(secret) == 0 {
+		return nil
+	}
+	return &messageAuth{
+		mac: hmac.New(sha256.New, secret),
+		tag: make([]byte, 0, authTagSize),
+	}
+}
+
+func (a *messageAuth) sum(method uint16, payload []byte) []byte {
+	var m [2]byte
+	binary.BigEndian.PutUint16(m[:], method)
+	a.mac.Reset()
+	if _, err := a.mac.Write(m[:]); err != nil {
+		panic(err)
+	}
+	if _, err := a.mac.Write(payload); err != nil {
+		panic(err)
+	}
+	return a.mac.Sum(a.tag[:0])
+}
+
+// sign appends the tag of the payload to the payload.
+func (a *messageAuth) sign(method uint16, payload []byte) []byte {
+	return append(payload, a.sum(method, payload)...)
+}
+
+// verify checks the tag appended to the payload, the payload without the tag
+// is returned.
+func (a *messageAuth) verify(method uint16, buf []byte) ([]byte, bool) {
+	if len(buf) < authTagSize {
+		return nil, false
+	}
+	payload := buf[:len(buf)-authTagSize]
+	return payload, hmac.Equal(buf[len(payload):], a.sum(method, payload))
+}
+
+// authenticate checks whether the received message is authenticated as
+// required by the local configuration. The payload without the tag is
+// returned when the message is accepted.
+func authenticate(auth *messageAuth,
+	method uint16, buf []byte) ([]byte, bool) {
+	authenticated := method == authRaftType || method == authSnapshotType
+	if auth == nil {
+		if authenticated {
+			plog.Errorf("authenticated message dropped, TransportSecret not set")
+			return nil, false
+		}
+		return buf, true
+	}
+	if !authenticated {
+		plog.Errorf("unauthenticated message dropped")
+		return nil, false
+	}
+	payload, ok := auth.verify(method, buf)
+	if !ok {
+		plog.Errorf("invalid message authentication tag")
+	}
+	return payload, ok
+}
+
 // TCPConnection is the connection used for sending raft messages to remote
 // nodes.
 type TCPConnection struct {
 	conn    net.Conn
 	header  []byte
 	payload []byte
+	auth    *messageAuth
 }
 
 // NewTCPConnection creates and returns a new TCPConnection instance.
@@ -244,7 +326,7 @@ func (c *TCPConnection) Close() {
 // SendMessageBatch sends a raft message batch to remote node.
 func (c *TCPConnection) SendMessageBatch(batch raftpb.MessageBatch) error {
 	header := requestHeader{method: raftType}
-	sz := batch.SizeUpperLimit()
+	sz := batch.SizeUpperLimit() + authTagSize
 	var buf []byte
 	if len(c.payload) < sz {
 		buf = make([]byte, sz)
@@ -255,7 +337,12 @@ func (c *TCPConnection) SendMessageBatch(batch raftpb.MessageBatch) error {
 	if err != nil {
 		panic(err)
 	}
-	return writeMessage(c.conn, header, buf[:n], c.header)
+	buf = buf[:n]
+	if c.auth != nil {
+		header.method = authRaftType
+		buf = c.auth.sign(header.method, buf)
+	}
+	return writeMessage(c.conn, header, buf, c.header)
 }
 
 // TCPSnapshotConnection is the connection for sending raft snapshot chunks to
@@ -263,6 +350,7 @@ func (c *TCPConnection) SendMessageBatch(batch raftpb.MessageBatch) error {
 type TCPSnapshotConnection struct {
 	conn   net.Conn
 	header []byte
+	auth   *messageAuth
 }
 
 // NewTCPSnapshotConnection creates and returns a new snapshot connection.
@@ -284,21 +372,31 @@ func (c *TCPSnapshotConnection) Close() {
 func (c *TCPSnapshotConnection) SendSnapshotChunk(chunk raftpb.SnapshotChunk) error {
 	header := requestHeader{method: snapshotType}
 	sz := chunk.Size()
-	buf := make([]byte, sz)
+	buf := make([]byte, sz+authTagSize)
 	n, err := chunk.MarshalTo(buf)
 	if err != nil {
 		panic(err)
 	}
-	return writeMessage(c.conn, header, buf[:n], c.header)
+	buf = buf[:n]
+	if c.auth != nil {
+		header.method = authSnapshotType
+		buf = c.auth.sign(header.method, buf)
+	}
+	return writeMessage(c.conn, header, buf, c.header)
 }
 
 // TCPTransport is a TCP based RPC module for exchanging raft messages and
-// snapshots between NodeHost instances.
+// snapshots between NodeHost instances. Received messages failing the
+// checksum validation are dropped. When the TransportSecret is set in the
+// NodeHostConfig, messages are authenticated using HMAC-SHA256 and received
+// messages failing the authentication are also dropped.
 type TCPTransport struct {
-	nhConfig       config.NodeHostConfig
-	stopper        *syncutil.Stopper
-	requestHandler raftio.RequestHandler
-	sinkFactory    raftio.ChunkSinkFactory
+	checksumFailures uint64
+	authFailures     uint64
+	nhConfig         config.NodeHostConfig
+	stopper          *syncutil.Stopper
+	requestHandler   raftio.RequestHandler
+	sinkFactory      raftio.ChunkSinkFactory
 }
 
 // NewTCPTransport creates and returns a new TCP transport module.
@@ -369,7 +467,9 @@ func (g *TCPTransport) GetConnection(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	return NewTCPConnection(conn), nil
+	c := NewTCPConnection(conn)
+	c.auth = newMessageAuth(g.nhConfig.TransportSecret)
+	return c, nil
 }
 
 // GetSnapshotConnection returns a new raftio.IConnection for sending raft
@@ -380,7 +480,9 @@ func (g *TCPTransport) GetSnapshotConnection(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	return NewTCPSnapshotConnection(conn), nil
+	c := NewTCPSnapshotConnection(conn)
+	c.auth = newMessageAuth(g.nhConfig.TransportSecret)
+	return c, nil
 }
 
 // Name returns a human readable name of the TCP transport module.
@@ -388,10 +490,20 @@ func (g *TCPTransport) Name() string {
 	return TCPRaftRPCName
 }
 
+// GetMetrics returns the counters of received messages dropped by the TCP
+// transport module.
+func (g *TCPTransport) GetMetrics() raftio.TransportMetrics {
+	return raftio.TransportMetrics{
+		ChecksumFailures:       atomic.LoadUint64(&g.checksumFailures),
+		AuthenticationFailures: atomic.LoadUint64(&g.authFailures),
+	}
+}
+
 func (g *TCPTransport) serveConn(conn net.Conn) {
 	magicNum := make([]byte, len(magicNumber))
 	header := make([]byte, requestHeaderSize)
 	tbuf := make([]byte, payloadBufferSize)
+	auth := newMessageAuth(g.nhConfig.TransportSecret)
 	var chunks raftio.IChunkSink
 	stopper := syncutil.NewStopper()
 	defer func() {
@@ -414,10 +526,23 @@ func (g *TCPTransport) serveConn(conn net.Conn) {
 			}
 		}
 		rheader, buf, err := readMessage(conn, header, tbuf)
+		if err == errBadChecksum {
+			// the message is fully received, drop it and keep the connection
+			atomic.AddUint64(&g.checksumFailures, 1)
+			continue
+		}
 		if err != nil {
+			if err == ErrBadMessage {
+				atomic.AddUint64(&g.checksumFailures, 1)
+			}
 			return
 		}
-		if rheader.method == raftType {
+		buf, ok := authenticate(auth, rheader.method, buf)
+		if !ok {
+			atomic.AddUint64(&g.authFailures, 1)
+			continue
+		}
+		if rheader.method == raftType || rheader.method == authRaftType {
 			batch := raftpb.MessageBatch{}
 			if err := batch.Unmarshal(buf); err != nil {
 				return
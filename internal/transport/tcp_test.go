@@ -15,7 +15,9 @@
 package transport
 
 import (
+	"bytes"
 	"encoding/binary"
+	"net"
 	"reflect"
 	"testing"
 )
@@ -86,3 +88,105 @@ func TestInvalidMethodNameIsReported(t *testing.T) {
 		t.Fatalf("decode did not report invalid method name")
 	}
 }
+
+func TestMessageAuthTagIsVerified(t *testing.T) {
+	auth := newMessageAuth([]byte("secret"))
+	payload := []byte("test-payload")
+	buf := auth.sign(raftType, append([]byte{}, payload...))
+	if len(buf) != len(payload)+authTagSize {
+		t.Fatalf("unexpected size %d", len(buf))
+	}
+	v, ok := auth.verify(raftType, buf)
+	if !ok {
+		t.Fatalf("failed to verify the tag")
+	}
+	if !bytes.Equal(v, payload) {
+		t.Errorf("payload changed")
+	}
+	if _, ok := auth.verify(snapshotType, buf); ok {
+		t.Errorf("method not covered by the tag")
+	}
+	buf[0] = buf[0] + 1
+	if _, ok := auth.verify(raftType, buf); ok {
+		t.Errorf("tampered payload not reported")
+	}
+	if _, ok := auth.verify(raftType, buf[:authTagSize-1]); ok {
+		t.Errorf("short message not reported")
+	}
+	other := newMessageAuth([]byte("other-secret"))
+	buf = other.sign(raftType, append([]byte{}, payload...))
+	if _, ok := auth.verify(raftType, buf); ok {
+		t.Errorf("tag computed using a different secret not reported")
+	}
+}
+
+func TestMessageAuthenticationIsEnforced(t *testing.T) {
+	auth := newMessageAuth([]byte("secret"))
+	if newMessageAuth(nil) != nil {
+		t.Fatalf("unexpected messageAuth")
+	}
+	payload := []byte("test-payload")
+	if _, ok := authenticate(nil, raftType, payload); !ok {
+		t.Errorf("message dropped when TransportSecret is not set")
+	}
+	if _, ok := authenticate(nil, authRaftType, payload); ok {
+		t.Errorf("authenticated message accepted without TransportSecret")
+	}
+	if _, ok := authenticate(auth, raftType, payload); ok {
+		t.Errorf("unauthenticated message accepted")
+	}
+	buf := auth.sign(authRaftType, append([]byte{}, payload...))
+	v, ok := authenticate(auth, authRaftType, buf)
+	if !ok || !bytes.Equal(v, payload) {
+		t.Errorf("authenticated message dropped")
+	}
+}
+
+func TestCorruptedPayloadIsReported(t *testing.T) {
+	tests := []struct {
+		corrupt func([]byte)
+		err     error
+	}{
+		{func(buf []byte) {}, nil},
+		{func(buf []byte) { buf[len(buf)-1]++ }, errBadChecksum},
+		{func(buf []byte) { buf[len(magicNumber)]++ }, ErrBadMessage},
+	}
+	for idx, tt := range tests {
+		payload := []byte("test-payload")
+		sc, rc := net.Pipe()
+		wire := &bytes.Buffer{}
+		go func() {
+			defer sc.Close()
+			h := requestHeader{method: raftType}
+			if err := writeMessage(sc, h, payload,
+				make([]byte, requestHeaderSize)); err != nil {
+				panic(err)
+			}
+		}()
+		if _, err := wire.ReadFrom(rc); err != nil {
+			t.Fatalf("failed to read %v", err)
+		}
+		data := wire.Bytes()
+		tt.corrupt(data)
+		ic, oc := net.Pipe()
+		go func() {
+			defer oc.Close()
+			// the reader stops reading once corruption is detected in the header
+			oc.Write(data)
+		}()
+		magicNum := make([]byte, len(magicNumber))
+		if err := readMagicNumber(ic, magicNum); err != nil {
+			t.Fatalf("%d, failed to read magic number %v", idx, err)
+		}
+		_, buf, err := readMessage(ic,
+			make([]byte, requestHeaderSize), make([]byte, 1024))
+		if err != tt.err {
+			t.Errorf("%d, got %v, want %v", idx, err, tt.err)
+		}
+		if err == nil && !bytes.Equal(buf, payload) {
+			t.Errorf("%d, payload changed", idx)
+		}
+		ic.Close()
+		rc.Close()
+	}
+}
@@ -116,6 +116,7 @@ type ITransport interface {
 	RemoveMessageHandler()
 	ASyncSend(pb.Message) bool
 	ASyncSendSnapshot(pb.Message) bool
+	GetMetrics() raftio.TransportMetrics
 	Stop()
 }
 
@@ -235,6 +236,16 @@ func (t *Transport) GetRaftRPC() raftio.IRaftRPC {
 	return t.raftRPC
 }
 
+// GetMetrics returns the counters of received messages dropped by the Raft
+// RPC module. Zero values are returned when the Raft RPC module does not keep
+// track of dropped messages.
+func (t *Transport) GetMetrics() raftio.TransportMetrics {
+	if m, ok := t.raftRPC.(raftio.IMetricsRaftRPC); ok {
+		return m.GetMetrics()
+	}
+	return raftio.TransportMetrics{}
+}
+
 // SetPreSendMessageBatchHook set the SendMessageBatch hook.
 // This function is only expected to be used in monkey testing.
 func (t *Transport) SetPreSendMessageBatchHook(h SendMessageBatchFunc) {
@@ -0,0 +1,95 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"time"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/client"
+)
+
+// Client accesses the key-value store managed by the specified Raft cluster.
+// All reads are linearizable.
+type Client struct {
+	nh        dragonboat.ISyncRequester
+	clusterID uint64
+	session   *client.Session
+}
+
+// NewClient returns a new Client instance.
+func NewClient(nh dragonboat.ISyncRequester, clusterID uint64) *Client {
+	return &Client{
+		nh:        nh,
+		clusterID: clusterID,
+		session:   nh.GetNoOPSession(clusterID),
+	}
+}
+
+// Put sets the value of the key, the key expires after the specified TTL when
+// it is not 0.
+func (c *Client) Put(ctx context.Context,
+	key []byte, value []byte, ttl time.Duration) error {
+	r := NewPutRequest(key, value, ttl)
+	_, err := c.update(ctx, r)
+	return err
+}
+
+// Delete removes the key, it returns a boolean value indicating whether the
+// key existed.
+func (c *Client) Delete(ctx context.Context, key []byte) (bool, error) {
+	r := NewDeleteRequest(key)
+	result, err := c.update(ctx, r)
+	return result == 1, err
+}
+
+// DeleteRange removes all keys in the range [key, endKey), an empty endKey
+// means no upper bound. It returns the number of removed keys.
+func (c *Client) DeleteRange(ctx context.Context,
+	key []byte, endKey []byte) (uint64, error) {
+	return c.update(ctx, NewDeleteRangeRequest(key, endKey))
+}
+
+// Get returns the value of the key, ErrKeyNotFound is returned when the key
+// does not exist or has expired.
+func (c *Client) Get(ctx context.Context, key []byte) ([]byte, error) {
+	q := NewGetQuery(key)
+	return c.nh.SyncRead(ctx, c.clusterID, q.Encode())
+}
+
+// Range returns at most limit key-value pairs in the range [key, endKey) in
+// ascending key order. An empty endKey means no upper bound, 0 limit means no
+// limit.
+func (c *Client) Range(ctx context.Context,
+	key []byte, endKey []byte, limit uint32) ([]KV, error) {
+	q := NewRangeQuery(key, endKey, limit)
+	data, err := c.nh.SyncRead(ctx, c.clusterID, q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	return DecodeKVs(data)
+}
+
+func (c *Client) update(ctx context.Context, r Request) (uint64, error) {
+	result, err := c.nh.SyncPropose(ctx, c.session, r.Encode())
+	if err != nil {
+		return 0, err
+	}
+	if result == ResultInvalidRequest {
+		return 0, ErrInvalidRequest
+	}
+	return result, nil
+}
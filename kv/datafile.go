@@ -0,0 +1,117 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"os"
+	"sync"
+)
+
+// dataFile is an append only file holding values of the on disk state
+// machine. Values are appended by the update goroutine and concurrently read
+// by lookups and snapshots, each of them holds a reference to the file so a
+// retired file is only closed and removed once it is no longer being read.
+// Nil dataFile instances are used by the in memory state machine.
+type dataFile struct {
+	mu      sync.Mutex
+	f       *os.File
+	path    string
+	size    int64
+	refs    int
+	retired bool
+	closed  bool
+}
+
+func newDataFile(path string) (*dataFile, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &dataFile{f: f, path: path}, nil
+}
+
+// acquire adds a reference to the file, it returns false when the file has
+// already been closed.
+func (d *dataFile) acquire() bool {
+	if d == nil {
+		return true
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return false
+	}
+	d.refs++
+	return true
+}
+
+func (d *dataFile) release() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.refs--
+	d.mayClose()
+}
+
+// retire marks the file as no longer used by the latest state, it is closed
+// and removed once all references are released.
+func (d *dataFile) retire() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.retired = true
+	d.mayClose()
+}
+
+func (d *dataFile) mayClose() {
+	if !d.retired || d.refs > 0 || d.closed {
+		return
+	}
+	d.closed = true
+	if err := d.f.Close(); err != nil {
+		plog.Errorf("failed to close %s, %v", d.path, err)
+	}
+	if err := os.Remove(d.path); err != nil {
+		plog.Errorf("failed to remove %s, %v", d.path, err)
+	}
+}
+
+// append writes the value to the end of the file and returns its offset. It
+// is only invoked by the update goroutine.
+func (d *dataFile) append(value []byte) int64 {
+	offset := d.size
+	if _, err := d.f.WriteAt(value, offset); err != nil {
+		plog.Panicf("failed to write %s, %v", d.path, err)
+	}
+	d.size += int64(len(value))
+	return offset
+}
+
+// read returns the value of the specified item, the caller must hold a
+// reference to the file.
+func (d *dataFile) read(it item) ([]byte, error) {
+	if d == nil {
+		return it.value, nil
+	}
+	value := make([]byte, it.size)
+	if _, err := d.f.ReadAt(value, it.offset); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,312 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package kv implements a replicated key-value store on top of dragonboat.
+
+StateMachine is an IConcurrentStateMachine keeping ordered key-value pairs in
+a persistent treap, lookups and snapshots are served from immutable versions
+of the treap so they never block updates. Values are kept in memory by the
+state machine returned by NewStateMachine, the ones created by the factory
+returned by NewOnDiskStateMachineFactory keep keys in memory and values in a
+data file on disk so large data sets can be stored with modest memory usage.
+The data file only holds a copy of the state and is discarded when the state
+machine is created, the state itself is restored from snapshots and Raft logs
+as usual.
+
+Keys can be set to expire after a TTL. To keep the Update method
+deterministic, each request carries the wall clock time of its proposer, the
+state machine considers the largest time seen so far as the current time. A
+key is expired once that time reaches its expiration time, expired keys are
+excluded from lookups and snapshots and are eventually removed. Clocks of
+proposers are thus expected to be reasonably synchronized.
+
+A typical application starts Raft clusters using
+
+	nh.StartConcurrentCluster(peers, false, kv.NewStateMachine, rc)
+
+and accesses them using a Client.
+*/
+package kv
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/lni/dragonboat/logger"
+)
+
+var (
+	plog = logger.GetLogger("kv")
+)
+
+var (
+	// ErrKeyNotFound indicates that the requested key does not exist or has
+	// expired.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrInvalidRequest indicates that the request can not be decoded or is
+	// invalid, e.g. with an empty key.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrClosed indicates that the state machine has been closed.
+	ErrClosed = errors.New("state machine closed")
+)
+
+const (
+	// ResultInvalidRequest is the Update result of invalid requests.
+	ResultInvalidRequest uint64 = math.MaxUint64
+)
+
+// Op is the type of an update request.
+type Op uint8
+
+const (
+	// Put sets the value of the key, the Update result is 1.
+	Put Op = iota + 1
+	// Delete removes the key, the Update result is 1 when the key existed and
+	// 0 otherwise.
+	Delete
+	// DeleteRange removes all keys in the range [Key, EndKey), an empty EndKey
+	// means no upper bound. The Update result is the number of removed keys.
+	DeleteRange
+)
+
+const (
+	requestHeaderSize = 21
+	queryHeaderSize   = 17
+)
+
+// Request is an update request proposed to the Raft cluster.
+type Request struct {
+	Op Op
+	// Time is the wall clock time of the proposer in nanoseconds since the
+	// Unix epoch.
+	Time int64
+	Key  []byte
+	// Value is the value of Put requests.
+	Value []byte
+	// EndKey is the end of the range of DeleteRange requests.
+	EndKey []byte
+	// ExpireAt is the expiration time of Put requests in nanoseconds since the
+	// Unix epoch, 0 means the key never expires.
+	ExpireAt int64
+}
+
+// NewPutRequest returns a Put request, the key expires after the specified
+// TTL when it is not 0.
+func NewPutRequest(key []byte, value []byte, ttl time.Duration) Request {
+	now := time.Now().UnixNano()
+	r := Request{Op: Put, Time: now, Key: key, Value: value}
+	if ttl > 0 {
+		r.ExpireAt = now + int64(ttl)
+	}
+	return r
+}
+
+// NewDeleteRequest returns a Delete request.
+func NewDeleteRequest(key []byte) Request {
+	return Request{Op: Delete, Time: time.Now().UnixNano(), Key: key}
+}
+
+// NewDeleteRangeRequest returns a DeleteRange request.
+func NewDeleteRangeRequest(key []byte, endKey []byte) Request {
+	return Request{
+		Op:     DeleteRange,
+		Time:   time.Now().UnixNano(),
+		Key:    key,
+		EndKey: endKey,
+	}
+}
+
+// Encode returns the byte slice representation of the request ready to be
+// proposed to a Raft cluster.
+func (r *Request) Encode() []byte {
+	payload := r.Value
+	if r.Op == DeleteRange {
+		payload = r.EndKey
+	}
+	data := make([]byte, requestHeaderSize+len(r.Key)+len(payload))
+	data[0] = byte(r.Op)
+	binary.BigEndian.PutUint64(data[1:], uint64(r.Time))
+	binary.BigEndian.PutUint64(data[9:], uint64(r.ExpireAt))
+	binary.BigEndian.PutUint32(data[17:], uint32(len(r.Key)))
+	copy(data[requestHeaderSize:], r.Key)
+	copy(data[requestHeaderSize+len(r.Key):], payload)
+	return data
+}
+
+// DecodeRequest decodes the proposed command. The returned Request shares
+// the underlying memory with the specified command.
+func DecodeRequest(cmd []byte) (Request, error) {
+	if len(cmd) < requestHeaderSize {
+		return Request{}, ErrInvalidRequest
+	}
+	sz := uint64(binary.BigEndian.Uint32(cmd[17:]))
+	if sz > uint64(len(cmd)-requestHeaderSize) {
+		return Request{}, ErrInvalidRequest
+	}
+	r := Request{
+		Op:       Op(cmd[0]),
+		Time:     int64(binary.BigEndian.Uint64(cmd[1:])),
+		ExpireAt: int64(binary.BigEndian.Uint64(cmd[9:])),
+		Key:      cmd[requestHeaderSize : requestHeaderSize+sz],
+	}
+	payload := cmd[requestHeaderSize+sz:]
+	switch r.Op {
+	case Put:
+		if len(r.Key) == 0 {
+			return Request{}, ErrInvalidRequest
+		}
+		r.Value = payload
+	case Delete:
+		if len(r.Key) == 0 || len(payload) > 0 {
+			return Request{}, ErrInvalidRequest
+		}
+	case DeleteRange:
+		r.EndKey = payload
+	default:
+		return Request{}, ErrInvalidRequest
+	}
+	return r, nil
+}
+
+// QueryOp is the type of a lookup query.
+type QueryOp uint8
+
+const (
+	// Get returns the value of the key.
+	Get QueryOp = iota + 1
+	// Range returns key-value pairs in the range [Key, EndKey) encoded in the
+	// format expected by DecodeKVs, an empty EndKey means no upper bound.
+	Range
+)
+
+// Query is a lookup query.
+type Query struct {
+	Op QueryOp
+	// Time is the wall clock time of the reader in nanoseconds since the Unix
+	// epoch, keys expired at that time are excluded from the result.
+	Time   int64
+	Key    []byte
+	EndKey []byte
+	// Limit is the max number of key-value pairs returned by Range queries, 0
+	// means no limit.
+	Limit uint32
+}
+
+// NewGetQuery returns a Get query.
+func NewGetQuery(key []byte) Query {
+	return Query{Op: Get, Time: time.Now().UnixNano(), Key: key}
+}
+
+// NewRangeQuery returns a Range query.
+func NewRangeQuery(key []byte, endKey []byte, limit uint32) Query {
+	return Query{
+		Op:     Range,
+		Time:   time.Now().UnixNano(),
+		Key:    key,
+		EndKey: endKey,
+		Limit:  limit,
+	}
+}
+
+// Encode returns the byte slice representation of the query ready to be used
+// for reading from a Raft cluster.
+func (q *Query) Encode() []byte {
+	data := make([]byte, queryHeaderSize+len(q.Key)+len(q.EndKey))
+	data[0] = byte(q.Op)
+	binary.BigEndian.PutUint64(data[1:], uint64(q.Time))
+	binary.BigEndian.PutUint32(data[9:], q.Limit)
+	binary.BigEndian.PutUint32(data[13:], uint32(len(q.Key)))
+	copy(data[queryHeaderSize:], q.Key)
+	copy(data[queryHeaderSize+len(q.Key):], q.EndKey)
+	return data
+}
+
+// DecodeQuery decodes the specified query. The returned Query shares the
+// underlying memory with the specified query.
+func DecodeQuery(query []byte) (Query, error) {
+	if len(query) < queryHeaderSize {
+		return Query{}, ErrInvalidRequest
+	}
+	sz := uint64(binary.BigEndian.Uint32(query[13:]))
+	if sz > uint64(len(query)-queryHeaderSize) {
+		return Query{}, ErrInvalidRequest
+	}
+	q := Query{
+		Op:     QueryOp(query[0]),
+		Time:   int64(binary.BigEndian.Uint64(query[1:])),
+		Limit:  binary.BigEndian.Uint32(query[9:]),
+		Key:    query[queryHeaderSize : queryHeaderSize+sz],
+		EndKey: query[queryHeaderSize+sz:],
+	}
+	if q.Op != Get && q.Op != Range {
+		return Query{}, ErrInvalidRequest
+	}
+	return q, nil
+}
+
+// KV is a key-value pair.
+type KV struct {
+	Key   []byte
+	Value []byte
+}
+
+func encodeKVs(kvs []KV) []byte {
+	sz := 0
+	for _, kv := range kvs {
+		sz += 8 + len(kv.Key) + len(kv.Value)
+	}
+	data := make([]byte, 0, sz)
+	var buf [4]byte
+	for _, kv := range kvs {
+		binary.BigEndian.PutUint32(buf[:], uint32(len(kv.Key)))
+		data = append(data, buf[:]...)
+		data = append(data, kv.Key...)
+		binary.BigEndian.PutUint32(buf[:], uint32(len(kv.Value)))
+		data = append(data, buf[:]...)
+		data = append(data, kv.Value...)
+	}
+	return data
+}
+
+// DecodeKVs decodes the result of a Range query.
+func DecodeKVs(data []byte) ([]KV, error) {
+	result := make([]KV, 0)
+	for len(data) > 0 {
+		key, rest, ok := decodeBytes(data)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		value, rest, ok := decodeBytes(rest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		result = append(result, KV{Key: key, Value: value})
+		data = rest
+	}
+	return result, nil
+}
+
+func decodeBytes(data []byte) ([]byte, []byte, bool) {
+	if len(data) < 4 {
+		return nil, nil, false
+	}
+	sz := uint64(binary.BigEndian.Uint32(data))
+	if sz > uint64(len(data)-4) {
+		return nil, nil, false
+	}
+	return data[4 : 4+sz], data[4+sz:], true
+}
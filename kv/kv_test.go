@@ -0,0 +1,406 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/internal/utils/random"
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+const (
+	testDir = "kv_test_data_safe_to_delete"
+)
+
+func put(key string, value string, tm int64, expireAt int64) []byte {
+	r := Request{
+		Op:       Put,
+		Time:     tm,
+		Key:      []byte(key),
+		Value:    []byte(value),
+		ExpireAt: expireAt,
+	}
+	return r.Encode()
+}
+
+func update(s sm.IConcurrentStateMachine, cmds ...[]byte) []uint64 {
+	ents := make([]sm.Entry, 0)
+	for idx, cmd := range cmds {
+		ents = append(ents, sm.Entry{Index: uint64(idx + 1), Cmd: cmd})
+	}
+	results := make([]uint64, 0)
+	for _, e := range s.Update(ents) {
+		results = append(results, e.Result)
+	}
+	return results
+}
+
+func lookup(s sm.IConcurrentStateMachine, q Query) ([]byte, error) {
+	return s.Lookup(q.Encode())
+}
+
+func getRange(t *testing.T, s sm.IConcurrentStateMachine,
+	start string, end string, limit uint32, tm int64) string {
+	data, err := lookup(s, Query{
+		Op:     Range,
+		Time:   tm,
+		Key:    []byte(start),
+		EndKey: []byte(end),
+		Limit:  limit,
+	})
+	if err != nil {
+		t.Fatalf("range lookup failed %v", err)
+	}
+	kvs, err := DecodeKVs(data)
+	if err != nil {
+		t.Fatalf("failed to decode %v", err)
+	}
+	result := ""
+	for _, kv := range kvs {
+		result += fmt.Sprintf("%s=%s,", kv.Key, kv.Value)
+	}
+	return result
+}
+
+func runStateMachineTest(t *testing.T,
+	tf func(t *testing.T, create func() sm.IConcurrentStateMachine)) {
+	t.Run("memory", func(t *testing.T) {
+		tf(t, func() sm.IConcurrentStateMachine {
+			return NewStateMachine(1, 1)
+		})
+	})
+	t.Run("disk", func(t *testing.T) {
+		if err := os.RemoveAll(testDir); err != nil {
+			t.Fatalf("%v", err)
+		}
+		defer os.RemoveAll(testDir)
+		nodeID := uint64(0)
+		f := NewOnDiskStateMachineFactory(testDir)
+		tf(t, func() sm.IConcurrentStateMachine {
+			nodeID++
+			return f(1, nodeID)
+		})
+	})
+}
+
+func TestRequestCanBeEncodedAndDecoded(t *testing.T) {
+	reqs := []Request{
+		{Op: Put, Time: 1, Key: []byte("k"), Value: []byte("v"), ExpireAt: 2},
+		{Op: Delete, Time: 3, Key: []byte("k")},
+		{Op: DeleteRange, Time: 4, Key: []byte("a"), EndKey: []byte("b")},
+		{Op: DeleteRange, Time: 4},
+	}
+	for idx, r := range reqs {
+		decoded, err := DecodeRequest(r.Encode())
+		if err != nil {
+			t.Fatalf("%d, failed to decode %v", idx, err)
+		}
+		if decoded.Op != r.Op || decoded.Time != r.Time ||
+			decoded.ExpireAt != r.ExpireAt ||
+			!bytes.Equal(decoded.Key, r.Key) ||
+			!bytes.Equal(decoded.Value, r.Value) ||
+			!bytes.Equal(decoded.EndKey, r.EndKey) {
+			t.Errorf("%d, got %+v, want %+v", idx, decoded, r)
+		}
+	}
+	invalid := []Request{
+		{Op: Put},
+		{Op: Delete},
+		{Op: Op(100), Key: []byte("k")},
+	}
+	for idx, r := range invalid {
+		if _, err := DecodeRequest(r.Encode()); err != ErrInvalidRequest {
+			t.Errorf("%d, invalid request not reported", idx)
+		}
+	}
+	if _, err := DecodeRequest([]byte("short")); err != ErrInvalidRequest {
+		t.Errorf("short request not reported")
+	}
+}
+
+func TestQueryCanBeEncodedAndDecoded(t *testing.T) {
+	q := NewRangeQuery([]byte("a"), []byte("z"), 10)
+	decoded, err := DecodeQuery(q.Encode())
+	if err != nil {
+		t.Fatalf("failed to decode %v", err)
+	}
+	if decoded.Op != q.Op || decoded.Time != q.Time || decoded.Limit != 10 ||
+		!bytes.Equal(decoded.Key, q.Key) || !bytes.Equal(decoded.EndKey, q.EndKey) {
+		t.Errorf("got %+v, want %+v", decoded, q)
+	}
+	if _, err := DecodeQuery([]byte{1, 2, 3}); err != ErrInvalidRequest {
+		t.Errorf("short query not reported")
+	}
+}
+
+func TestUpdateAndLookup(t *testing.T) {
+	runStateMachineTest(t, func(t *testing.T,
+		create func() sm.IConcurrentStateMachine) {
+		s := create()
+		defer s.Close()
+		del := NewDeleteRequest([]byte("b"))
+		delRange := Request{Op: DeleteRange, Key: []byte("c"), EndKey: []byte("e")}
+		results := update(s, put("a", "1", 1, 0), put("b", "2", 1, 0),
+			put("c", "3", 1, 0), put("d", "4", 1, 0), put("e", "5", 1, 0),
+			put("a", "6", 1, 0), del.Encode(), del.Encode(), delRange.Encode(),
+			[]byte("invalid"))
+		expected := []uint64{1, 1, 1, 1, 1, 1, 1, 0, 2, ResultInvalidRequest}
+		for idx, r := range results {
+			if r != expected[idx] {
+				t.Errorf("%d, got result %d, want %d", idx, r, expected[idx])
+			}
+		}
+		v, err := lookup(s, NewGetQuery([]byte("a")))
+		if err != nil || string(v) != "6" {
+			t.Errorf("unexpected value %s, %v", v, err)
+		}
+		if _, err := lookup(s, NewGetQuery([]byte("b"))); err != ErrKeyNotFound {
+			t.Errorf("unexpected error %v", err)
+		}
+		if r := getRange(t, s, "", "", 0, 0); r != "a=6,e=5," {
+			t.Errorf("unexpected range result %s", r)
+		}
+		update(s, put("b", "7", 1, 0), put("c", "8", 1, 0))
+		if r := getRange(t, s, "b", "", 2, 0); r != "b=7,c=8," {
+			t.Errorf("unexpected range result %s", r)
+		}
+		if r := getRange(t, s, "b", "e", 0, 0); r != "b=7,c=8," {
+			t.Errorf("unexpected range result %s", r)
+		}
+	})
+}
+
+func TestKeysExpire(t *testing.T) {
+	runStateMachineTest(t, func(t *testing.T,
+		create func() sm.IConcurrentStateMachine) {
+		s := create()
+		defer s.Close()
+		update(s, put("a", "1", 100, 200), put("b", "2", 100, 300),
+			put("c", "3", 100, 0))
+		if r := getRange(t, s, "", "", 0, 100); r != "a=1,b=2,c=3," {
+			t.Errorf("unexpected range result %s", r)
+		}
+		// expired according to the time of the reader
+		if r := getRange(t, s, "", "", 0, 250); r != "b=2,c=3," {
+			t.Errorf("unexpected range result %s", r)
+		}
+		// expired according to the time of the proposer
+		del := Request{Op: Delete, Time: 200, Key: []byte("a")}
+		if r := update(s, del.Encode()); r[0] != 0 {
+			t.Errorf("expired key deleted")
+		}
+		if _, err := lookup(s, Query{Op: Get, Key: []byte("a")}); err != ErrKeyNotFound {
+			t.Errorf("unexpected error %v", err)
+		}
+		// keys with their TTL extended
+		update(s, put("b", "4", 250, 0), put("d", "5", 250, 260))
+		update(s, put("e", "6", 400, 0))
+		if r := getRange(t, s, "", "", 0, 0); r != "b=4,c=3,e=6," {
+			t.Errorf("unexpected range result %s", r)
+		}
+		kvsm := s.(*StateMachine)
+		if len(kvsm.expirations) != 0 {
+			t.Errorf("expirations not removed")
+		}
+		if len(getKeys(kvsm.root, "", "")) != 3 {
+			t.Errorf("expired keys not removed")
+		}
+	})
+}
+
+func TestSnapshotCanBeSavedAndRecovered(t *testing.T) {
+	runStateMachineTest(t, func(t *testing.T,
+		create func() sm.IConcurrentStateMachine) {
+		s := create()
+		defer s.Close()
+		for i := 0; i < 3000; i++ {
+			update(s, put(fmt.Sprintf("key-%04d", i), fmt.Sprintf("v%d", i),
+				int64(i), int64(i+10)*int64(i%2)))
+		}
+		ctx, err := s.PrepareSnapshot()
+		if err != nil {
+			t.Fatalf("failed to prepare snapshot %v", err)
+		}
+		hash := s.GetHash()
+		// updates made after PrepareSnapshot are not included in the snapshot
+		update(s, put("key-0000", "changed", 3000, 0), put("zzz", "z", 3000, 0))
+		buf := bytes.NewBuffer(nil)
+		sz, err := s.SaveSnapshot(ctx, buf, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to save snapshot %v", err)
+		}
+		if sz != uint64(buf.Len()) {
+			t.Errorf("got size %d, want %d", sz, buf.Len())
+		}
+		r := create()
+		defer r.Close()
+		update(r, put("other", "1", 1, 0))
+		if err := r.RecoverFromSnapshot(buf, nil, nil); err != nil {
+			t.Fatalf("failed to recover from snapshot %v", err)
+		}
+		if r.GetHash() != hash {
+			t.Errorf("hash mismatch")
+		}
+		if _, err := lookup(r, NewGetQuery([]byte("other"))); err != ErrKeyNotFound {
+			t.Errorf("unexpected error %v", err)
+		}
+		if _, err := lookup(r, Query{Op: Get, Key: []byte("key-0001")}); err != ErrKeyNotFound {
+			t.Errorf("expired key included in snapshot")
+		}
+		v, err := lookup(r, Query{Op: Get, Key: []byte("key-0000")})
+		if err != nil || string(v) != "v0" {
+			t.Errorf("unexpected value %s, %v", v, err)
+		}
+		if r := getRange(t, r, "key-2996", "key-2997", 0, 0); r != "key-2996=v2996," {
+			t.Errorf("unexpected range result %s", r)
+		}
+	})
+}
+
+func TestSnapshotCanBeStopped(t *testing.T) {
+	s := NewStateMachine(1, 1)
+	for i := 0; i < 3000; i++ {
+		update(s, put(fmt.Sprintf("key-%d", i), "v", 1, 0))
+	}
+	ctx, err := s.PrepareSnapshot()
+	if err != nil {
+		t.Fatalf("failed to prepare snapshot %v", err)
+	}
+	done := make(chan struct{})
+	close(done)
+	_, err = s.SaveSnapshot(ctx, ioutil.Discard, nil, done)
+	if err != sm.ErrSnapshotStopped {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestDataFileIsCompacted(t *testing.T) {
+	if err := os.RemoveAll(testDir); err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer os.RemoveAll(testDir)
+	s := NewOnDiskStateMachineFactory(testDir)(1, 1).(*StateMachine)
+	defer s.Close()
+	for i := 0; i < 100; i++ {
+		update(s, put(fmt.Sprintf("key-%d", i%10), fmt.Sprintf("v%d", i), 1, 0))
+	}
+	ctx, err := s.PrepareSnapshot()
+	if err != nil {
+		t.Fatalf("failed to prepare snapshot %v", err)
+	}
+	hash := s.GetHash()
+	old := s.compact()
+	s.publish()
+	old.retire()
+	if s.file.size != s.live || s.file.size != 30 {
+		t.Errorf("unexpected data file size %d", s.file.size)
+	}
+	if s.GetHash() != hash {
+		t.Errorf("hash changed")
+	}
+	// the old data file is kept until the snapshot is saved
+	files, err := filepath.Glob(filepath.Join(testDir, "1-1", "data-*"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("unexpected data files %v, %v", files, err)
+	}
+	if _, err := s.SaveSnapshot(ctx, ioutil.Discard, nil, nil); err != nil {
+		t.Fatalf("failed to save snapshot %v", err)
+	}
+	files, err = filepath.Glob(filepath.Join(testDir, "1-1", "data-*"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("unexpected data files %v, %v", files, err)
+	}
+	if r := getRange(t, s, "key-8", "", 0, 0); r != "key-8=v98,key-9=v99," {
+		t.Errorf("unexpected range result %s", r)
+	}
+}
+
+func TestLookupFailsAfterClose(t *testing.T) {
+	runStateMachineTest(t, func(t *testing.T,
+		create func() sm.IConcurrentStateMachine) {
+		s := create()
+		update(s, put("a", "1", 1, 0))
+		s.Close()
+		if _, err := lookup(s, NewGetQuery([]byte("a"))); err != ErrClosed {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+}
+
+type testNodeHost struct {
+	sm sm.IConcurrentStateMachine
+}
+
+func (nh *testNodeHost) SyncPropose(ctx context.Context,
+	session *client.Session, cmd []byte) (uint64, error) {
+	return update(nh.sm, cmd)[0], nil
+}
+
+func (nh *testNodeHost) SyncRead(ctx context.Context,
+	clusterID uint64, query []byte) ([]byte, error) {
+	return nh.sm.Lookup(query)
+}
+
+func (nh *testNodeHost) GetNoOPSession(clusterID uint64) *client.Session {
+	return client.NewNoOPSession(clusterID, random.LockGuardedRand)
+}
+
+func TestClient(t *testing.T) {
+	nh := &testNodeHost{sm: NewStateMachine(1, 1)}
+	c := NewClient(nh, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, k := range []string{"a", "b", "c", "d"} {
+		if err := c.Put(ctx, []byte(k), []byte(k+k), 0); err != nil {
+			t.Fatalf("put failed %v", err)
+		}
+	}
+	if err := c.Put(ctx, []byte("e"), []byte("e"), time.Nanosecond); err != nil {
+		t.Fatalf("put failed %v", err)
+	}
+	if err := c.Put(ctx, nil, []byte("v"), 0); err != ErrInvalidRequest {
+		t.Errorf("unexpected error %v", err)
+	}
+	v, err := c.Get(ctx, []byte("a"))
+	if err != nil || string(v) != "aa" {
+		t.Errorf("unexpected value %s, %v", v, err)
+	}
+	if _, err := c.Get(ctx, []byte("e")); err != ErrKeyNotFound {
+		t.Errorf("unexpected error %v", err)
+	}
+	if ok, err := c.Delete(ctx, []byte("a")); err != nil || !ok {
+		t.Errorf("delete failed, %t, %v", ok, err)
+	}
+	if ok, err := c.Delete(ctx, []byte("a")); err != nil || ok {
+		t.Errorf("delete failed, %t, %v", ok, err)
+	}
+	if n, err := c.DeleteRange(ctx, []byte("d"), nil); err != nil || n != 1 {
+		t.Errorf("delete range failed, %d, %v", n, err)
+	}
+	kvs, err := c.Range(ctx, nil, nil, 0)
+	if err != nil || len(kvs) != 2 ||
+		string(kvs[0].Key) != "b" || string(kvs[1].Value) != "cc" {
+		t.Errorf("unexpected range result %v, %v", kvs, err)
+	}
+}
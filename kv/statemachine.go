@@ -0,0 +1,523 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+const (
+	snapshotVersion uint8 = 1
+	// data files smaller than minCompactionSize are never compacted
+	minCompactionSize int64 = 64 * 1024 * 1024
+	// number of entries between checks of the done channel
+	doneCheckInterval = 1024
+)
+
+var (
+	errUnknownSnapshotVersion = errors.New("unknown snapshot version")
+)
+
+// state is a point in time view of the state machine.
+type state struct {
+	root  *node
+	clock int64
+	file  *dataFile
+}
+
+type expiration struct {
+	at  int64
+	key string
+}
+
+type expirations []expiration
+
+func (e expirations) Len() int            { return len(e) }
+func (e expirations) Less(i, j int) bool  { return e[i].at < e[j].at }
+func (e expirations) Swap(i, j int)       { e[i], e[j] = e[j], e[i] }
+func (e *expirations) Push(x interface{}) { *e = append(*e, x.(expiration)) }
+func (e *expirations) Pop() interface{} {
+	old := *e
+	v := old[len(old)-1]
+	*e = old[:len(old)-1]
+	return v
+}
+
+// StateMachine is the replicated key-value store state machine.
+type StateMachine struct {
+	clusterID uint64
+	nodeID    uint64
+	dir       string
+	seq       uint64
+	closed    uint32
+	current   atomic.Value
+	// fields below are only accessed by Update and RecoverFromSnapshot
+	root        *node
+	clock       int64
+	file        *dataFile
+	live        int64
+	expirations expirations
+}
+
+var _ sm.IConcurrentStateMachine = (*StateMachine)(nil)
+
+// NewStateMachine returns a new StateMachine instance keeping all keys and
+// values in memory. It can be used as the factory function specified when
+// calling the StartConcurrentCluster method of NodeHost.
+func NewStateMachine(clusterID uint64,
+	nodeID uint64) sm.IConcurrentStateMachine {
+	s := &StateMachine{clusterID: clusterID, nodeID: nodeID}
+	s.publish()
+	return s
+}
+
+// NewOnDiskStateMachineFactory returns a factory function to be specified
+// when calling the StartConcurrentCluster method of NodeHost. Created
+// StateMachine instances keep values in data files in a subdirectory of the
+// specified directory, existing data files of the same node are removed.
+func NewOnDiskStateMachineFactory(
+	dir string) func(uint64, uint64) sm.IConcurrentStateMachine {
+	return func(clusterID uint64, nodeID uint64) sm.IConcurrentStateMachine {
+		nodeDir := filepath.Join(dir, fmt.Sprintf("%d-%d", clusterID, nodeID))
+		if err := os.RemoveAll(nodeDir); err != nil {
+			plog.Panicf("failed to remove %s, %v", nodeDir, err)
+		}
+		if err := os.MkdirAll(nodeDir, 0755); err != nil {
+			plog.Panicf("failed to create %s, %v", nodeDir, err)
+		}
+		s := &StateMachine{clusterID: clusterID, nodeID: nodeID, dir: nodeDir}
+		s.file = s.mustCreateDataFile()
+		s.publish()
+		return s
+	}
+}
+
+// Update applies the specified update requests.
+func (s *StateMachine) Update(ents []sm.Entry) []sm.Entry {
+	for idx := range ents {
+		ents[idx].Result = s.update(ents[idx].Cmd)
+	}
+	s.expire()
+	retired := s.mayCompact()
+	s.publish()
+	retired.retire()
+	return ents
+}
+
+// Lookup performs the specified Get or Range query.
+func (s *StateMachine) Lookup(query []byte) ([]byte, error) {
+	q, err := DecodeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer st.file.release()
+	now := st.clock
+	if q.Time > now {
+		now = q.Time
+	}
+	if q.Op == Get {
+		it, ok := get(st.root, string(q.Key))
+		if !ok || it.expired(now) {
+			return nil, ErrKeyNotFound
+		}
+		return st.file.read(it)
+	}
+	kvs := make([]KV, 0)
+	ascend(st.root, string(q.Key), string(q.EndKey), func(n *node) bool {
+		if n.item.expired(now) {
+			return true
+		}
+		var value []byte
+		value, err = st.file.read(n.item)
+		if err != nil {
+			return false
+		}
+		kvs = append(kvs, KV{Key: []byte(n.key), Value: value})
+		return q.Limit == 0 || len(kvs) < int(q.Limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encodeKVs(kvs), nil
+}
+
+// PrepareSnapshot returns the current point in time view of the state
+// machine.
+func (s *StateMachine) PrepareSnapshot() (interface{}, error) {
+	return s.acquire()
+}
+
+// SaveSnapshot saves the point in time view returned by PrepareSnapshot.
+func (s *StateMachine) SaveSnapshot(ctx interface{}, w io.Writer,
+	fc sm.ISnapshotFileCollection, done <-chan struct{}) (uint64, error) {
+	st := ctx.(*state)
+	defer st.file.release()
+	cw := &countedWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	header := make([]byte, 9)
+	header[0] = snapshotVersion
+	binary.BigEndian.PutUint64(header[1:], uint64(st.clock))
+	if _, err := bw.Write(header); err != nil {
+		return 0, err
+	}
+	count := 0
+	var err error
+	ascend(st.root, "", "", func(n *node) bool {
+		if n.item.expired(st.clock) {
+			return true
+		}
+		if count++; count%doneCheckInterval == 0 && isDone(done) {
+			err = sm.ErrSnapshotStopped
+			return false
+		}
+		err = writeEntry(bw, st.file, n)
+		return err == nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := bw.WriteByte(0); err != nil {
+		return 0, err
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	return cw.n, nil
+}
+
+// RecoverFromSnapshot recovers the state machine from the specified snapshot.
+func (s *StateMachine) RecoverFromSnapshot(r io.Reader,
+	files []sm.SnapshotFile, done <-chan struct{}) error {
+	br := bufio.NewReader(r)
+	header := make([]byte, 9)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return err
+	}
+	if header[0] != snapshotVersion {
+		return errUnknownSnapshotVersion
+	}
+	var file *dataFile
+	if len(s.dir) > 0 {
+		file = s.mustCreateDataFile()
+	}
+	var root *node
+	var exps expirations
+	for count := 1; ; count++ {
+		if count%doneCheckInterval == 0 && isDone(done) {
+			file.retire()
+			return sm.ErrSnapshotStopped
+		}
+		key, it, ok, err := readEntry(br, file)
+		if err != nil {
+			file.retire()
+			return err
+		}
+		if !ok {
+			break
+		}
+		root, _, _ = insert(root, key, it)
+		if it.expireAt > 0 {
+			exps = append(exps, expiration{at: it.expireAt, key: key})
+		}
+	}
+	heap.Init(&exps)
+	retired := s.file
+	s.root = root
+	s.clock = int64(binary.BigEndian.Uint64(header[1:]))
+	s.file = file
+	s.live = 0
+	if file != nil {
+		s.live = file.size
+	}
+	s.expirations = exps
+	s.publish()
+	retired.retire()
+	return nil
+}
+
+// Close closes the state machine.
+func (s *StateMachine) Close() {
+	if !atomic.CompareAndSwapUint32(&s.closed, 0, 1) {
+		return
+	}
+	s.getState().file.retire()
+}
+
+// GetHash returns the hash of all key-value pairs.
+func (s *StateMachine) GetHash() uint64 {
+	st, err := s.acquire()
+	if err != nil {
+		return 0
+	}
+	defer st.file.release()
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(st.clock))
+	mustWrite(h, buf[:])
+	ascend(st.root, "", "", func(n *node) bool {
+		if n.item.expired(st.clock) {
+			return true
+		}
+		value, err := st.file.read(n.item)
+		if err != nil {
+			plog.Panicf("failed to read value, %v", err)
+		}
+		binary.BigEndian.PutUint64(buf[:], uint64(n.item.expireAt))
+		mustWrite(h, buf[:])
+		mustWrite(h, []byte(n.key))
+		mustWrite(h, value)
+		return true
+	})
+	return h.Sum64()
+}
+
+func (s *StateMachine) update(cmd []byte) uint64 {
+	r, err := DecodeRequest(cmd)
+	if err != nil {
+		return ResultInvalidRequest
+	}
+	if r.Time > s.clock {
+		s.clock = r.Time
+	}
+	key := string(r.Key)
+	switch r.Op {
+	case Put:
+		it := item{expireAt: r.ExpireAt}
+		if s.file != nil {
+			it.offset = s.file.append(r.Value)
+			it.size = int64(len(r.Value))
+			s.live += it.size
+		} else {
+			it.value = append([]byte(nil), r.Value...)
+		}
+		var old item
+		var ok bool
+		s.root, old, ok = insert(s.root, key, it)
+		if ok {
+			s.drop(old)
+		}
+		if it.expireAt > 0 {
+			heap.Push(&s.expirations, expiration{at: it.expireAt, key: key})
+		}
+		return 1
+	case Delete:
+		if s.remove(key) {
+			return 1
+		}
+		return 0
+	case DeleteRange:
+		keys := make([]string, 0)
+		ascend(s.root, key, string(r.EndKey), func(n *node) bool {
+			keys = append(keys, n.key)
+			return true
+		})
+		removed := uint64(0)
+		for _, k := range keys {
+			if s.remove(k) {
+				removed++
+			}
+		}
+		return removed
+	}
+	panic("unknown op")
+}
+
+// remove removes the key, it returns a boolean value indicating whether the
+// key existed and was not expired.
+func (s *StateMachine) remove(key string) bool {
+	var old item
+	var ok bool
+	s.root, old, ok = remove(s.root, key)
+	if !ok {
+		return false
+	}
+	s.drop(old)
+	return !old.expired(s.clock)
+}
+
+func (s *StateMachine) drop(it item) {
+	if s.file != nil {
+		s.live -= it.size
+	}
+}
+
+// expire removes keys expired at the current time.
+func (s *StateMachine) expire() {
+	for len(s.expirations) > 0 && s.expirations[0].at <= s.clock {
+		e := heap.Pop(&s.expirations).(expiration)
+		if it, ok := get(s.root, e.key); ok && it.expireAt == e.at {
+			s.remove(e.key)
+		}
+	}
+}
+
+// mayCompact copies live values to a new data file when more than half of
+// the data file is occupied by removed or overwritten values. The replaced
+// data file is returned, it should be retired once the new state is
+// published.
+func (s *StateMachine) mayCompact() *dataFile {
+	if s.file == nil ||
+		s.file.size < minCompactionSize || s.file.size-s.live <= s.live {
+		return nil
+	}
+	return s.compact()
+}
+
+func (s *StateMachine) compact() *dataFile {
+	old := s.file
+	file := s.mustCreateDataFile()
+	s.root = mapItems(s.root, func(n *node) item {
+		value, err := old.read(n.item)
+		if err != nil {
+			plog.Panicf("failed to read value, %v", err)
+		}
+		it := n.item
+		it.offset = file.append(value)
+		return it
+	})
+	s.file = file
+	s.live = file.size
+	return old
+}
+
+func (s *StateMachine) publish() {
+	s.current.Store(&state{root: s.root, clock: s.clock, file: s.file})
+}
+
+func (s *StateMachine) getState() *state {
+	return s.current.Load().(*state)
+}
+
+// acquire returns the current state with a reference to its data file held.
+func (s *StateMachine) acquire() (*state, error) {
+	for {
+		if atomic.LoadUint32(&s.closed) == 1 {
+			return nil, ErrClosed
+		}
+		// acquire only fails after a newer state has been published
+		st := s.getState()
+		if st.file.acquire() {
+			return st, nil
+		}
+	}
+}
+
+func (s *StateMachine) mustCreateDataFile() *dataFile {
+	s.seq++
+	fp := filepath.Join(s.dir, fmt.Sprintf("data-%d", s.seq))
+	file, err := newDataFile(fp)
+	if err != nil {
+		plog.Panicf("failed to create %s, %v", fp, err)
+	}
+	return file
+}
+
+func writeEntry(w io.Writer, file *dataFile, n *node) error {
+	value, err := file.read(n.item)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 13)
+	buf[0] = 1
+	binary.BigEndian.PutUint64(buf[1:], uint64(n.item.expireAt))
+	binary.BigEndian.PutUint32(buf[9:], uint32(len(n.key)))
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, n.key); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(buf, uint32(len(value)))
+	if _, err := w.Write(buf[:4]); err != nil {
+		return err
+	}
+	_, err = w.Write(value)
+	return err
+}
+
+func readEntry(r *bufio.Reader,
+	file *dataFile) (string, item, bool, error) {
+	marker, err := r.ReadByte()
+	if err != nil {
+		return "", item{}, false, err
+	}
+	if marker == 0 {
+		return "", item{}, false, nil
+	}
+	buf := make([]byte, 12)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", item{}, false, err
+	}
+	it := item{expireAt: int64(binary.BigEndian.Uint64(buf))}
+	key := make([]byte, binary.BigEndian.Uint32(buf[8:]))
+	if _, err := io.ReadFull(r, key); err != nil {
+		return "", item{}, false, err
+	}
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return "", item{}, false, err
+	}
+	value := make([]byte, binary.BigEndian.Uint32(buf))
+	if _, err := io.ReadFull(r, value); err != nil {
+		return "", item{}, false, err
+	}
+	if file != nil {
+		it.offset = file.append(value)
+		it.size = int64(len(value))
+	} else {
+		it.value = value
+	}
+	return string(key), it, true, nil
+}
+
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+func mustWrite(w io.Writer, data []byte) {
+	if _, err := w.Write(data); err != nil {
+		panic(err)
+	}
+}
+
+type countedWriter struct {
+	w io.Writer
+	n uint64
+}
+
+func (c *countedWriter) Write(data []byte) (int, error) {
+	n, err := c.w.Write(data)
+	c.n += uint64(n)
+	return n, err
+}
@@ -0,0 +1,179 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"hash/fnv"
+)
+
+// item is the value of a key, the value is kept in memory or in the data file
+// at the specified offset.
+type item struct {
+	value    []byte
+	offset   int64
+	size     int64
+	expireAt int64
+}
+
+func (i *item) expired(now int64) bool {
+	return i.expireAt > 0 && i.expireAt <= now
+}
+
+// node is a node of a persistent treap. Nodes are never modified once they
+// become reachable from a published root, updates copy the path from the root
+// to the updated node instead, so any root is an immutable point in time view
+// of the key-value pairs.
+type node struct {
+	key      string
+	item     item
+	priority uint64
+	left     *node
+	right    *node
+}
+
+func getPriority(key string) uint64 {
+	h := fnv.New64a()
+	if _, err := h.Write([]byte(key)); err != nil {
+		panic(err)
+	}
+	return h.Sum64()
+}
+
+func (n *node) clone() *node {
+	cn := *n
+	return &cn
+}
+
+func get(n *node, key string) (item, bool) {
+	for n != nil {
+		if key < n.key {
+			n = n.left
+		} else if key > n.key {
+			n = n.right
+		} else {
+			return n.item, true
+		}
+	}
+	return item{}, false
+}
+
+// insert returns the root of a new treap with the key set to the specified
+// item, the old item is returned when the key already exists.
+func insert(n *node, key string, it item) (*node, item, bool) {
+	if n == nil {
+		return &node{key: key, item: it, priority: getPriority(key)}, item{}, false
+	}
+	cn := n.clone()
+	if key < n.key {
+		left, old, ok := insert(n.left, key, it)
+		cn.left = left
+		if left.priority > cn.priority {
+			cn.left = left.right
+			left.right = cn
+			return left, old, ok
+		}
+		return cn, old, ok
+	} else if key > n.key {
+		right, old, ok := insert(n.right, key, it)
+		cn.right = right
+		if right.priority > cn.priority {
+			cn.right = right.left
+			right.left = cn
+			return right, old, ok
+		}
+		return cn, old, ok
+	}
+	cn.item = it
+	return cn, n.item, true
+}
+
+// remove returns the root of a new treap with the key removed, the removed
+// item is returned when the key exists.
+func remove(n *node, key string) (*node, item, bool) {
+	if n == nil {
+		return nil, item{}, false
+	}
+	if key < n.key {
+		left, old, ok := remove(n.left, key)
+		if !ok {
+			return n, old, ok
+		}
+		cn := n.clone()
+		cn.left = left
+		return cn, old, ok
+	} else if key > n.key {
+		right, old, ok := remove(n.right, key)
+		if !ok {
+			return n, old, ok
+		}
+		cn := n.clone()
+		cn.right = right
+		return cn, old, ok
+	}
+	return merge(n.left, n.right), n.item, true
+}
+
+func merge(a *node, b *node) *node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		ca := a.clone()
+		ca.right = merge(a.right, b)
+		return ca
+	}
+	cb := b.clone()
+	cb.left = merge(a, b.left)
+	return cb
+}
+
+// ascend invokes f on nodes with keys in the range [start, end) in ascending
+// order until f returns false, an empty end means no upper bound.
+func ascend(n *node, start string, end string, f func(*node) bool) bool {
+	if n == nil {
+		return true
+	}
+	if n.key > start {
+		if !ascend(n.left, start, end, f) {
+			return false
+		}
+	}
+	inRange := len(end) == 0 || n.key < end
+	if n.key >= start && inRange {
+		if !f(n) {
+			return false
+		}
+	}
+	if inRange {
+		return ascend(n.right, start, end, f)
+	}
+	return true
+}
+
+// mapItems returns a treap with the same shape and keys as the specified one
+// with each item replaced by the result of f.
+func mapItems(n *node, f func(*node) item) *node {
+	if n == nil {
+		return nil
+	}
+	cn := n.clone()
+	cn.item = f(n)
+	cn.left = mapItems(n.left, f)
+	cn.right = mapItems(n.right, f)
+	return cn
+}
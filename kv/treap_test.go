@@ -0,0 +1,151 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func getKeys(n *node, start string, end string) []string {
+	keys := make([]string, 0)
+	ascend(n, start, end, func(n *node) bool {
+		keys = append(keys, n.key)
+		return true
+	})
+	return keys
+}
+
+func checkTreap(t *testing.T, n *node, expected map[string]int64) {
+	keys := make([]string, 0)
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := getKeys(n, "", "")
+	if len(result) != len(keys) {
+		t.Fatalf("got %d keys, want %d", len(result), len(keys))
+	}
+	for idx, key := range keys {
+		if result[idx] != key {
+			t.Fatalf("got key %s, want %s", result[idx], key)
+		}
+		it, ok := get(n, key)
+		if !ok || it.expireAt != expected[key] {
+			t.Fatalf("unexpected item for key %s", key)
+		}
+	}
+	var check func(n *node)
+	check = func(n *node) {
+		if n == nil {
+			return
+		}
+		for _, c := range []*node{n.left, n.right} {
+			if c != nil && c.priority > n.priority {
+				t.Fatalf("heap property violated")
+			}
+		}
+		check(n.left)
+		check(n.right)
+	}
+	check(n)
+}
+
+func TestTreapMatchesMap(t *testing.T) {
+	var root *node
+	expected := make(map[string]int64)
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("key-%d", rand.Intn(500))
+		if rand.Intn(3) == 0 {
+			var ok bool
+			root, _, ok = remove(root, key)
+			_, existed := expected[key]
+			if ok != existed {
+				t.Fatalf("unexpected remove result for %s", key)
+			}
+			delete(expected, key)
+		} else {
+			var old item
+			var ok bool
+			root, old, ok = insert(root, key, item{expireAt: int64(i)})
+			v, existed := expected[key]
+			if ok != existed || (ok && old.expireAt != v) {
+				t.Fatalf("unexpected insert result for %s", key)
+			}
+			expected[key] = int64(i)
+		}
+	}
+	checkTreap(t, root, expected)
+}
+
+func TestTreapVersionsAreImmutable(t *testing.T) {
+	var root *node
+	expected := make(map[string]int64)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%03d", i)
+		root, _, _ = insert(root, key, item{expireAt: 1})
+		expected[key] = 1
+	}
+	v1 := root
+	for i := 0; i < 100; i += 2 {
+		root, _, _ = remove(root, fmt.Sprintf("key-%03d", i))
+	}
+	for i := 1; i < 100; i += 2 {
+		root, _, _ = insert(root, fmt.Sprintf("key-%03d", i), item{expireAt: 2})
+	}
+	root = mapItems(root, func(n *node) item { return item{expireAt: 3} })
+	checkTreap(t, v1, expected)
+	if len(getKeys(root, "", "")) != 50 {
+		t.Errorf("unexpected key count")
+	}
+}
+
+func TestTreapAscend(t *testing.T) {
+	var root *node
+	for i := 0; i < 10; i++ {
+		root, _, _ = insert(root, fmt.Sprintf("%d", i), item{})
+	}
+	tests := []struct {
+		start string
+		end   string
+		keys  string
+	}{
+		{"", "", "0123456789"},
+		{"3", "", "3456789"},
+		{"3", "7", "3456"},
+		{"35", "7", "456"},
+		{"", "0", ""},
+		{"7", "3", ""},
+	}
+	for idx, tt := range tests {
+		keys := ""
+		for _, k := range getKeys(root, tt.start, tt.end) {
+			keys += k
+		}
+		if keys != tt.keys {
+			t.Errorf("%d, got %s, want %s", idx, keys, tt.keys)
+		}
+	}
+	count := 0
+	ascend(root, "", "", func(n *node) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Errorf("ascend not stopped")
+	}
+}
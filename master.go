@@ -58,6 +58,8 @@ type NodeHostInfo struct {
 	NodeHostAPIAddress string
 	// Region is the region of the NodeHost.
 	Region string
+	// Labels is the set of labels specified in the NodeHostConfig.
+	Labels map[string]string
 	// ClusterInfo is a list of all Raft clusters managed by the NodeHost
 	ClusterInfoList []ClusterInfo
 	// ClusterIDList is a list of cluster IDs for all Raft clusters managed by
@@ -0,0 +1,254 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dragonboat
+
+import (
+	"context"
+	"sort"
+)
+
+// MembershipChangeType is the type of a single step membership change.
+type MembershipChangeType int
+
+const (
+	// PromoteObserverChange promotes an observer to a regular node.
+	PromoteObserverChange MembershipChangeType = iota
+	// AddNodeChange adds a new regular node.
+	AddNodeChange
+	// DeleteNodeChange removes a regular node.
+	DeleteNodeChange
+)
+
+func (t MembershipChangeType) String() string {
+	switch t {
+	case PromoteObserverChange:
+		return "PromoteObserver"
+	case AddNodeChange:
+		return "AddNode"
+	case DeleteNodeChange:
+		return "DeleteNode"
+	}
+	return "Unknown"
+}
+
+// MembershipChange is a single step membership change made by the
+// ReconcileMembership method.
+type MembershipChange struct {
+	Type    MembershipChangeType
+	NodeID  uint64
+	Address string
+}
+
+// ReconcileProgress describes the progress of a ReconcileMembership call.
+type ReconcileProgress struct {
+	// Change is the membership change that has just been completed.
+	Change MembershipChange
+	// Completed is the number of membership changes completed so far.
+	Completed int
+	// Remaining is the number of membership changes still required to reach
+	// the desired membership.
+	Remaining int
+}
+
+// ReconcileProgressFunc is the function invoked by ReconcileMembership each
+// time a membership change is completed.
+type ReconcileProgressFunc func(ReconcileProgress)
+
+// ReconcileMembership changes the membership of the specified Raft cluster
+// until its regular nodes are exactly those specified in desiredMembers, a
+// map of NodeID values to NodeHost Raft addresses, or NodeHost IDs when the
+// AddressByNodeHostID field of the NodeHostConfig is set. This is a
+// synchronous method meaning it will only return after the desired membership
+// is reached, a failure or timeout. The specified progress function, when not
+// nil, is invoked each time a membership change is completed.
+//
+// Membership changes are made one at a time and are determined again after
+// each change using the latest membership, ReconcileMembership can thus be
+// safely retried after a failure. Observers included in desiredMembers are
+// promoted first, nodes not included in desiredMembers are then removed until
+// the cluster is no larger than the desired size, after which new nodes are
+// added and remaining nodes are removed in turns. The number of nodes thus
+// never exceeds the larger one of the current size and the desired size plus
+// one, replacing all nodes never requires more than one added node to be
+// running before its replaced node can be removed. The local node, when it is
+// to be removed, is always removed last. Observers not included in
+// desiredMembers are not changed.
+//
+// Similar to RequestAddNode, it is application's responsibility to call
+// StartCluster on the right NodeHost instances to actually start the added
+// nodes. ReconcileMembership does not wait for added nodes to catch up, they
+// should be started as soon as they are reported by the progress function.
+//
+// ErrInvalidMembership is returned when desiredMembers is empty, includes a
+// removed node or a node with an address different from the one recorded in
+// the current membership.
+func (nh *NodeHost) ReconcileMembership(ctx context.Context, clusterID uint64,
+	desiredMembers map[uint64]string, progress ReconcileProgressFunc) error {
+	v, ok := nh.getCluster(clusterID)
+	if !ok {
+		return ErrClusterNotFound
+	}
+	completed := 0
+	for {
+		membership, err := nh.GetClusterMembership(ctx, clusterID)
+		if err != nil {
+			return err
+		}
+		changes, err := getMembershipChanges(membership,
+			desiredMembers, v.nodeID)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		cc := changes[0]
+		plog.Infof("%s reconciling membership, %s %d, %d changes remaining",
+			v.describe(), cc.Type, cc.NodeID, len(changes))
+		if err := nh.syncChangeMembership(ctx,
+			clusterID, cc, membership.ConfigChangeID); err != nil {
+			return err
+		}
+		completed++
+		if progress != nil {
+			progress(ReconcileProgress{
+				Change:    cc,
+				Completed: completed,
+				Remaining: len(changes) - 1,
+			})
+		}
+		if cc.Type == DeleteNodeChange && cc.NodeID == v.nodeID {
+			// the local node is always the last one to be removed
+			return nil
+		}
+	}
+}
+
+func (nh *NodeHost) syncChangeMembership(ctx context.Context,
+	clusterID uint64, cc MembershipChange, configChangeID uint64) error {
+	timeout, err := getTimeoutFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	var rs *RequestState
+	if cc.Type == DeleteNodeChange {
+		rs, err = nh.RequestDeleteNode(clusterID,
+			cc.NodeID, configChangeID, timeout)
+	} else {
+		rs, err = nh.RequestAddNode(clusterID,
+			cc.NodeID, cc.Address, configChangeID, timeout)
+	}
+	if err != nil {
+		return err
+	}
+	return waitMembershipChange(ctx, rs)
+}
+
+func waitMembershipChange(ctx context.Context, rs *RequestState) error {
+	select {
+	case s := <-rs.CompletedC:
+		if s.Timeout() {
+			return ErrTimeout
+		} else if s.Completed() {
+			rs.Release()
+			return nil
+		} else if s.Terminated() {
+			return ErrClusterClosed
+		} else if s.Rejected() {
+			return ErrRejected
+		}
+		panic("unknown CompletedC value")
+	case <-ctx.Done():
+		if ctx.Err() == context.Canceled {
+			return ErrCanceled
+		} else if ctx.Err() == context.DeadlineExceeded {
+			return ErrTimeout
+		}
+		panic("unknown ctx error")
+	}
+}
+
+// getMembershipChanges returns the ordered membership changes required to
+// change the current membership to the desired one.
+func getMembershipChanges(m *Membership,
+	desired map[uint64]string, localNodeID uint64) ([]MembershipChange, error) {
+	if len(desired) == 0 {
+		return nil, ErrInvalidMembership
+	}
+	promoted := make([]MembershipChange, 0)
+	added := make([]MembershipChange, 0)
+	deleted := make([]MembershipChange, 0)
+	for nodeID, addr := range desired {
+		if _, ok := m.Removed[nodeID]; ok || nodeID == 0 {
+			return nil, ErrInvalidMembership
+		}
+		if v, ok := m.Nodes[nodeID]; ok {
+			if v != addr {
+				return nil, ErrInvalidMembership
+			}
+		} else if v, ok := m.Observers[nodeID]; ok {
+			if v != addr {
+				return nil, ErrInvalidMembership
+			}
+			promoted = append(promoted,
+				MembershipChange{Type: PromoteObserverChange, NodeID: nodeID, Address: addr})
+		} else {
+			if len(addr) == 0 {
+				return nil, ErrInvalidMembership
+			}
+			added = append(added,
+				MembershipChange{Type: AddNodeChange, NodeID: nodeID, Address: addr})
+		}
+	}
+	for nodeID, addr := range m.Nodes {
+		if _, ok := desired[nodeID]; !ok {
+			deleted = append(deleted,
+				MembershipChange{Type: DeleteNodeChange, NodeID: nodeID, Address: addr})
+		}
+	}
+	sortMembershipChanges(promoted, localNodeID)
+	sortMembershipChanges(added, localNodeID)
+	sortMembershipChanges(deleted, localNodeID)
+	changes := promoted
+	size := len(m.Nodes) + len(promoted)
+	for len(added) > 0 || len(deleted) > 0 {
+		// deleted is sorted with the local node last, it is only deleted after
+		// all other changes
+		canDelete := len(deleted) > 0 &&
+			(deleted[0].NodeID != localNodeID || len(added) == 0)
+		if canDelete && (size > len(desired) || len(added) == 0) {
+			changes = append(changes, deleted[0])
+			deleted = deleted[1:]
+			size--
+		} else {
+			changes = append(changes, added[0])
+			added = added[1:]
+			size++
+		}
+	}
+	return changes, nil
+}
+
+func sortMembershipChanges(changes []MembershipChange, localNodeID uint64) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].NodeID == localNodeID {
+			return false
+		}
+		if changes[j].NodeID == localNodeID {
+			return true
+		}
+		return changes[i].NodeID < changes[j].NodeID
+	})
+}
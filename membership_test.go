@@ -0,0 +1,113 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dragonboat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func getTestMembership() *Membership {
+	return &Membership{
+		Nodes:     map[uint64]string{1: "a1", 2: "a2", 3: "a3"},
+		Observers: map[uint64]string{4: "a4"},
+		Removed:   map[uint64]struct{}{5: {}},
+	}
+}
+
+func TestMembershipChangesAreOrdered(t *testing.T) {
+	m := getTestMembership()
+	desired := map[uint64]string{3: "a3", 4: "a4", 7: "a7", 6: "a6"}
+	changes, err := getMembershipChanges(m, desired, 1)
+	if err != nil {
+		t.Fatalf("failed to get changes %v", err)
+	}
+	expected := []MembershipChange{
+		{Type: PromoteObserverChange, NodeID: 4, Address: "a4"},
+		{Type: AddNodeChange, NodeID: 6, Address: "a6"},
+		{Type: DeleteNodeChange, NodeID: 2, Address: "a2"},
+		{Type: AddNodeChange, NodeID: 7, Address: "a7"},
+		{Type: DeleteNodeChange, NodeID: 1, Address: "a1"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("got %v, want %v", changes, expected)
+	}
+}
+
+func TestReplacedNodesAreAddedAndDeletedInTurns(t *testing.T) {
+	m := getTestMembership()
+	desired := map[uint64]string{6: "a6", 7: "a7", 8: "a8"}
+	changes, err := getMembershipChanges(m, desired, 1)
+	if err != nil {
+		t.Fatalf("failed to get changes %v", err)
+	}
+	expected := []MembershipChange{
+		{Type: AddNodeChange, NodeID: 6, Address: "a6"},
+		{Type: DeleteNodeChange, NodeID: 2, Address: "a2"},
+		{Type: AddNodeChange, NodeID: 7, Address: "a7"},
+		{Type: DeleteNodeChange, NodeID: 3, Address: "a3"},
+		{Type: AddNodeChange, NodeID: 8, Address: "a8"},
+		{Type: DeleteNodeChange, NodeID: 1, Address: "a1"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("got %v, want %v", changes, expected)
+	}
+}
+
+func TestNodesAreDeletedFirstWhenShrinking(t *testing.T) {
+	m := getTestMembership()
+	desired := map[uint64]string{1: "a1", 6: "a6"}
+	changes, err := getMembershipChanges(m, desired, 1)
+	if err != nil {
+		t.Fatalf("failed to get changes %v", err)
+	}
+	expected := []MembershipChange{
+		{Type: DeleteNodeChange, NodeID: 2, Address: "a2"},
+		{Type: AddNodeChange, NodeID: 6, Address: "a6"},
+		{Type: DeleteNodeChange, NodeID: 3, Address: "a3"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("got %v, want %v", changes, expected)
+	}
+}
+
+func TestNoMembershipChangeRequiredForDesiredMembership(t *testing.T) {
+	m := getTestMembership()
+	desired := map[uint64]string{1: "a1", 2: "a2", 3: "a3"}
+	changes, err := getMembershipChanges(m, desired, 1)
+	if err != nil {
+		t.Fatalf("failed to get changes %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("unexpected changes %v", changes)
+	}
+}
+
+func TestInvalidDesiredMembershipIsRejected(t *testing.T) {
+	tests := []map[uint64]string{
+		{},
+		{1: "a1", 5: "a5"},
+		{1: "a1", 2: "a3"},
+		{1: "a1", 4: "a3"},
+		{1: "a1", 6: ""},
+		{0: "a0"},
+	}
+	for idx, desired := range tests {
+		_, err := getMembershipChanges(getTestMembership(), desired, 1)
+		if err != ErrInvalidMembership {
+			t.Errorf("%d, got %v, want ErrInvalidMembership", idx, err)
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package dragonboat
 
 import (
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -42,6 +43,7 @@ var (
 	incomingProposalsMaxLen = settings.Soft.IncomingProposalQueueLength
 	incomingReadIndexMaxLen = settings.Soft.IncomingReadIndexQueueLength
 	lazyFreeCycle           = settings.Soft.LazyFreeCycle
+	busyApplyInterval       = nodeReloadInterval
 	logUnreachable          = true
 )
 
@@ -64,6 +66,7 @@ type node struct {
 	pendingProposals     *pendingProposal
 	pendingReadIndexes   *pendingReadIndex
 	pendingConfigChange  *pendingConfigChange
+	pendingAppliedIndex  *pendingAppliedIndex
 	raftMu               sync.Mutex
 	node                 *raft.Peer
 	logreader            *logdb.LogReader
@@ -75,13 +78,23 @@ type node struct {
 	tickCount            uint64
 	expireNotified       uint64
 	rateLimited          bool
+	lastBusyApply        time.Time
+	quota                *proposalQuota
+	rateLimiter          *clientRateLimiter
 	closeOnce            sync.Once
 	ss                   *snapshotState
 	snapshotLock         *syncutil.Lock
+	watchers             watchers
+	ghost                uint32
+	removedNodes         sync.Map
 	initializedMu        struct {
 		sync.Mutex
 		initialized bool
 	}
+	offloadedMu struct {
+		sync.Mutex
+		rsm.OffloadedStatus
+	}
 	quiesceManager
 }
 
@@ -97,6 +110,7 @@ func newNode(raftAddress string,
 	nodeRegistry transport.INodeRegistry,
 	requestStatePool *sync.Pool,
 	config config.Config,
+	rateLimiter *clientRateLimiter,
 	tickMillisecond uint64,
 	ldb raftio.ILogDB) *node {
 	proposals := newEntryQueue(incomingProposalsMaxLen, lazyFreeCycle)
@@ -118,6 +132,7 @@ func newNode(raftAddress string,
 		pendingProposals:    pp,
 		pendingReadIndexes:  pscr,
 		pendingConfigChange: pcc,
+		pendingAppliedIndex: newPendingAppliedIndex(),
 		nodeRegistry:        nodeRegistry,
 		snapshotter:         snapshotter,
 		logreader:           lr,
@@ -126,6 +141,8 @@ func newNode(raftAddress string,
 		logdb:               ldb,
 		snapshotLock:        syncutil.NewLock(),
 		ss:                  &snapshotState{},
+		quota:               newProposalQuota(config),
+		rateLimiter:         rateLimiter,
 		quiesceManager: quiesceManager{
 			electionTick: config.ElectionRTT * 2,
 			enabled:      config.Quiesce,
@@ -202,6 +219,13 @@ func (rc *node) propose(session *client.Session,
 	if !session.ValidForProposal(rc.clusterID) {
 		return nil, ErrInvalidSession
 	}
+	if !rc.rateLimiter.allow(session, cmd) {
+		return nil, ErrRateLimited
+	}
+	if !rc.quota.allow(cmd) {
+		rc.rateLimiter.refund(session, cmd)
+		return nil, ErrQuotaExceeded
+	}
 	return rc.pendingProposals.propose(session, cmd, handler, timeout)
 }
 
@@ -264,12 +288,77 @@ func (rc *node) getLeaderID() (uint64, bool) {
 	return v, v != raft.NoLeader
 }
 
+func (rc *node) queryRaftLog(firstIndex uint64,
+	lastIndex uint64, maxBytes uint64) ([]pb.Entry, error) {
+	// entries applied by the local node are known to be committed, they might
+	// not be in the LogReader yet as entries are published before being saved
+	applied := rc.sm.GetLastApplied()
+	first, last := rc.logreader.GetRange()
+	if firstIndex < first {
+		return nil, ErrCompacted
+	}
+	if last > applied {
+		last = applied
+	}
+	if lastIndex > last+1 {
+		lastIndex = last + 1
+	}
+	if firstIndex >= lastIndex {
+		return []pb.Entry{}, nil
+	}
+	if maxBytes == 0 {
+		maxBytes = math.MaxUint64
+	}
+	ents, err := rc.logreader.Entries(firstIndex, lastIndex, maxBytes)
+	if err == raft.ErrCompacted {
+		return nil, ErrCompacted
+	}
+	return ents, err
+}
+
+func (rc *node) getLeaderInfo() (uint64, uint64) {
+	return rc.node.GetLeaderInfo()
+}
+
 func (rc *node) notifyOffloaded(from rsm.From) {
 	rc.sm.Offloaded(from)
+	rc.offloadedMu.Lock()
+	defer rc.offloadedMu.Unlock()
+	rc.offloadedMu.SetOffloaded(from)
 }
 
 func (rc *node) notifyLoaded(from rsm.From) {
 	rc.sm.Loaded(from)
+	rc.offloadedMu.Lock()
+	defer rc.offloadedMu.Unlock()
+	rc.offloadedMu.SetLoaded(from)
+}
+
+// offloaded returns a boolean value indicating whether the node has been
+// offloaded from the NodeHost and all execution engine workers.
+func (rc *node) offloaded() bool {
+	rc.offloadedMu.Lock()
+	defer rc.offloadedMu.Unlock()
+	return rc.offloadedMu.ReadyToDestroy()
+}
+
+// setGhost marks the node as a ghost replica, i.e. it has been removed from
+// the Raft cluster.
+func (rc *node) setGhost() {
+	atomic.StoreUint32(&rc.ghost, 1)
+}
+
+func (rc *node) isGhost() bool {
+	return atomic.LoadUint32(&rc.ghost) == 1
+}
+
+func (rc *node) setNodeRemoved(nodeID uint64) {
+	rc.removedNodes.Store(nodeID, struct{}{})
+}
+
+func (rc *node) nodeRemoved(nodeID uint64) bool {
+	_, ok := rc.removedNodes.Load(nodeID)
+	return ok
 }
 
 func (rc *node) entriesToApply(ents []pb.Entry) (nents []pb.Entry) {
@@ -482,6 +571,22 @@ func (rc *node) handleCommit(batch []rsm.Commit,
 	return rc.sm.Handle(batch, entries)
 }
 
+// applyThrottled returns a boolean value indicating whether committed entries
+// should not be applied for now. Committed entries are applied to busy state
+// machines at most once every busyApplyInterval, pending entries are kept in
+// the commitC and in the Raft log in the meantime.
+func (rc *node) applyThrottled() bool {
+	if !rc.sm.Busy() {
+		return false
+	}
+	now := time.Now()
+	if now.Sub(rc.lastBusyApply) < busyApplyInterval {
+		return true
+	}
+	rc.lastBusyApply = now
+	return false
+}
+
 func (rc *node) removeSnapshotFlagFile(index uint64) error {
 	return rc.snapshotter.removeFlagFile(index)
 }
@@ -667,6 +772,7 @@ func (rc *node) handleEvents() bool {
 			rc.expireNotified = rc.tickCount
 		}
 		rc.pendingReadIndexes.applied(lastApplied)
+		rc.pendingAppliedIndex.applied(lastApplied)
 	}
 	return hasEvent
 }
@@ -814,6 +920,9 @@ func (rc *node) applyUpdate(entry pb.Entry,
 		}
 		rc.pendingProposals.applied(entry.ClientID,
 			entry.SeriesID, entry.Key, result, rejected)
+		if watchable(entry) {
+			rc.watchers.publish(entry, result, rejected)
+		}
 	}
 }
 
@@ -827,8 +936,10 @@ func (rc *node) applyConfigChange(cc pb.ConfigChange) {
 	case pb.AddObserver:
 		rc.nodeRegistry.AddNode(rc.clusterID, cc.NodeID, string(cc.Address))
 	case pb.RemoveNode:
+		rc.setNodeRemoved(cc.NodeID)
 		if cc.NodeID == rc.nodeID {
 			plog.Infof("%s applied ConfChange Remove for itself", rc.describe())
+			rc.setGhost()
 			rc.nodeRegistry.RemoveCluster(rc.clusterID)
 			rc.requestRemoval()
 		} else {
@@ -852,7 +963,9 @@ func (rc *node) restoreRemotes(snapshot pb.Snapshot) {
 		rc.nodeRegistry.AddNode(rc.clusterID, nid, addr)
 	}
 	for nid := range snapshot.Membership.Removed {
+		rc.setNodeRemoved(nid)
 		if nid == rc.nodeID {
+			rc.setGhost()
 			rc.nodeRegistry.RemoveCluster(rc.clusterID)
 			rc.requestRemoval()
 		}
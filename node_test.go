@@ -235,6 +235,7 @@ func doGetTestRaftNodes(startID uint64, count int, ordered bool,
 			nr,
 			requestStatePool,
 			config,
+			nil,
 			tickMillisecond,
 			ldb)
 		nodes = append(nodes, node)
@@ -138,12 +138,62 @@ var (
 	// ErrInvalidDeadline indicates that the specified deadline is invalid, e.g.
 	// time in the past.
 	ErrInvalidDeadline = errors.New("invalid deadline")
+	// ErrPlacementConstraintViolated indicates that the requested operation
+	// would leave voting members of the Raft cluster distributed in a way that
+	// violates the placement constraints specified in config.Config.
+	ErrPlacementConstraintViolated = errors.New("placement constraint violated")
+	// ErrNotObserver indicates that the specified node is not an observer of
+	// the Raft cluster.
+	ErrNotObserver = errors.New("not an observer")
+	// ErrInvalidMembership indicates that the desired membership specified for
+	// the Raft cluster is invalid.
+	ErrInvalidMembership = errors.New("invalid membership")
+	// ErrNotLeader indicates that the local node is not the leader of the Raft
+	// cluster.
+	ErrNotLeader = errors.New("not leader")
+	// ErrInvalidRange indicates that the specified Raft log index range is
+	// invalid.
+	ErrInvalidRange = errors.New("invalid range")
+	// ErrCompacted indicates that the requested Raft log entries have been
+	// compacted and are no longer available.
+	ErrCompacted = errors.New("entry compacted")
+	// ErrWatcherOverflow indicates that the Watcher has been stopped as the
+	// application failed to keep up with the proposals applied.
+	ErrWatcherOverflow = errors.New("watcher overflow")
+	// ErrPluginNotFound indicates that no plugin with the specified app name
+	// can be found.
+	ErrPluginNotFound = errors.New("plugin not found")
+	// ErrClusterNotStopped indicates that the specified node is still running
+	// or is still being stopped.
+	ErrClusterNotStopped = errors.New("cluster not stopped")
+	// ErrRemovingNodeData indicates that data of the specified node is being
+	// removed.
+	ErrRemovingNodeData = errors.New("node data being removed")
+	// ErrRemoveDataNotSupported indicates that the Log DB used by the NodeHost
+	// does not support removing node data.
+	ErrRemoveDataNotSupported = errors.New("removing node data not supported")
 )
 
 // MasterClientFactoryFunc is the factory function for creating a new
 // IMasterClient instance.
 type MasterClientFactoryFunc func(*NodeHost) IMasterClient
 
+// ISyncProposer is the interface implemented by NodeHost for making
+// synchronous proposals. It allows packages built on top of NodeHost to be
+// used and tested without a NodeHost instance.
+type ISyncProposer interface {
+	SyncPropose(ctx context.Context,
+		session *client.Session, cmd []byte) (uint64, error)
+	GetNoOPSession(clusterID uint64) *client.Session
+}
+
+// ISyncRequester is the interface implemented by NodeHost for making
+// synchronous proposals and linearizable reads.
+type ISyncRequester interface {
+	ISyncProposer
+	SyncRead(ctx context.Context, clusterID uint64, query []byte) ([]byte, error)
+}
+
 // NodeHost manages Raft clusters and enables them to share resources such as
 // transport and persistent storage etc. NodeHost is also the central access
 // point for Dragonboat functionalities provided to applications.
@@ -168,6 +218,7 @@ type NodeHost struct {
 	region           string
 	masterClient     IMasterClient
 	deploymentID     uint64
+	id               atomic.Value
 	rsPool           []*sync.Pool
 	execEngine       *execEngine
 	logdb            raftio.ILogDB
@@ -175,6 +226,8 @@ type NodeHost struct {
 	msgHandler       *messageHandler
 	initializedC     chan struct{}
 	transportLatency *sample
+	ghosts           *ghostReplicas
+	plugins          *pluginManager
 }
 
 // NewNodeHost creates a new NodeHost instance. The returned NodeHost instance
@@ -199,9 +252,11 @@ func NewNodeHostWithMasterClientFactory(nhConfig config.NodeHostConfig,
 		nhConfig:         nhConfig,
 		stopper:          syncutil.NewStopper(),
 		duStopper:        syncutil.NewStopper(),
-		nodes:            transport.NewNodes(streamConnections),
+		nodes:            newNodeRegistry(nhConfig),
 		initializedC:     make(chan struct{}),
 		transportLatency: newSample(),
+		ghosts:           newGhostReplicas(),
+		plugins:          newPluginManager(nhConfig.PluginDir),
 	}
 	nh.snapshotStatus = newSnapshotFeedback(nh.pushSnapshotStatus)
 	nh.msgHandler = newNodeHostMessageHandler(nh)
@@ -280,6 +335,23 @@ func (nh *NodeHost) RaftAddress() string {
 	return nh.nhConfig.RaftAddress
 }
 
+// ID returns the NodeHost ID of the NodeHost instance. The NodeHost ID is
+// persistent, it is saved in the NodeHostDir when the NodeHost is first
+// started and stays the same across restarts even when the RaftAddress is
+// changed. Empty string is returned when the NodeHost is still being
+// initialized by the Master servers.
+//
+// When the AddressByNodeHostID field of the NodeHostConfig is set to true,
+// the NodeHost ID rather than the RaftAddress should be specified as the
+// address of the NodeHost when starting Raft clusters or requesting
+// membership changes.
+func (nh *NodeHost) ID() string {
+	if v := nh.id.Load(); v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
 // Stop stops all Raft nodes managed by the NodeHost instance, closes the
 // transport and persistent storage modules.
 func (nh *NodeHost) Stop() {
@@ -342,7 +414,11 @@ func (nh *NodeHost) Stop() {
 // createStateMachine is a factory function for creating the IStateMachine
 // instance, config is the configuration instance that will be passed to the
 // underlying Raft node object, the cluster ID and node ID of the involved node
-// is given in the ClusterID and NodeID fields of the config object.
+// is given in the ClusterID and NodeID fields of the config object. NodeHost
+// IDs rather than RaftAddress values should be specified in the nodes map when
+// the AddressByNodeHostID field of the NodeHostConfig is set. Snapshots are
+// saved concurrent to the Update() method when the created IStateMachine
+// instance implements the statemachine.ICloneableStateMachine interface.
 //
 // Note that this method is not for changing the membership of the specified
 // Raft cluster, it launches a node that is already a member of the Raft
@@ -363,7 +439,7 @@ func (nh *NodeHost) StartCluster(nodes map[uint64]string,
 	cf := func(clusterID uint64, nodeID uint64,
 		done <-chan struct{}) rsm.IManagedStateMachine {
 		sm := createStateMachine(clusterID, nodeID)
-		return rsm.NewNativeStateMachine(rsm.NewRegularStateMachine(sm), done)
+		return rsm.NewNativeStateMachine(rsm.NewStateMachineAdapter(sm), done)
 	}
 	return nh.startCluster(nodes, join, cf, stopc, config)
 }
@@ -399,6 +475,41 @@ func (nh *NodeHost) StartClusterUsingPlugin(nodes map[uint64]string,
 	return nh.startCluster(nodes, join, cf, stopc, config)
 }
 
+// StartClusterUsingAppName adds a new cluster node to the NodeHost and start
+// running the new node. The state machine is created by the plugin with the
+// specified app name, plugins are discovered by scanning the PluginDir
+// directory specified in NodeHostConfig each time StartClusterUsingAppName is
+// invoked. Each Go plugin is only opened once, plugins that can not be loaded
+// are logged and skipped.
+//
+// A Go plugin is a .so file named as dragonboat-plugin-<name>.so, it must
+// export a DragonboatApplicationName string variable and a CreateStateMachine
+// or CreateConcurrentStateMachine function with the same signature of the
+// factory function accepted by StartCluster or StartConcurrentCluster. C++
+// plugins are identified by using cpp-<appname> as the app name.
+//
+// Loaded Go plugins can not be unloaded. To upgrade a state machine without
+// recompiling the host binary, build the new version of the plugin with a
+// different file name and plugin path, replace the old .so file in PluginDir
+// with it and restart the node using StartClusterUsingAppName. Having two
+// plugins with the same app name in PluginDir is considered as an error when
+// starting a node using that app name.
+func (nh *NodeHost) StartClusterUsingAppName(nodes map[uint64]string,
+	join bool, appName string, config config.Config) error {
+	pd, err := nh.plugins.get(appName)
+	if err != nil {
+		return err
+	}
+	if pd.isRegularStateMachine() {
+		return nh.StartCluster(nodes,
+			join, pd.createRegularStateMachine, config)
+	} else if pd.isConcurrentStateMachine() {
+		return nh.StartConcurrentCluster(nodes,
+			join, pd.createConcurrentStateMachine, config)
+	}
+	return nh.StartClusterUsingPlugin(nodes, join, pd.filepath, config)
+}
+
 // StopCluster removes and stops the Raft node associated with the specified
 // Raft cluster from the NodeHost. The node to be removed and stopped is
 // identified by the clusterID value.
@@ -542,6 +653,57 @@ func (nh *NodeHost) GetLeaderID(clusterID uint64) (uint64, bool, error) {
 	return nodeID, valid, nil
 }
 
+// FencingToken is the token identifying a leader of a Raft cluster.
+type FencingToken struct {
+	// Term is the Raft term in which LeaderID is the leader.
+	Term uint64
+	// LeaderID is the NodeID of the leader.
+	LeaderID uint64
+}
+
+// Value returns the fencing token value. Fencing token values are
+// monotonically increasing, the value of a token returned by a newer leader is
+// always greater than the value of tokens returned by previous leaders.
+func (t FencingToken) Value() uint64 {
+	return t.Term
+}
+
+// GetFencingToken returns a fencing token for the local node when it is the
+// leader of the specified Raft cluster. This is a synchronous method meaning
+// it will only return after the leadership of the local node is confirmed by
+// the quorum, a failure or timeout. ErrNotLeader is returned when the local
+// node is not the leader.
+//
+// Fencing tokens can be used for implementing leases of external resources,
+// e.g. object stores or databases, on top of Raft leadership. The application
+// running on the leader passes the token value along with its requests, the
+// external resource rejects requests with a token value lower than the
+// highest value it has seen, so requests made by a deposed leader are fenced
+// off.
+func (nh *NodeHost) GetFencingToken(ctx context.Context,
+	clusterID uint64) (FencingToken, error) {
+	v, ok := nh.getCluster(clusterID)
+	if !ok {
+		return FencingToken{}, ErrClusterNotFound
+	}
+	leaderID, term := v.getLeaderInfo()
+	if leaderID != v.nodeID {
+		return FencingToken{}, ErrNotLeader
+	}
+	_, err := nh.linearizableRead(ctx, clusterID,
+		func(node *node) (interface{}, error) {
+			// leadership confirmed by the ReadIndex belongs to the same term
+			if l, t := node.getLeaderInfo(); l != leaderID || t != term {
+				return nil, ErrNotLeader
+			}
+			return nil, nil
+		})
+	if err != nil {
+		return FencingToken{}, err
+	}
+	return FencingToken{Term: term, LeaderID: leaderID}, nil
+}
+
 // GetNoOPSession returns a NO-OP client session ready to be used for
 // making proposals. The NO-OP client session is a dummy client session that
 // will not be checked or enforced. Use this No-OP client session when you
@@ -645,6 +807,11 @@ func (nh *NodeHost) CloseSession(ctx context.Context,
 // Session object. The input byte slice can be reused for other purposes
 // immediate after the return of this method.
 //
+// ErrRateLimited is returned when the ClientProposalRate limit specified in
+// NodeHostConfig is exceeded by the client, ErrQuotaExceeded is returned when
+// the MaxProposalRate or MaxProposalBytesRate quota of the Raft cluster is
+// exceeded.
+//
 // This method returns a RequestState instance or an error immediately.
 // Application can wait on the CompleteC member channel of the returned
 // RequestState instance to get notified for the outcome of the proposal and
@@ -773,6 +940,10 @@ func (nh *NodeHost) RequestDeleteNode(clusterID uint64,
 // Application can wait on the CompleteC member of the returned RequestState
 // instance to get notified for the outcome.
 //
+// When PlacementConstraints are specified for the Raft cluster, the request is
+// rejected with ErrPlacementConstraintViolated if the new node would violate
+// those constraints based on the local node's knowledge of the membership.
+//
 // If there is already an observer with the same nodeID in the cluster, it will
 // be promoted to a regular node with voting power. The address parameter of the
 // RequestAddNode call is ignored when promoting an observer to a regular node.
@@ -782,12 +953,14 @@ func (nh *NodeHost) RequestDeleteNode(clusterID uint64,
 // start the Raft cluster node.
 //
 // The input address parameter is the RaftAddress of the NodeHost where the new
-// Raft node being added will be running. When the raft cluster is created with
-// the OrderedConfigChange config flag set as false, the configChangeIndex
-// parameter is ignored. Otherwise, it should be set to the most recent Config
-// Change Index value returned by the GetClusterMembership method. The requested
-// add node operation will be rejected if other membership change has been
-// applied since the call to the GetClusterMembership method.
+// Raft node being added will be running, or its NodeHost ID when the
+// AddressByNodeHostID field of the NodeHostConfig is set. When the raft
+// cluster is created with the OrderedConfigChange config flag set as false,
+// the configChangeIndex parameter is ignored. Otherwise, it should be set to
+// the most recent Config Change Index value returned by the
+// GetClusterMembership method. The requested add node operation will be
+// rejected if other membership change has been applied since the call to the
+// GetClusterMembership method.
 func (nh *NodeHost) RequestAddNode(clusterID uint64,
 	nodeID uint64, address string, configChangeIndex uint64,
 	timeout time.Duration) (*RequestState, error) {
@@ -795,6 +968,9 @@ func (nh *NodeHost) RequestAddNode(clusterID uint64,
 	if !ok {
 		return nil, ErrClusterNotFound
 	}
+	if err := nh.checkAddNodePlacement(v, nodeID, address); err != nil {
+		return nil, err
+	}
 	req, err := v.requestAddNodeWithOrderID(nodeID,
 		address, configChangeIndex, timeout)
 	nh.execEngine.setNodeReady(clusterID)
@@ -817,12 +993,14 @@ func (nh *NodeHost) RequestAddNode(clusterID uint64,
 // set to true on the right NodeHost to actually start the observer instance.
 //
 // The input address parameter is the RaftAddress of the NodeHost where the new
-// observer being added will be running. When the raft cluster is created with
-// the OrderedConfigChange config flag set as false, the configChangeIndex
-// parameter is ignored. Otherwise, it should be set to the most recent Config
-// Change Index value returned by the GetClusterMembership method. The requested
-// add observer operation will be rejected if other membership change has been
-// applied since the call to the GetClusterMembership method.
+// observer being added will be running, or its NodeHost ID when the
+// AddressByNodeHostID field of the NodeHostConfig is set. When the raft
+// cluster is created with the OrderedConfigChange config flag set as false,
+// the configChangeIndex parameter is ignored. Otherwise, it should be set to
+// the most recent Config Change Index value returned by the
+// GetClusterMembership method. The requested add observer operation will be
+// rejected if other membership change has been applied since the call to the
+// GetClusterMembership method.
 func (nh *NodeHost) RequestAddObserver(clusterID uint64,
 	nodeID uint64, address string, configChangeIndex uint64,
 	timeout time.Duration) (*RequestState, error) {
@@ -836,6 +1014,45 @@ func (nh *NodeHost) RequestAddObserver(clusterID uint64,
 	return req, err
 }
 
+// SyncPromoteObserver promotes the specified observer to a regular node with
+// voting power. This is a synchronous method meaning it will only return after
+// its confirmed completion, failure or timeout.
+//
+// An observer continuously receives and applies the replicated log, it can
+// thus be used as a hot standby replica. Once promoted, the node inherits the
+// replication progress of the observer, no snapshot is required to bring it
+// up to date, making it suitable for quickly replacing a failed node. It is
+// application's responsibility to remove the failed node by calling
+// RequestDeleteNode.
+//
+// SyncPromoteObserver returns ErrNotObserver when the specified node is
+// neither an observer nor a regular node of the Raft cluster. It returns nil
+// if the specified node is already a regular node.
+func (nh *NodeHost) SyncPromoteObserver(ctx context.Context,
+	clusterID uint64, nodeID uint64) error {
+	membership, err := nh.GetClusterMembership(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	if _, ok := membership.Nodes[nodeID]; ok {
+		return nil
+	}
+	address, ok := membership.Observers[nodeID]
+	if !ok {
+		return ErrNotObserver
+	}
+	timeout, err := getTimeoutFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	rs, err := nh.RequestAddNode(clusterID,
+		nodeID, address, membership.ConfigChangeID, timeout)
+	if err != nil {
+		return err
+	}
+	return waitMembershipChange(ctx, rs)
+}
+
 // RequestLeaderTransfer makes a request to transfer the leadership of the
 // specified Raft cluster to the target node identified by targetNodeID. It
 // returns an error if the request fails to be started. There is no guarantee
@@ -853,6 +1070,93 @@ func (nh *NodeHost) RequestLeaderTransfer(clusterID uint64,
 	return nil
 }
 
+// Watch returns a Watcher instance for receiving committed proposals applied
+// on the local node of the specified Raft cluster in Raft log index order,
+// starting from the proposal with Raft log index fromIndex. Setting fromIndex
+// to 0 means only proposals applied after the Watch call will be received.
+// Proposals applied before the Watch call are read from the Raft log,
+// ErrCompacted is returned when they are no longer available in the Raft log.
+//
+// Received records can be used to build change data capture pipelines or
+// secondary indexes. Proposals retried using the same client session might be
+// received more than once. The Watcher never slows down the local node, it
+// fails with the ErrWatcherOverflow error when the application can not keep
+// up with the applied proposals, a new Watcher can be created to resume from
+// the Raft log index following the last received record. Watcher must be
+// closed when it is no longer required.
+func (nh *NodeHost) Watch(clusterID uint64,
+	fromIndex uint64) (*Watcher, error) {
+	v, ok := nh.getCluster(clusterID)
+	if !ok {
+		return nil, ErrClusterNotFound
+	}
+	return v.watch(fromIndex)
+}
+
+// AwaitAppliedIndex waits until the local node of the specified Raft cluster
+// has applied the Raft log entry with the specified index to its state
+// machine. This is a synchronous method meaning it will only return after
+// the entry is applied locally, the cluster is closed or timeout.
+//
+// AwaitAppliedIndex can be used to read your own writes on a different node,
+// e.g. the Raft log index of a proposal can be obtained from a Watcher or
+// the leader node, the application can then wait for the proposal to be
+// applied on the local node before calling ReadLocal.
+func (nh *NodeHost) AwaitAppliedIndex(ctx context.Context,
+	clusterID uint64, index uint64) error {
+	if _, err := getTimeoutFromContext(ctx); err != nil {
+		return err
+	}
+	v, ok := nh.getCluster(clusterID)
+	if !ok {
+		return ErrClusterNotFound
+	}
+	c := v.pendingAppliedIndex.add(index)
+	defer v.pendingAppliedIndex.remove(c)
+	if v.sm.GetLastApplied() >= index {
+		return nil
+	}
+	select {
+	case <-c:
+		return nil
+	case <-v.stopc:
+		return ErrClusterClosed
+	case <-ctx.Done():
+		if ctx.Err() == context.Canceled {
+			return ErrCanceled
+		} else if ctx.Err() == context.DeadlineExceeded {
+			return ErrTimeout
+		}
+		panic("unknown ctx error")
+	}
+}
+
+// QueryRaftLog returns committed Raft log entries with index in the range of
+// [firstIndex, lastIndex) from the LogDB of the local node of the specified
+// Raft cluster. Only entries already applied by the local node are returned,
+// the returned entries can thus be fewer than requested, or even empty when
+// no requested entry has been applied yet. The total size of the returned
+// entries is limited to maxBytes, at least one entry is returned when
+// available even if its size exceeds maxBytes. Setting maxBytes to 0 means no
+// limit.
+//
+// All types of entries are returned, including those internally used by
+// dragonboat, e.g. membership change and client session entries. Entries
+// proposed by the application can be identified by their IsUpdateEntry
+// method. ErrCompacted is returned when requested entries have been compacted
+// from the Raft log.
+func (nh *NodeHost) QueryRaftLog(clusterID uint64,
+	firstIndex uint64, lastIndex uint64, maxBytes uint64) ([]pb.Entry, error) {
+	if firstIndex == 0 || firstIndex >= lastIndex {
+		return nil, ErrInvalidRange
+	}
+	v, ok := nh.getCluster(clusterID)
+	if !ok {
+		return nil, ErrClusterNotFound
+	}
+	return v.queryRaftLog(firstIndex, lastIndex, maxBytes)
+}
+
 // GetNodeUser returns an INodeUser instance ready to be used to directly make
 // proposals or read index operations without locating the node repeatedly in
 // the NodeHost. A possible use case is when loading a large data set say with
@@ -883,6 +1187,25 @@ func (nh *NodeHost) HasNodeInfo(clusterID uint64, nodeID uint64) bool {
 	return true
 }
 
+// GetTransportMetrics returns the counters of Raft messages and snapshot
+// chunks received and dropped by the Raft RPC module, e.g. messages that are
+// corrupted or can not be authenticated using the TransportSecret.
+func (nh *NodeHost) GetTransportMetrics() raftio.TransportMetrics {
+	return nh.transport.GetMetrics()
+}
+
+// GetProposalQuotaMetrics returns the counters of proposals rejected by the
+// specified Raft cluster for exceeding its MaxProposalRate or
+// MaxProposalBytesRate quota.
+func (nh *NodeHost) GetProposalQuotaMetrics(
+	clusterID uint64) (ProposalQuotaMetrics, error) {
+	v, ok := nh.getCluster(clusterID)
+	if !ok {
+		return ProposalQuotaMetrics{}, ErrClusterNotFound
+	}
+	return v.quota.getMetrics(), nil
+}
+
 func (nh *NodeHost) propose(s *client.Session,
 	cmd []byte, handler ICompleteHandler,
 	timeout time.Duration) (*RequestState, error) {
@@ -1070,11 +1393,22 @@ func (nh *NodeHost) startCluster(nodes map[uint64]string,
 	if _, ok := nh.clusterMu.clusters.Load(clusterID); ok {
 		return ErrClusterAlreadyExist
 	}
+	if nh.ghosts.isRemoving(clusterID, nodeID) {
+		return ErrRemovingNodeData
+	}
 	if join && len(nodes) > 0 {
 		plog.Errorf("trying to join %s with initial member list %v",
 			logutil.DescribeNode(clusterID, nodeID), nodes)
 		return ErrInvalidClusterSettings
 	}
+	if !join && !config.IsObserver && len(nodes) > 0 {
+		if err := nh.checkPlacement(config.PlacementConstraints,
+			nodes); err != nil {
+			plog.Errorf("initial members %v of %s, %v",
+				nodes, logutil.DescribeNode(clusterID, nodeID), err)
+			return err
+		}
+	}
 	addresses, initialMember, err := nh.bootstrapCluster(nodes, join, config)
 	if err == ErrInvalidClusterSettings {
 		return ErrInvalidClusterSettings
@@ -1116,6 +1450,7 @@ func (nh *NodeHost) startCluster(nodes map[uint64]string,
 		nh.nodes,
 		nh.rsPool[nodeID%rsPoolSize],
 		config,
+		newClientRateLimiter(nh.nhConfig),
 		nh.nhConfig.RTTMillisecond,
 		nh.logdb)
 	nh.clusterMu.clusters.Store(clusterID, rn)
@@ -1124,6 +1459,62 @@ func (nh *NodeHost) startCluster(nodes map[uint64]string,
 	return nil
 }
 
+func (nh *NodeHost) getNodeHostLabels(addr string) (map[string]string, bool) {
+	if stringutil.CleanAddress(addr) ==
+		stringutil.CleanAddress(nh.nhConfig.RaftAddress) {
+		return nh.nhConfig.Labels, true
+	}
+	if nh.nhConfig.AddressByNodeHostID && addr == nh.ID() {
+		return nh.nhConfig.Labels, true
+	}
+	if nh.nhConfig.LabelResolver == nil {
+		return nil, false
+	}
+	return nh.nhConfig.LabelResolver(addr)
+}
+
+func (nh *NodeHost) checkPlacement(constraints []config.PlacementConstraint,
+	members map[uint64]string) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+	labels := make([]map[string]string, 0, len(members))
+	for _, addr := range members {
+		l, ok := nh.getNodeHostLabels(addr)
+		if !ok {
+			plog.Errorf("labels of NodeHost %s are unknown", addr)
+			return ErrPlacementConstraintViolated
+		}
+		labels = append(labels, l)
+	}
+	err := config.CheckPlacement(constraints, len(members), labels)
+	if err != nil {
+		plog.Warningf("placement check failed, %v", err)
+		return ErrPlacementConstraintViolated
+	}
+	return nil
+}
+
+func (nh *NodeHost) checkAddNodePlacement(n *node,
+	nodeID uint64, address string) error {
+	if len(n.config.PlacementConstraints) == 0 {
+		return nil
+	}
+	members, observers, _, _ := n.sm.GetMembership()
+	if _, ok := members[nodeID]; ok {
+		return nil
+	}
+	if addr, ok := observers[nodeID]; ok {
+		address = addr
+	}
+	voters := make(map[uint64]string)
+	for nid, addr := range members {
+		voters[nid] = addr
+	}
+	voters[nodeID] = address
+	return nh.checkPlacement(n.config.PlacementConstraints, voters)
+}
+
 func (nh *NodeHost) createPools() {
 	nh.rsPool = make([]*sync.Pool, rsPoolSize)
 	for i := uint64(0); i < rsPoolSize; i++ {
@@ -1141,10 +1532,19 @@ func (nh *NodeHost) createPools() {
 func (nh *NodeHost) createLogDB(nhConfig config.NodeHostConfig,
 	deploymentID uint64) {
 	nhDirs, walDirs := nh.serverCtx.CreateNodeHostDir(deploymentID)
+	id := nh.serverCtx.GetNodeHostID(deploymentID)
 	nh.serverCtx.CheckNodeHostDir(deploymentID, nh.nhConfig.RaftAddress)
+	nh.id.Store(id)
 	var factory config.LogDBFactoryFunc
 	if nhConfig.LogDBFactory != nil {
 		factory = nhConfig.LogDBFactory
+	} else if nhConfig.LogDBSyncIntervalMillisecond > 0 {
+		interval := time.Duration(nhConfig.LogDBSyncIntervalMillisecond) *
+			time.Millisecond
+		factory = func(dirs []string,
+			lowLatencyDirs []string) (raftio.ILogDB, error) {
+			return logdb.OpenRelaxedLogDB(dirs, lowLatencyDirs, interval)
+		}
 	} else {
 		factory = logdb.OpenLogDB
 	}
@@ -1182,6 +1582,7 @@ func (nh *NodeHost) stopNode(clusterID uint64,
 	nh.clusterMu.csi++
 	cluster.close()
 	cluster.notifyOffloaded(rsm.FromNodeHost)
+	nh.ghosts.addStopped(cluster)
 	return nil
 }
 
@@ -1230,6 +1631,7 @@ func (nh *NodeHost) reportNodeHostInfo(ctx context.Context,
 		NodeHostAddress:    nh.RaftAddress(),
 		NodeHostAPIAddress: nh.nhConfig.APIAddress,
 		Region:             nh.region,
+		Labels:             nh.nhConfig.Labels,
 		ClusterInfoList:    clusterInfoList,
 		ClusterIDList:      clusterIDList,
 		LogInfoIncluded:    plogIncluded,
@@ -1450,12 +1852,10 @@ func (nh *NodeHost) checkTransportLatency(clusterID uint64,
 
 func (nh *NodeHost) closeStoppedClusters() {
 	chans := make([]<-chan struct{}, 0)
-	keys := make([]uint64, 0)
-	nodeIDs := make([]uint64, 0)
+	nodes := make([]*node, 0)
 	nh.forEachCluster(func(cid uint64, node *node) bool {
 		chans = append(chans, node.shouldStop())
-		keys = append(keys, cid)
-		nodeIDs = append(nodeIDs, node.nodeID)
+		nodes = append(nodes, node)
 		return true
 	})
 	if len(chans) == 0 {
@@ -1470,11 +1870,14 @@ func (nh *NodeHost) closeStoppedClusters() {
 	}
 	cases[len(chans)] = reflect.SelectCase{Dir: reflect.SelectDefault}
 	chosen, _, ok := reflect.Select(cases)
-	if !ok && chosen < len(keys) {
-		clusterID := keys[chosen]
-		nodeID := nodeIDs[chosen]
-		if err := nh.StopNode(clusterID, nodeID); err != nil {
-			plog.Errorf("failed to remove cluster %d", clusterID)
+	if !ok && chosen < len(nodes) {
+		node := nodes[chosen]
+		if err := nh.StopNode(node.clusterID, node.nodeID); err != nil {
+			plog.Errorf("failed to remove cluster %d", node.clusterID)
+			return
+		}
+		if node.isGhost() {
+			nh.onLocalGhostReplica(node)
 		}
 	}
 }
@@ -1485,6 +1888,7 @@ func (nh *NodeHost) nodeMonitorMain(ctx context.Context,
 	tf := func() bool {
 		count++
 		nh.closeStoppedClusters()
+		nh.handleGhostReplicas()
 		return false
 	}
 	lang.RunTicker(monitorInterval, tf, nh.stopper.ShouldStop(), nil)
@@ -1585,6 +1989,15 @@ func (nh *NodeHost) logNodeHostDetails() {
 		plog.Infof("logdb type: %s", nh.logdb.Name())
 	}
 	plog.Infof("nodehost address: %s", nh.nhConfig.RaftAddress)
+	plog.Infof("nodehost id: %s", nh.ID())
+}
+
+func newNodeRegistry(nhConfig config.NodeHostConfig) *transport.Nodes {
+	if nhConfig.AddressByNodeHostID {
+		return transport.NewNodesWithNodeHostIDResolver(streamConnections,
+			nhConfig.NodeHostIDResolver)
+	}
+	return transport.NewNodes(streamConnections)
 }
 
 func (nh *NodeHost) logTransportLatency() {
@@ -1689,8 +2102,12 @@ func (h *messageHandler) HandleMessageBatch(msg pb.MessageBatch) {
 			h.HandlePongMessage(req)
 			continue
 		}
-		_, q, ok := nh.getClusterAndQueueNotLocked(req.ClusterId)
+		n, q, ok := nh.getClusterAndQueueNotLocked(req.ClusterId)
 		if ok {
+			if n.nodeRemoved(req.From) {
+				nh.onRemoteGhostReplica(req.ClusterId, req.From, msg.SourceAddress)
+				continue
+			}
 			if req.Type == pb.InstallSnapshot {
 				q.AddSnapshot(req)
 			} else {
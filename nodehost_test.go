@@ -345,6 +345,27 @@ func TestLogDBCanBeExtended(t *testing.T) {
 	}
 }
 
+func TestNodeHostIDIsKeptWhenRaftAddressChanges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer os.RemoveAll(singleNodeHostTestDir)
+	os.RemoveAll(singleNodeHostTestDir)
+	c := getTestNodeHostConfig()
+	c.AddressByNodeHostID = true
+	c.NodeHostIDResolver = func(string) (string, bool) { return "", false }
+	nh := NewNodeHost(*c)
+	id := nh.ID()
+	nh.Stop()
+	if len(id) == 0 {
+		t.Fatalf("NodeHost ID not set")
+	}
+	c.RaftAddress = "localhost:1112"
+	nh = NewNodeHost(*c)
+	defer nh.Stop()
+	if nh.ID() != id {
+		t.Errorf("NodeHost ID changed, %s vs %s", nh.ID(), id)
+	}
+}
+
 func TestTCPTransportIsUsedByDefault(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer os.RemoveAll(singleNodeHostTestDir)
@@ -1096,6 +1117,219 @@ func TestNodeHostSyncIOAPIs(t *testing.T) {
 	singleNodeHostTest(t, tf)
 }
 
+func TestNodeHostQueryRaftLog(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		cs := nh.GetNoOPSession(2)
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		for _, sz := range []int{16, 32} {
+			if _, err := nh.SyncPropose(ctx, cs, make([]byte, sz)); err != nil {
+				t.Fatalf("make proposal failed %v", err)
+			}
+		}
+		if _, err := nh.QueryRaftLog(3, 1, 100, 0); err != ErrClusterNotFound {
+			t.Errorf("unexpected err %v, want ErrClusterNotFound", err)
+		}
+		if _, err := nh.QueryRaftLog(2, 0, 100, 0); err != ErrInvalidRange {
+			t.Errorf("unexpected err %v, want ErrInvalidRange", err)
+		}
+		if _, err := nh.QueryRaftLog(2, 5, 5, 0); err != ErrInvalidRange {
+			t.Errorf("unexpected err %v, want ErrInvalidRange", err)
+		}
+		ents, err := nh.QueryRaftLog(2, 1, 100, 0)
+		if err != nil {
+			t.Fatalf("failed to query raft log %v", err)
+		}
+		sizes := make([]int, 0)
+		for idx, e := range ents {
+			if e.Index != uint64(idx+1) {
+				t.Errorf("unexpected index %d, want %d", e.Index, idx+1)
+			}
+			if e.IsUpdateEntry() {
+				sizes = append(sizes, len(e.Cmd))
+			}
+		}
+		if len(sizes) != 2 || sizes[0] != 16 || sizes[1] != 32 {
+			t.Errorf("unexpected entries %v", sizes)
+		}
+		last := ents[len(ents)-1].Index
+		ents, err = nh.QueryRaftLog(2, last+1, last+100, 0)
+		if err != nil || len(ents) != 0 {
+			t.Errorf("unexpected result %v, %v", ents, err)
+		}
+		ents, err = nh.QueryRaftLog(2, 1, 100, 1)
+		if err != nil || len(ents) != 1 {
+			t.Errorf("unexpected result %v, %v", ents, err)
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
+func TestNodeHostWatch(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		cs := nh.GetNoOPSession(2)
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if _, err := nh.SyncPropose(ctx, cs, make([]byte, 16)); err != nil {
+			t.Fatalf("make proposal failed %v", err)
+		}
+		if _, err := nh.Watch(3, 0); err != ErrClusterNotFound {
+			t.Errorf("unexpected err %v, want ErrClusterNotFound", err)
+		}
+		w, err := nh.Watch(2, 1)
+		if err != nil {
+			t.Fatalf("failed to watch %v", err)
+		}
+		defer w.Close()
+		if _, err := nh.SyncPropose(ctx, cs, make([]byte, 32)); err != nil {
+			t.Fatalf("make proposal failed %v", err)
+		}
+		var lastIndex uint64
+		for _, sz := range []int{16, 32} {
+			select {
+			case r := <-w.RecordC():
+				if len(r.Cmd) != sz {
+					t.Errorf("unexpected cmd len %d, want %d", len(r.Cmd), sz)
+				}
+				if r.Index <= lastIndex {
+					t.Errorf("unexpected index %d", r.Index)
+				}
+				if sz == 32 && (!r.ResultAvailable || r.Result != 32) {
+					t.Errorf("unexpected result %d", r.Result)
+				}
+				lastIndex = r.Index
+			case <-ctx.Done():
+				t.Fatalf("failed to receive record")
+			}
+		}
+		if err := nh.StopCluster(2); err != nil {
+			t.Fatalf("failed to stop cluster 2 %v", err)
+		}
+		for range w.RecordC() {
+		}
+		if w.Err() != ErrClusterClosed {
+			t.Errorf("unexpected err %v, want ErrClusterClosed", w.Err())
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
+func TestNodeHostWatchFromFutureIndex(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		n, ok := nh.getCluster(2)
+		if !ok {
+			t.Fatalf("failed to get cluster")
+		}
+		from := n.sm.GetLastApplied() + 3
+		w, err := nh.Watch(2, from)
+		if err != nil {
+			t.Fatalf("failed to watch %v", err)
+		}
+		defer w.Close()
+		cs := nh.GetNoOPSession(2)
+		for i := 0; i < 4; i++ {
+			if _, err := nh.SyncPropose(ctx, cs, make([]byte, 16)); err != nil {
+				t.Fatalf("make proposal failed %v", err)
+			}
+		}
+		select {
+		case r := <-w.RecordC():
+			if r.Index != from {
+				t.Errorf("unexpected index %d, want %d", r.Index, from)
+			}
+		case <-ctx.Done():
+			t.Fatalf("failed to receive record")
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
+func TestWatcherIsUnregisteredOnError(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		n, ok := nh.getCluster(2)
+		if !ok {
+			t.Fatalf("failed to get cluster")
+		}
+		applied := n.sm.GetLastApplied()
+		// history beyond the last index in the Raft log fails the history read
+		w := newWatcher(n)
+		w.from = applied
+		w.history = applied + 100
+		n.watchers.add(w)
+		go w.run()
+		for range w.RecordC() {
+		}
+		if w.Err() == nil {
+			t.Errorf("error not set")
+		}
+		n.watchers.mu.Lock()
+		defer n.watchers.mu.Unlock()
+		if _, ok := n.watchers.watchers[w]; ok {
+			t.Errorf("watcher still registered")
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
+func TestSlowWatcherFailsWithoutBlockingPublish(t *testing.T) {
+	n := &node{stopc: make(chan struct{})}
+	w := newWatcher(n)
+	w.liveC = make(chan WatchRecord, 1)
+	w.from = 1
+	n.watchers.add(w)
+	go w.run()
+	for i := uint64(1); i <= 3; i++ {
+		n.watchers.publish(pb.Entry{Index: i, Cmd: make([]byte, 16)}, 0, false)
+	}
+	for range w.RecordC() {
+	}
+	if w.Err() != ErrWatcherOverflow {
+		t.Errorf("unexpected err %v, want ErrWatcherOverflow", w.Err())
+	}
+	n.watchers.mu.Lock()
+	defer n.watchers.mu.Unlock()
+	if len(n.watchers.watchers) != 0 {
+		t.Errorf("watcher still registered")
+	}
+}
+
+func TestNodeHostAwaitAppliedIndex(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		n, ok := nh.getCluster(2)
+		if !ok {
+			t.Fatalf("failed to get cluster")
+		}
+		applied := n.sm.GetLastApplied()
+		if err := nh.AwaitAppliedIndex(ctx, 2, applied); err != nil {
+			t.Errorf("failed to wait for applied index %v", err)
+		}
+		if err := nh.AwaitAppliedIndex(ctx, 3, applied); err != ErrClusterNotFound {
+			t.Errorf("unexpected err %v, want ErrClusterNotFound", err)
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- nh.AwaitAppliedIndex(ctx, 2, applied+1)
+		}()
+		cs := nh.GetNoOPSession(2)
+		if _, err := nh.SyncPropose(ctx, cs, make([]byte, 16)); err != nil {
+			t.Fatalf("make proposal failed %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Errorf("failed to wait for applied index %v", err)
+		}
+		tctx, tcancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer tcancel()
+		if err := nh.AwaitAppliedIndex(tctx, 2, applied+100); err != ErrTimeout {
+			t.Errorf("unexpected err %v, want ErrTimeout", err)
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
 func TestNodeHostAddNode(t *testing.T) {
 	tf := func(t *testing.T, nh *NodeHost) {
 		rs, err := nh.RequestAddNode(2, 2, "localhost:25000", 0, time.Second)
@@ -1110,6 +1344,67 @@ func TestNodeHostAddNode(t *testing.T) {
 	singleNodeHostTest(t, tf)
 }
 
+func TestPlacementConstraintsAreChecked(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	os.RemoveAll(singleNodeHostTestDir)
+	defer os.RemoveAll(singleNodeHostTestDir)
+	zones := map[string]string{
+		"localhost:25000": "zone-1",
+		"localhost:25001": "zone-2",
+	}
+	nhc := getTestNodeHostConfig()
+	nhc.RaftAddress = singleNodeHostTestAddr
+	nhc.Labels = map[string]string{"zone": "zone-1"}
+	nhc.LabelResolver = func(addr string) (map[string]string, bool) {
+		if z, ok := zones[addr]; ok {
+			return map[string]string{"zone": z}, true
+		}
+		return nil, false
+	}
+	nh := NewNodeHost(*nhc)
+	defer nh.Stop()
+	rc := config.Config{
+		NodeID:               1,
+		ClusterID:            2,
+		ElectionRTT:          5,
+		HeartbeatRTT:         1,
+		PlacementConstraints: []config.PlacementConstraint{{Label: "zone"}},
+	}
+	newPST := func(clusterID uint64, nodeID uint64) sm.IStateMachine {
+		return &PST{}
+	}
+	peers := map[uint64]string{
+		1: singleNodeHostTestAddr,
+		2: "localhost:25000",
+		3: "localhost:25001",
+	}
+	if err := nh.StartCluster(peers,
+		false, newPST, rc); err != ErrPlacementConstraintViolated {
+		t.Fatalf("failed to report placement constraint violation, %v", err)
+	}
+	peers = map[uint64]string{1: singleNodeHostTestAddr}
+	if err := nh.StartCluster(peers, false, newPST, rc); err != nil {
+		t.Fatalf("failed to start cluster %v", err)
+	}
+	waitForLeaderToBeElected(t, nh, 2)
+	_, err := nh.RequestAddNode(2, 2, "localhost:25000", 0, time.Second)
+	if err != ErrPlacementConstraintViolated {
+		t.Errorf("failed to report placement constraint violation, %v", err)
+	}
+	_, err = nh.RequestAddNode(2, 2, "localhost:25002", 0, time.Second)
+	if err != ErrPlacementConstraintViolated {
+		t.Errorf("unknown labels not reported, %v", err)
+	}
+	rs, err := nh.RequestAddNode(2, 2, "localhost:25001", 0, time.Second)
+	if err != nil {
+		t.Fatalf("failed to add node %v", err)
+	}
+	v := <-rs.CompletedC
+	if !v.Completed() {
+		t.Errorf("failed to complete add node")
+	}
+}
+
 func TestNodeHostGetNodeUser(t *testing.T) {
 	tf := func(t *testing.T, nh *NodeHost) {
 		n, err := nh.GetNodeUser(2)
@@ -1222,6 +1517,110 @@ func TestNodeHostAddObserverRemoveNode(t *testing.T) {
 	singleNodeHostTest(t, tf)
 }
 
+func TestNodeHostStartClusterUsingUnknownAppName(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		rc := config.Config{
+			NodeID:       1,
+			ClusterID:    3,
+			ElectionRTT:  5,
+			HeartbeatRTT: 1,
+		}
+		peers := map[uint64]string{1: nh.RaftAddress()}
+		err := nh.StartClusterUsingAppName(peers, false, "no-such-app", rc)
+		if err != ErrPluginNotFound {
+			t.Errorf("unexpected err %v, want ErrPluginNotFound", err)
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
+func TestNodeHostSyncPromoteObserver(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := nh.SyncPromoteObserver(ctx, 2, 2); err != ErrNotObserver {
+			t.Errorf("unexpected err %v, want ErrNotObserver", err)
+		}
+		if err := nh.SyncPromoteObserver(ctx, 2, 1); err != nil {
+			t.Errorf("failed to promote regular node %v", err)
+		}
+		rs, err := nh.RequestAddObserver(2, 2, "localhost:25000", 0, time.Second)
+		if err != nil {
+			t.Errorf("failed to add observer %v", err)
+		}
+		v := <-rs.CompletedC
+		if !v.Completed() {
+			t.Errorf("failed to complete add observer")
+		}
+		if err := nh.SyncPromoteObserver(ctx, 2, 2); err != nil {
+			t.Fatalf("failed to promote observer %v", err)
+		}
+		// node 2 is not running, check the local membership as quorum is lost
+		n, ok := nh.getCluster(2)
+		if !ok {
+			t.Fatalf("failed to get cluster")
+		}
+		members, observers, _, _ := n.sm.GetMembership()
+		if len(observers) != 0 {
+			t.Errorf("unexpected observers len")
+		}
+		addr, ok := members[2]
+		if !ok || addr != "localhost:25000" {
+			t.Errorf("node 2 not promoted")
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
+func TestNodeHostReconcileMembership(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		progress := func(p ReconcileProgress) {
+			t.Errorf("unexpected progress %+v", p)
+		}
+		desired := map[uint64]string{1: "localhost:25000"}
+		if err := nh.ReconcileMembership(ctx,
+			2, desired, progress); err != ErrInvalidMembership {
+			t.Errorf("unexpected err %v, want ErrInvalidMembership", err)
+		}
+		desired = map[uint64]string{1: singleNodeHostTestAddr}
+		if err := nh.ReconcileMembership(ctx,
+			100, desired, progress); err != ErrClusterNotFound {
+			t.Errorf("unexpected err %v, want ErrClusterNotFound", err)
+		}
+		if err := nh.ReconcileMembership(ctx, 2, desired, progress); err != nil {
+			t.Errorf("failed to reconcile membership %v", err)
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
+func TestNodeHostGetFencingToken(t *testing.T) {
+	tf := func(t *testing.T, nh *NodeHost) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if _, err := nh.GetFencingToken(ctx, 100); err != ErrClusterNotFound {
+			t.Errorf("unexpected err %v, want ErrClusterNotFound", err)
+		}
+		token, err := nh.GetFencingToken(ctx, 2)
+		if err != nil {
+			t.Fatalf("failed to get fencing token %v", err)
+		}
+		if token.LeaderID != 1 || token.Value() == 0 {
+			t.Errorf("unexpected token %+v", token)
+		}
+		next, err := nh.GetFencingToken(ctx, 2)
+		if err != nil {
+			t.Fatalf("failed to get fencing token %v", err)
+		}
+		if next != token {
+			t.Errorf("token changed, %+v, %+v", next, token)
+		}
+	}
+	singleNodeHostTest(t, tf)
+}
+
 func TestNodeHostLeadershipTransfer(t *testing.T) {
 	tf := func(t *testing.T, nh *NodeHost) {
 		if err := nh.RequestLeaderTransfer(2, 1); err != nil {
@@ -1464,3 +1863,8 @@ func TestRateLimitCanUseFollowerFeedback(t *testing.T) {
 	}
 	rateLimitedTwoNodeHostTest(t, tf)
 }
+
+func TestNodeHostImplementsSyncRequester(t *testing.T) {
+	var _ ISyncProposer = (*NodeHost)(nil)
+	var _ ISyncRequester = (*NodeHost)(nil)
+}
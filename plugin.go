@@ -0,0 +1,140 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dragonboat
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"sync"
+
+	"github.com/lni/dragonboat/internal/utils/fileutil"
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+const (
+	// cpp plugins are always loaded from the working directory
+	cppPluginDir = "."
+)
+
+type pluginDetails struct {
+	appName                      string
+	filepath                     string
+	createRegularStateMachine    func(uint64, uint64) sm.IStateMachine
+	createConcurrentStateMachine func(uint64, uint64) sm.IConcurrentStateMachine
+}
+
+func (pd *pluginDetails) isRegularStateMachine() bool {
+	return pd.createRegularStateMachine != nil
+}
+
+func (pd *pluginDetails) isConcurrentStateMachine() bool {
+	return pd.createConcurrentStateMachine != nil
+}
+
+// pluginManager looks up plugins by their app names. The plugin dir is scanned
+// on each lookup so newly added plugins can be found, each Go plugin is only
+// opened once, those failed to be loaded are logged and skipped.
+type pluginManager struct {
+	mu  sync.Mutex
+	dir string
+	// loaded Go plugins keyed by their file paths, nil for invalid ones
+	loaded map[string]*pluginDetails
+}
+
+func newPluginManager(dir string) *pluginManager {
+	if len(dir) == 0 {
+		dir = "."
+	}
+	return &pluginManager{
+		dir:    dir,
+		loaded: make(map[string]*pluginDetails),
+	}
+}
+
+func (pm *pluginManager) get(appName string) (pluginDetails, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	plugins := make(map[string][]pluginDetails)
+	for _, fn := range fileutil.GetPossibleSOFiles(pm.dir) {
+		cp := filepath.Join(pm.dir, fn)
+		pd, ok := pm.loaded[cp]
+		if !ok {
+			v, err := loadNativePlugin(cp)
+			if err != nil {
+				plog.Errorf("failed to load plugin %s, %v", cp, err)
+			} else {
+				plog.Infof("added a create sm function from %s, appName: %s",
+					cp, v.appName)
+				pd = &v
+			}
+			pm.loaded[cp] = pd
+		}
+		if pd != nil {
+			plugins[pd.appName] = append(plugins[pd.appName], *pd)
+		}
+	}
+	getCppPlugins(cppPluginDir, plugins)
+	result, ok := plugins[appName]
+	if !ok {
+		return pluginDetails{}, ErrPluginNotFound
+	}
+	if len(result) > 1 {
+		return pluginDetails{}, fmt.Errorf(
+			"plugins with the same appName %s already exist", appName)
+	}
+	return result[0], nil
+}
+
+func loadNativePlugin(cp string) (pluginDetails, error) {
+	p, err := plugin.Open(cp)
+	if err != nil {
+		return pluginDetails{}, err
+	}
+	nf, err := p.Lookup("DragonboatApplicationName")
+	if err != nil {
+		return pluginDetails{}, errors.New("no DragonboatApplicationName")
+	}
+	name, ok := nf.(*string)
+	if !ok {
+		return pluginDetails{}, errors.New("invalid DragonboatApplicationName")
+	}
+	pd := pluginDetails{appName: *name, filepath: cp}
+	if csm, err := p.Lookup("CreateStateMachine"); err == nil {
+		pd.createRegularStateMachine, ok =
+			csm.(func(uint64, uint64) sm.IStateMachine)
+	} else if csm, err := p.Lookup("CreateConcurrentStateMachine"); err == nil {
+		pd.createConcurrentStateMachine, ok =
+			csm.(func(uint64, uint64) sm.IConcurrentStateMachine)
+	} else {
+		ok = false
+	}
+	if !ok {
+		return pluginDetails{}, errors.New("no valid create sm function")
+	}
+	return pd, nil
+}
+
+func getCppPlugins(dir string, result map[string][]pluginDetails) {
+	for _, cp := range fileutil.GetPossibleCPPSOFiles(dir) {
+		appName := fileutil.GetAppNameFromFilename(cp)
+		entryName := fmt.Sprintf("cpp-%s", appName)
+		plog.Infof("adding a C++ plugin %s, entryName: %s, appName: %s",
+			cp, entryName, appName)
+		result[entryName] = append(result[entryName],
+			pluginDetails{appName: entryName, filepath: cp})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dragonboat
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testPluginDir = "plugin_test_dir_safe_to_delete"
+)
+
+func TestPluginNotFoundInEmptyDir(t *testing.T) {
+	os.RemoveAll(testPluginDir)
+	if err := os.MkdirAll(testPluginDir, 0755); err != nil {
+		t.Fatalf("failed to create dir %v", err)
+	}
+	defer os.RemoveAll(testPluginDir)
+	pm := newPluginManager(testPluginDir)
+	if _, err := pm.get("test-app"); err != ErrPluginNotFound {
+		t.Errorf("unexpected err %v, want ErrPluginNotFound", err)
+	}
+}
+
+func TestInvalidPluginIsSkipped(t *testing.T) {
+	os.RemoveAll(testPluginDir)
+	if err := os.MkdirAll(testPluginDir, 0755); err != nil {
+		t.Fatalf("failed to create dir %v", err)
+	}
+	defer os.RemoveAll(testPluginDir)
+	fp := filepath.Join(testPluginDir, "dragonboat-plugin-invalid.so")
+	if err := ioutil.WriteFile(fp, []byte("not a plugin"), 0644); err != nil {
+		t.Fatalf("failed to create file %v", err)
+	}
+	pm := newPluginManager(testPluginDir)
+	for i := 0; i < 2; i++ {
+		if _, err := pm.get("test-app"); err != ErrPluginNotFound {
+			t.Errorf("unexpected err %v, want ErrPluginNotFound", err)
+		}
+	}
+	pd, ok := pm.loaded[fp]
+	if !ok {
+		t.Errorf("invalid plugin not recorded")
+	}
+	if pd != nil {
+		t.Errorf("invalid plugin loaded")
+	}
+}
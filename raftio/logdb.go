@@ -136,3 +136,13 @@ type ILogDB interface {
 	// Raft node.
 	ListSnapshots(clusterID uint64, nodeID uint64) ([]pb.Snapshot, error)
 }
+
+// INodeDataRemover is an optional interface that can be implemented by ILogDB
+// instances to support removing data of Raft nodes no longer managed by the
+// NodeHost.
+type INodeDataRemover interface {
+	// RemoveNodeData removes all data associated with the specified Raft node,
+	// including its bootstrap info. It should only be called after the node
+	// has been stopped.
+	RemoveNodeData(clusterID uint64, nodeID uint64) error
+}
@@ -34,6 +34,26 @@ import (
 // have them processed by Dragonboat.
 type RequestHandler func(req pb.MessageBatch)
 
+// TransportMetrics contains counters of received Raft messages and snapshot
+// chunks dropped by the Raft RPC module.
+type TransportMetrics struct {
+	// ChecksumFailures is the number of received messages dropped for failing
+	// the checksum validation, e.g. messages corrupted during transmission.
+	ChecksumFailures uint64
+	// AuthenticationFailures is the number of received messages dropped for
+	// failing the HMAC based authentication, e.g. messages not sent by a
+	// NodeHost knowing the TransportSecret or modified in transit. Replayed
+	// copies of authenticated messages are not detected and not counted.
+	AuthenticationFailures uint64
+}
+
+// IMetricsRaftRPC is the optional interface implemented by Raft RPC modules
+// that keep track of dropped messages.
+type IMetricsRaftRPC interface {
+	// GetMetrics returns the current counters of dropped messages.
+	GetMetrics() TransportMetrics
+}
+
 // ChunkSinkFactory is a factory function that returns a new IChunkSink
 // instance. The returned IChunkSink will be used to accept future received
 // snapshot chunks.
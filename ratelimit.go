@@ -0,0 +1,238 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dragonboat
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/config"
+)
+
+type rateLimitKey struct {
+	clientID uint64
+	key      string
+}
+
+// clientRateLimiter limits the rate of proposals made by each client on a
+// Raft cluster using token buckets. Each Raft cluster has its own
+// clientRateLimiter instance so proposals made to different Raft clusters
+// never contend on the same lock.
+type clientRateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	keyFunc config.ClientRateLimitKeyFunc
+	buckets map[rateLimitKey]*tokenBucket
+	lastGC  time.Time
+}
+
+// newClientRateLimiter returns a clientRateLimiter instance based on the
+// specified NodeHostConfig, nil is returned when rate limiting is disabled.
+func newClientRateLimiter(nhConfig config.NodeHostConfig) *clientRateLimiter {
+	if nhConfig.ClientProposalRate == 0 {
+		return nil
+	}
+	burst := nhConfig.ClientProposalBurst
+	if burst == 0 {
+		burst = nhConfig.ClientProposalRate
+	}
+	return &clientRateLimiter{
+		rate:    float64(nhConfig.ClientProposalRate),
+		burst:   float64(burst),
+		keyFunc: nhConfig.ClientRateLimitKey,
+		buckets: make(map[rateLimitKey]*tokenBucket),
+	}
+}
+
+// allow returns a boolean value indicating whether the specified proposal is
+// allowed to be made. It always returns true when rate limiting is disabled.
+func (r *clientRateLimiter) allow(s *client.Session, cmd []byte) bool {
+	if r == nil {
+		return true
+	}
+	return r.take(r.getKey(s, cmd), time.Now())
+}
+
+// refund returns the token taken by a proposal allowed by the allow method
+// but not made, e.g. when it is rejected for exceeding the cluster quota.
+func (r *clientRateLimiter) refund(s *client.Session, cmd []byte) {
+	if r == nil {
+		return
+	}
+	r.give(r.getKey(s, cmd))
+}
+
+func (r *clientRateLimiter) getKey(s *client.Session,
+	cmd []byte) rateLimitKey {
+	if r.keyFunc != nil {
+		return rateLimitKey{key: r.keyFunc(s.ClusterID, s.ClientID, cmd)}
+	}
+	return rateLimitKey{clientID: s.ClientID}
+}
+
+func (r *clientRateLimiter) take(key rateLimitKey, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gc(now)
+	b, ok := r.buckets[key]
+	if !ok {
+		b = newTokenBucket(r.burst, now)
+		r.buckets[key] = b
+	} else {
+		b.refill(r.rate, r.burst, now)
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (r *clientRateLimiter) give(key rateLimitKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.buckets[key]; ok {
+		b.tokens = math.Min(b.tokens+1, r.burst)
+	}
+}
+
+// gc removes full buckets, they are no different from new ones. This keeps
+// the number of buckets bounded when clients keep using new client sessions.
+func (r *clientRateLimiter) gc(now time.Time) {
+	if now.Sub(r.lastGC) < time.Second {
+		return
+	}
+	r.lastGC = now
+	for key, b := range r.buckets {
+		b.refill(r.rate, r.burst, now)
+		if b.tokens >= r.burst {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// ProposalQuotaMetrics contains counters of proposals rejected by a Raft
+// cluster for exceeding its MaxProposalRate or MaxProposalBytesRate quota.
+type ProposalQuotaMetrics struct {
+	// RejectedProposals is the number of rejected proposals.
+	RejectedProposals uint64
+	// RejectedBytes is the total size of commands of rejected proposals.
+	RejectedBytes uint64
+}
+
+// proposalQuota enforces the MaxProposalRate and MaxProposalBytesRate quotas
+// of a Raft cluster. Each quota allows a burst of one second worth of
+// proposals, a proposal larger than the bytes quota is allowed as long as
+// the quota is not exhausted, the excess is deducted from future quota.
+type proposalQuota struct {
+	rejectedProposals uint64
+	rejectedBytes     uint64
+	mu                sync.Mutex
+	proposalRate      float64
+	bytesRate         float64
+	proposals         *tokenBucket
+	bytes             *tokenBucket
+}
+
+// newProposalQuota returns a proposalQuota instance based on the specified
+// Config, nil is returned when no quota is set.
+func newProposalQuota(cfg config.Config) *proposalQuota {
+	if cfg.MaxProposalRate == 0 && cfg.MaxProposalBytesRate == 0 {
+		return nil
+	}
+	now := time.Now()
+	q := &proposalQuota{
+		proposalRate: float64(cfg.MaxProposalRate),
+		bytesRate:    float64(cfg.MaxProposalBytesRate),
+	}
+	if q.proposalRate > 0 {
+		q.proposals = newTokenBucket(q.proposalRate, now)
+	}
+	if q.bytesRate > 0 {
+		q.bytes = newTokenBucket(q.bytesRate, now)
+	}
+	return q
+}
+
+// allow returns a boolean value indicating whether a proposal with the
+// specified command is allowed to be made. It always returns true when no
+// quota is set.
+func (q *proposalQuota) allow(cmd []byte) bool {
+	if q == nil {
+		return true
+	}
+	return q.take(len(cmd), time.Now())
+}
+
+func (q *proposalQuota) take(size int, now time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	allowed := true
+	if q.proposals != nil {
+		q.proposals.refill(q.proposalRate, q.proposalRate, now)
+		allowed = q.proposals.tokens >= 1
+	}
+	if q.bytes != nil {
+		q.bytes.refill(q.bytesRate, q.bytesRate, now)
+		allowed = allowed && q.bytes.tokens > 0
+	}
+	if !allowed {
+		atomic.AddUint64(&q.rejectedProposals, 1)
+		atomic.AddUint64(&q.rejectedBytes, uint64(size))
+		return false
+	}
+	if q.proposals != nil {
+		q.proposals.tokens--
+	}
+	if q.bytes != nil {
+		q.bytes.tokens -= float64(size)
+	}
+	return true
+}
+
+func (q *proposalQuota) getMetrics() ProposalQuotaMetrics {
+	if q == nil {
+		return ProposalQuotaMetrics{}
+	}
+	return ProposalQuotaMetrics{
+		RejectedProposals: atomic.LoadUint64(&q.rejectedProposals),
+		RejectedBytes:     atomic.LoadUint64(&q.rejectedBytes),
+	}
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newTokenBucket(burst float64, now time.Time) *tokenBucket {
+	return &tokenBucket{tokens: burst, updated: now}
+}
+
+func (b *tokenBucket) refill(rate float64, burst float64, now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.updated = now
+	b.tokens += elapsed * rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dragonboat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/config"
+)
+
+func TestRateLimiterIsNotCreatedWhenDisabled(t *testing.T) {
+	r := newClientRateLimiter(config.NodeHostConfig{})
+	if r != nil {
+		t.Fatalf("rate limiter unexpectedly created")
+	}
+	s := &client.Session{ClusterID: 1, ClientID: 2}
+	for i := 0; i < 1000; i++ {
+		if !r.allow(s, nil) {
+			t.Fatalf("request unexpectedly limited")
+		}
+	}
+}
+
+func TestRateLimiterAllowsBurstThenRefills(t *testing.T) {
+	r := newClientRateLimiter(config.NodeHostConfig{
+		ClientProposalRate:  10,
+		ClientProposalBurst: 5,
+	})
+	key := rateLimitKey{clientID: 2}
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		if !r.take(key, now) {
+			t.Fatalf("request %d unexpectedly limited", i)
+		}
+	}
+	if r.take(key, now) {
+		t.Errorf("burst not limited")
+	}
+	now = now.Add(100 * time.Millisecond)
+	if !r.take(key, now) {
+		t.Errorf("tokens not refilled")
+	}
+	if r.take(key, now) {
+		t.Errorf("too many tokens refilled")
+	}
+	now = now.Add(time.Hour)
+	for i := 0; i < 5; i++ {
+		if !r.take(key, now) {
+			t.Fatalf("request %d unexpectedly limited", i)
+		}
+	}
+	if r.take(key, now) {
+		t.Errorf("tokens more than burst")
+	}
+}
+
+func TestRefundedTokensAreCappedByBurst(t *testing.T) {
+	r := newClientRateLimiter(config.NodeHostConfig{
+		ClientProposalRate:  10,
+		ClientProposalBurst: 5,
+	})
+	key := rateLimitKey{clientID: 2}
+	if !r.take(key, time.Now()) {
+		t.Fatalf("request unexpectedly limited")
+	}
+	r.buckets[key].tokens = 4.5
+	r.give(key)
+	if v := r.buckets[key].tokens; v != 5 {
+		t.Errorf("got %f tokens, want 5", v)
+	}
+}
+
+func TestRateLimiterBurstDefaultsToRate(t *testing.T) {
+	r := newClientRateLimiter(config.NodeHostConfig{ClientProposalRate: 3})
+	key := rateLimitKey{clientID: 2}
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if !r.take(key, now) {
+			t.Fatalf("request %d unexpectedly limited", i)
+		}
+	}
+	if r.take(key, now) {
+		t.Errorf("burst not limited")
+	}
+}
+
+func TestRateLimiterKeysByClient(t *testing.T) {
+	r := newClientRateLimiter(config.NodeHostConfig{ClientProposalRate: 1})
+	s1 := &client.Session{ClusterID: 1, ClientID: 1}
+	if !r.allow(s1, nil) {
+		t.Fatalf("request unexpectedly limited")
+	}
+	if r.allow(s1, nil) {
+		t.Errorf("request not limited")
+	}
+	if !r.allow(&client.Session{ClusterID: 1, ClientID: 2}, nil) {
+		t.Errorf("other client limited")
+	}
+}
+
+func TestRateLimiterUsesKeyFunc(t *testing.T) {
+	keyFunc := func(clusterID uint64, clientID uint64, cmd []byte) string {
+		return string(cmd[:1])
+	}
+	r := newClientRateLimiter(config.NodeHostConfig{
+		ClientProposalRate: 1,
+		ClientRateLimitKey: keyFunc,
+	})
+	if !r.allow(&client.Session{ClusterID: 1, ClientID: 1}, []byte("a1")) {
+		t.Fatalf("request unexpectedly limited")
+	}
+	if r.allow(&client.Session{ClusterID: 1, ClientID: 2}, []byte("a2")) {
+		t.Errorf("request with the same key not limited")
+	}
+	if !r.allow(&client.Session{ClusterID: 1, ClientID: 1}, []byte("b1")) {
+		t.Errorf("request with a different key limited")
+	}
+}
+
+func TestRateLimiterRemovesFullBuckets(t *testing.T) {
+	r := newClientRateLimiter(config.NodeHostConfig{ClientProposalRate: 10})
+	now := time.Now()
+	for i := uint64(0); i < 100; i++ {
+		r.take(rateLimitKey{clientID: i}, now)
+	}
+	if len(r.buckets) != 100 {
+		t.Fatalf("got %d buckets, want 100", len(r.buckets))
+	}
+	now = now.Add(2 * time.Second)
+	r.take(rateLimitKey{clientID: 1000}, now)
+	if len(r.buckets) != 1 {
+		t.Errorf("got %d buckets, want 1", len(r.buckets))
+	}
+}
+
+func TestProposalCanBeRateLimited(t *testing.T) {
+	n := &node{
+		quiesceManager: quiesceManager{clusterID: 1},
+		rateLimiter: newClientRateLimiter(config.NodeHostConfig{
+			ClientProposalRate: 1,
+		}),
+	}
+	s := &client.Session{ClusterID: 1, ClientID: 1}
+	n.rateLimiter.allow(s, nil)
+	if _, err := n.propose(s, nil, nil, time.Second); err != ErrRateLimited {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !IsTempError(ErrRateLimited) {
+		t.Errorf("ErrRateLimited is not a temp error")
+	}
+}
+
+func TestRateLimitTokenIsRefundedWhenQuotaExceeded(t *testing.T) {
+	n := &node{
+		quiesceManager: quiesceManager{clusterID: 1},
+		rateLimiter: newClientRateLimiter(config.NodeHostConfig{
+			ClientProposalRate: 1,
+		}),
+		quota: newProposalQuota(config.Config{MaxProposalRate: 1}),
+	}
+	n.quota.allow(nil)
+	s := &client.Session{ClusterID: 1, ClientID: 1}
+	if _, err := n.propose(s, nil, nil, time.Second); err != ErrQuotaExceeded {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !n.rateLimiter.allow(s, nil) {
+		t.Errorf("rate limit token not refunded")
+	}
+}
+
+func TestProposalQuotaIsNotCreatedWhenDisabled(t *testing.T) {
+	q := newProposalQuota(config.Config{})
+	if q != nil {
+		t.Fatalf("quota unexpectedly created")
+	}
+	if !q.allow(make([]byte, 1024)) {
+		t.Errorf("proposal unexpectedly rejected")
+	}
+	if m := q.getMetrics(); m.RejectedProposals != 0 {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+}
+
+func TestProposalRateQuota(t *testing.T) {
+	q := newProposalQuota(config.Config{MaxProposalRate: 4})
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		if !q.take(16, now) {
+			t.Fatalf("proposal %d unexpectedly rejected", i)
+		}
+	}
+	if q.take(16, now) {
+		t.Errorf("proposal not rejected")
+	}
+	if !q.take(16, now.Add(250*time.Millisecond)) {
+		t.Errorf("quota not refilled")
+	}
+	m := q.getMetrics()
+	if m.RejectedProposals != 1 || m.RejectedBytes != 16 {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+}
+
+func TestProposalBytesRateQuota(t *testing.T) {
+	q := newProposalQuota(config.Config{MaxProposalBytesRate: 100})
+	now := time.Now()
+	if !q.take(60, now) || !q.take(60, now) {
+		t.Fatalf("proposal unexpectedly rejected")
+	}
+	if q.take(1, now) {
+		t.Errorf("proposal not rejected")
+	}
+	// 20 bytes of debt are repaid first
+	if q.take(1, now.Add(200*time.Millisecond)) {
+		t.Errorf("proposal not rejected")
+	}
+	if !q.take(1000, now.Add(300*time.Millisecond)) {
+		t.Errorf("large proposal rejected")
+	}
+	m := q.getMetrics()
+	if m.RejectedProposals != 2 || m.RejectedBytes != 2 {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+}
+
+func TestProposalQuotaRequiresAllQuotas(t *testing.T) {
+	q := newProposalQuota(config.Config{
+		MaxProposalRate:      100,
+		MaxProposalBytesRate: 10,
+	})
+	now := time.Now()
+	if !q.take(10, now) {
+		t.Fatalf("proposal unexpectedly rejected")
+	}
+	if q.take(10, now) {
+		t.Errorf("bytes quota not enforced")
+	}
+	if q.proposals.tokens != 99 {
+		t.Errorf("rejected proposal consumed quota")
+	}
+}
@@ -69,6 +69,13 @@ var (
 	ErrCanceled = errors.New("request canceled")
 	// ErrRejected indicates that the request has been rejected.
 	ErrRejected = errors.New("request rejected")
+	// ErrRateLimited indicates that the request has been rejected as the client
+	// exceeded its ClientProposalRate limit.
+	ErrRateLimited = errors.New("request rate limited")
+	// ErrQuotaExceeded indicates that the proposal has been rejected as the
+	// MaxProposalRate or MaxProposalBytesRate quota of the Raft cluster has been
+	// exceeded.
+	ErrQuotaExceeded = errors.New("proposal quota exceeded")
 )
 
 // IsTempError returns a boolean value indicating whether the specified error
@@ -79,7 +86,10 @@ func IsTempError(err error) bool {
 		err == ErrBadKey ||
 		err == ErrPendingConfigChangeExist ||
 		err == ErrClusterClosed ||
-		err == ErrSystemStopped
+		err == ErrSystemStopped ||
+		err == ErrRateLimited ||
+		err == ErrQuotaExceeded ||
+		err == ErrNotLeader
 }
 
 // RequestResultCode is the result code returned to the client to indicate the
@@ -300,6 +310,42 @@ type pendingConfigChange struct {
 	logicalClock
 }
 
+type pendingAppliedIndex struct {
+	mu      sync.Mutex
+	waiters map[chan struct{}]uint64
+}
+
+func newPendingAppliedIndex() *pendingAppliedIndex {
+	return &pendingAppliedIndex{
+		waiters: make(map[chan struct{}]uint64),
+	}
+}
+
+func (p *pendingAppliedIndex) add(index uint64) chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c := make(chan struct{})
+	p.waiters[c] = index
+	return c
+}
+
+func (p *pendingAppliedIndex) remove(c chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.waiters, c)
+}
+
+func (p *pendingAppliedIndex) applied(applied uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for c, index := range p.waiters {
+		if index <= applied {
+			close(c)
+			delete(p.waiters, c)
+		}
+	}
+}
+
 func newPendingConfigChange(confChangeC chan<- *RequestState,
 	tickInMillisecond uint64) *pendingConfigChange {
 	gcTick := defaultGCTick
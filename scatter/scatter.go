@@ -0,0 +1,205 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package scatter implements scatter/gather helpers for applications that shard
+their data across many Raft clusters managed by dragonboat.
+
+A Scatter fans proposals or linearizable reads out to many Raft clusters
+concurrently, with the number of in flight requests bounded by the
+MaxConcurrency option and each request individually bounded by the Timeout
+option. Requests made to different Raft clusters are independent, results of
+all requests are always returned so callers can make use of partial results
+when some of the requests failed.
+*/
+package scatter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lni/dragonboat"
+)
+
+const (
+	// DefaultMaxConcurrency is the max number of in flight requests used when
+	// the MaxConcurrency option is not set.
+	DefaultMaxConcurrency = 16
+)
+
+// Options are the options used by Scatter.
+type Options struct {
+	// MaxConcurrency is the max number of in flight requests. The default
+	// DefaultMaxConcurrency is used when it is not set.
+	MaxConcurrency int
+	// Timeout is the timeout of each individual request. When it is not set,
+	// requests are only bounded by the deadline of the context specified when
+	// calling Propose or Read.
+	Timeout time.Duration
+}
+
+// Result is the result of a request made to a Raft cluster.
+type Result struct {
+	// ClusterID is the ID of the Raft cluster the request was made to.
+	ClusterID uint64
+	// Value is the result returned by the Update method of the state machine
+	// for proposals.
+	Value uint64
+	// Data is the result returned by the Lookup method of the state machine
+	// for reads.
+	Data []byte
+	// Err is the error encountered when making the request.
+	Err error
+}
+
+// Results is a list of Result sorted by ClusterID.
+type Results []Result
+
+// Err returns the first error found in the results, nil is returned when all
+// requests completed successfully.
+func (r Results) Err() error {
+	for _, v := range r {
+		if v.Err != nil {
+			return v.Err
+		}
+	}
+	return nil
+}
+
+// Succeeded returns results of requests completed successfully.
+func (r Results) Succeeded() Results {
+	result := make(Results, 0, len(r))
+	for _, v := range r {
+		if v.Err == nil {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Failed returns results of failed requests.
+func (r Results) Failed() Results {
+	result := make(Results, 0)
+	for _, v := range r {
+		if v.Err != nil {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Scatter makes requests to many Raft clusters concurrently.
+type Scatter struct {
+	nh   dragonboat.ISyncRequester
+	opts Options
+}
+
+// New returns a new Scatter instance which makes requests using the specified
+// NodeHost instance.
+func New(nh dragonboat.ISyncRequester, opts Options) *Scatter {
+	if opts.MaxConcurrency <= 0 {
+		opts.MaxConcurrency = DefaultMaxConcurrency
+	}
+	return &Scatter{nh: nh, opts: opts}
+}
+
+// Propose makes the specified proposals, a map of ClusterID values to
+// commands, using NO-OP client sessions. It blocks until all proposals are
+// completed or failed, results are returned sorted by ClusterID. Proposals not
+// yet made when the context is done fail with dragonboat.ErrTimeout or
+// dragonboat.ErrCanceled.
+func (s *Scatter) Propose(ctx context.Context,
+	cmds map[uint64][]byte) Results {
+	return s.run(ctx, getClusterIDs(cmds),
+		func(ctx context.Context, r *Result) {
+			session := s.nh.GetNoOPSession(r.ClusterID)
+			r.Value, r.Err = s.nh.SyncPropose(ctx, session, cmds[r.ClusterID])
+		})
+}
+
+// Read performs the specified linearizable reads, a map of ClusterID values
+// to queries. It blocks until all reads are completed or failed, results are
+// returned sorted by ClusterID. Reads not yet performed when the context is
+// done fail with dragonboat.ErrTimeout or dragonboat.ErrCanceled.
+func (s *Scatter) Read(ctx context.Context,
+	queries map[uint64][]byte) Results {
+	return s.run(ctx, getClusterIDs(queries),
+		func(ctx context.Context, r *Result) {
+			r.Data, r.Err = s.nh.SyncRead(ctx, r.ClusterID, queries[r.ClusterID])
+		})
+}
+
+func (s *Scatter) run(ctx context.Context, clusterIDs []uint64,
+	f func(ctx context.Context, r *Result)) Results {
+	results := make(Results, len(clusterIDs))
+	sem := make(chan struct{}, s.opts.MaxConcurrency)
+	var wg sync.WaitGroup
+	for idx, clusterID := range clusterIDs {
+		results[idx].ClusterID = clusterID
+		if err := getContextError(ctx); err != nil {
+			results[idx].Err = err
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[idx].Err = getContextError(ctx)
+			continue
+		}
+		wg.Add(1)
+		go func(r *Result) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			rctx, cancel := s.getRequestContext(ctx)
+			defer cancel()
+			f(rctx, r)
+		}(&results[idx])
+	}
+	wg.Wait()
+	return results
+}
+
+func (s *Scatter) getRequestContext(ctx context.Context) (context.Context,
+	context.CancelFunc) {
+	if s.opts.Timeout > 0 {
+		return context.WithTimeout(ctx, s.opts.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func getContextError(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.Canceled:
+		return dragonboat.ErrCanceled
+	default:
+		return dragonboat.ErrTimeout
+	}
+}
+
+func getClusterIDs(requests map[uint64][]byte) []uint64 {
+	clusterIDs := make([]uint64, 0, len(requests))
+	for clusterID := range requests {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	sort.Slice(clusterIDs, func(i, j int) bool {
+		return clusterIDs[i] < clusterIDs[j]
+	})
+	return clusterIDs
+}
@@ -0,0 +1,206 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scatter
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/internal/utils/random"
+)
+
+type testNodeHost struct {
+	mu       sync.Mutex
+	inflight int
+	max      int
+	delay    time.Duration
+	failed   map[uint64]bool
+	blocked  map[uint64]bool
+}
+
+func newTestNodeHost(delay time.Duration) *testNodeHost {
+	return &testNodeHost{
+		delay:   delay,
+		failed:  make(map[uint64]bool),
+		blocked: make(map[uint64]bool),
+	}
+}
+
+func (nh *testNodeHost) enter() {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	nh.inflight++
+	if nh.inflight > nh.max {
+		nh.max = nh.inflight
+	}
+}
+
+func (nh *testNodeHost) exit() {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	nh.inflight--
+}
+
+func (nh *testNodeHost) request(ctx context.Context, clusterID uint64) error {
+	nh.enter()
+	defer nh.exit()
+	nh.mu.Lock()
+	failed := nh.failed[clusterID]
+	blocked := nh.blocked[clusterID]
+	nh.mu.Unlock()
+	if blocked {
+		<-ctx.Done()
+		return dragonboat.ErrTimeout
+	}
+	time.Sleep(nh.delay)
+	if failed {
+		return dragonboat.ErrClusterNotFound
+	}
+	return nil
+}
+
+func (nh *testNodeHost) SyncPropose(ctx context.Context,
+	session *client.Session, cmd []byte) (uint64, error) {
+	if err := nh.request(ctx, session.ClusterID); err != nil {
+		return 0, err
+	}
+	return uint64(len(cmd)), nil
+}
+
+func (nh *testNodeHost) SyncRead(ctx context.Context,
+	clusterID uint64, query []byte) ([]byte, error) {
+	if err := nh.request(ctx, clusterID); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+func (nh *testNodeHost) GetNoOPSession(clusterID uint64) *client.Session {
+	return client.NewNoOPSession(clusterID, random.LockGuardedRand)
+}
+
+func getTestRequests(count uint64) map[uint64][]byte {
+	requests := make(map[uint64][]byte)
+	for i := uint64(1); i <= count; i++ {
+		requests[i] = make([]byte, i)
+	}
+	return requests
+}
+
+func TestProposalsAreMadeToAllClusters(t *testing.T) {
+	nh := newTestNodeHost(0)
+	s := New(nh, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	results := s.Propose(ctx, getTestRequests(32))
+	if len(results) != 32 {
+		t.Fatalf("got %d results, want 32", len(results))
+	}
+	if err := results.Err(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for idx, r := range results {
+		if r.ClusterID != uint64(idx+1) {
+			t.Errorf("results not sorted by cluster id")
+		}
+		if r.Value != r.ClusterID {
+			t.Errorf("cluster %d, got %d, want %d", r.ClusterID, r.Value, r.ClusterID)
+		}
+	}
+}
+
+func TestReadsAreMadeToAllClusters(t *testing.T) {
+	nh := newTestNodeHost(0)
+	s := New(nh, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	queries := getTestRequests(8)
+	results := s.Read(ctx, queries)
+	if err := results.Err(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, r := range results {
+		if !bytes.Equal(r.Data, queries[r.ClusterID]) {
+			t.Errorf("cluster %d, unexpected data", r.ClusterID)
+		}
+	}
+}
+
+func TestConcurrencyIsBounded(t *testing.T) {
+	nh := newTestNodeHost(5 * time.Millisecond)
+	s := New(nh, Options{MaxConcurrency: 3})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Propose(ctx, getTestRequests(20)).Err(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if nh.max > 3 {
+		t.Errorf("%d concurrent requests, want <= 3", nh.max)
+	}
+	if nh.max < 2 {
+		t.Errorf("requests are not concurrent")
+	}
+}
+
+func TestPartialResultsAreReturned(t *testing.T) {
+	nh := newTestNodeHost(0)
+	nh.failed[2] = true
+	nh.blocked[3] = true
+	s := New(nh, Options{Timeout: 50 * time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	results := s.Propose(ctx, getTestRequests(4))
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("per request timeout not applied")
+	}
+	if results.Err() != dragonboat.ErrClusterNotFound {
+		t.Errorf("unexpected error %v", results.Err())
+	}
+	failed := results.Failed()
+	if len(failed) != 2 ||
+		failed[0].ClusterID != 2 || failed[1].ClusterID != 3 {
+		t.Fatalf("unexpected failed results %v", failed)
+	}
+	if failed[1].Err != dragonboat.ErrTimeout {
+		t.Errorf("unexpected error %v", failed[1].Err)
+	}
+	succeeded := results.Succeeded()
+	if len(succeeded) != 2 ||
+		succeeded[0].ClusterID != 1 || succeeded[1].ClusterID != 4 {
+		t.Errorf("unexpected succeeded results %v", succeeded)
+	}
+}
+
+func TestRequestsFailWhenContextIsDone(t *testing.T) {
+	nh := newTestNodeHost(0)
+	s := New(nh, Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := s.Read(ctx, getTestRequests(4))
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	for _, r := range results {
+		if r.Err != dragonboat.ErrCanceled {
+			t.Errorf("cluster %d, unexpected error %v", r.ClusterID, r.Err)
+		}
+	}
+}
@@ -25,7 +25,9 @@ For in memory state machines which in general have low read/write latencies,
 IStateMachine is usually employed. The major drawback is that each IStateMachine
 is internally guarded by a sync.RWMutex so read/write accesses are not allowed
 to be concurrently invoked on IStateMachine. Multiple read requests can be
-concurrently invoked on the same IStateMachine instance.
+concurrently invoked on the same IStateMachine instance. IStateMachine types
+that can be cheaply copied can also implement the ICloneableStateMachine
+interface so saving snapshots no longer blocks Update().
 
 For IConcurrentStateMachine based application state machine, the major
 difference is that read/write accesses can be concurrently invoked. This allows
@@ -375,3 +377,74 @@ type IConcurrentStateMachine interface {
 	// GetHash is a read only method on the IConcurrentStateMachine instance.
 	GetHash() uint64
 }
+
+// ICloneableStateMachine is an optional interface that can be implemented by
+// IStateMachine instances to stop snapshotting from blocking the Update()
+// method. Instead of saving the snapshot from the IStateMachine instance
+// itself, a shadow copy of the IStateMachine instance is created by the
+// Clone() method and the snapshot is saved from that copy concurrent to the
+// Update() method. Such copy should thus be cheap to create when compared to
+// saving the snapshot, e.g. by copying a small in memory data structure or by
+// employing copy-on-write data structures.
+//
+// Lookup() and Update() are still not allowed to be concurrently invoked on
+// the IStateMachine instance.
+type ICloneableStateMachine interface {
+	IStateMachine
+	// Clone returns a copy of the IStateMachine instance. The returned copy
+	// must not share any mutable state with the IStateMachine instance, only
+	// its SaveSnapshot() method is invoked and it is invoked concurrent to the
+	// Update() method of the IStateMachine instance. The Close() method of the
+	// returned copy is never invoked.
+	//
+	// Clone() is a read only method on the IStateMachine instance, it is
+	// invoked with mutual exclusion protection from the Update() method.
+	Clone() IStateMachine
+}
+
+// CloneFunc is the function used for creating a copy of the specified
+// IStateMachine instance as required by the Clone() method of the
+// ICloneableStateMachine interface.
+type CloneFunc func(IStateMachine) IStateMachine
+
+type cloneableStateMachine struct {
+	IStateMachine
+	clone CloneFunc
+}
+
+// NewCloneableStateMachine returns an ICloneableStateMachine instance which
+// uses the specified CloneFunc to create copies of the specified IStateMachine
+// instance.
+func NewCloneableStateMachine(s IStateMachine,
+	clone CloneFunc) ICloneableStateMachine {
+	return &cloneableStateMachine{IStateMachine: s, clone: clone}
+}
+
+func (s *cloneableStateMachine) Clone() IStateMachine {
+	return s.clone(s.IStateMachine)
+}
+
+// Busy forwards the call to the wrapped IStateMachine when it implements
+// the IBusyStateMachine interface.
+func (s *cloneableStateMachine) Busy() bool {
+	if b, ok := s.IStateMachine.(IBusyStateMachine); ok {
+		return b.Busy()
+	}
+	return false
+}
+
+// IBusyStateMachine is an optional interface that can be implemented by
+// IStateMachine and IConcurrentStateMachine instances to temporarily slow
+// down the delivery of committed entries, e.g. when the state machine is busy
+// compacting its internal data structures. While the state machine reports
+// itself as busy, committed entries are delivered to the Update() method in
+// smaller batches at a lower rate, new entries are kept in the Raft log
+// rather than queued in memory for the state machine. Proposals fail with
+// ErrSystemBusy when the MaxInMemLogSize limit is reached as a result.
+type IBusyStateMachine interface {
+	// Busy returns a boolean value indicating whether the state machine is
+	// busy. Busy() is frequently invoked from the goroutine invoking the
+	// Update() method, it can be invoked concurrent to the Lookup() and
+	// SaveSnapshot() methods. It should be cheap, e.g. by checking a flag.
+	Busy() bool
+}
@@ -0,0 +1,427 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/config"
+	"github.com/lni/dragonboat/internal/transport"
+	"github.com/lni/dragonboat/raftio"
+	pb "github.com/lni/dragonboat/raftpb"
+)
+
+const (
+	chaosPollInterval = 100 * time.Millisecond
+)
+
+var (
+	// ErrNodeHostStopped indicates that the specified NodeHost is not running.
+	ErrNodeHostStopped = errors.New("nodehost stopped")
+	// ErrNodeHostRunning indicates that the specified NodeHost is running.
+	ErrNodeHostRunning = errors.New("nodehost running")
+	errPartitioned     = errors.New("partitioned by the orchestrator")
+)
+
+// StartFunc is invoked each time a NodeHost is created by the Orchestrator,
+// it is expected to start Raft clusters on the specified NodeHost. The idx
+// parameter is the index of the NodeHost in the Orchestrator.
+type StartFunc func(idx int, nh *dragonboat.NodeHost) error
+
+// Orchestrator manages a group of NodeHost instances running in the current
+// process and injects faults into them. It can stop and restart NodeHosts,
+// drop all data of stopped NodeHosts to simulate whole disk loss, partition
+// the network between NodeHosts and wait for Raft clusters to converge. Disk
+// faults on running NodeHosts, e.g. failed or torn writes, are not simulated.
+//
+// The Raft RPC module of each NodeHost is wrapped to drop messages sent
+// across network partitions, the RaftRPCFactory field of the NodeHostConfig
+// is honored when it is set.
+type Orchestrator struct {
+	mu      sync.Mutex
+	start   StartFunc
+	configs []config.NodeHostConfig
+	hosts   []*dragonboat.NodeHost
+	network *network
+}
+
+// NewOrchestrator creates NodeHost instances using the specified configs and
+// starts Raft clusters on them using the specified StartFunc.
+func NewOrchestrator(configs []config.NodeHostConfig,
+	start StartFunc) (*Orchestrator, error) {
+	o := &Orchestrator{
+		start:   start,
+		configs: make([]config.NodeHostConfig, len(configs)),
+		hosts:   make([]*dragonboat.NodeHost, len(configs)),
+		network: newNetwork(),
+	}
+	for idx, nhc := range configs {
+		nhc.RaftRPCFactory = o.network.wrap(nhc.RaftRPCFactory)
+		o.configs[idx] = nhc
+	}
+	for idx := range configs {
+		if err := o.Restart(idx); err != nil {
+			o.Close()
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// Len returns the number of NodeHosts managed by the Orchestrator.
+func (o *Orchestrator) Len() int {
+	return len(o.configs)
+}
+
+// NodeHost returns the specified NodeHost instance, nil is returned when it
+// is not running.
+func (o *Orchestrator) NodeHost(idx int) *dragonboat.NodeHost {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.hosts[idx]
+}
+
+// Stop stops the specified NodeHost.
+func (o *Orchestrator) Stop(idx int) error {
+	o.mu.Lock()
+	nh := o.hosts[idx]
+	o.hosts[idx] = nil
+	o.mu.Unlock()
+	if nh == nil {
+		return ErrNodeHostStopped
+	}
+	nh.Stop()
+	return nil
+}
+
+// Restart creates a new NodeHost instance for the specified stopped NodeHost
+// and starts Raft clusters on it using the StartFunc.
+func (o *Orchestrator) Restart(idx int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.hosts[idx] != nil {
+		return ErrNodeHostRunning
+	}
+	nh := dragonboat.NewNodeHost(o.configs[idx])
+	if err := o.start(idx, nh); err != nil {
+		nh.Stop()
+		return err
+	}
+	o.hosts[idx] = nh
+	return nil
+}
+
+// DropDisk stops the specified NodeHost when it is running and removes all
+// its data to simulate the loss of the whole disk. The NodeHost is not
+// restarted. Only whole disk loss on a stopped NodeHost is simulated, no I/O
+// error is ever injected into a running NodeHost.
+func (o *Orchestrator) DropDisk(idx int) error {
+	if err := o.Stop(idx); err != nil && err != ErrNodeHostStopped {
+		return err
+	}
+	nhc := o.configs[idx]
+	for _, dir := range []string{nhc.NodeHostDir, nhc.WALDir} {
+		if len(dir) == 0 {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Partition partitions the network so NodeHosts can only communicate with
+// NodeHosts in the same group. Each group is a list of NodeHost indexes,
+// NodeHosts not included in any group are isolated. It replaces any existing
+// partition.
+func (o *Orchestrator) Partition(groups ...[]int) {
+	group := make(map[string]int)
+	for gid, g := range groups {
+		for _, idx := range g {
+			group[o.configs[idx].RaftAddress] = gid + 1
+		}
+	}
+	o.network.partition(group)
+}
+
+// Isolate isolates the specified NodeHost from all other NodeHosts.
+func (o *Orchestrator) Isolate(idx int) {
+	var others []int
+	for i := range o.configs {
+		if i != idx {
+			others = append(others, i)
+		}
+	}
+	o.Partition(others)
+}
+
+// Heal removes the network partition.
+func (o *Orchestrator) Heal() {
+	o.network.partition(nil)
+}
+
+// Close stops all running NodeHosts.
+func (o *Orchestrator) Close() {
+	for idx := range o.configs {
+		if err := o.Stop(idx); err != nil && err != ErrNodeHostStopped {
+			plog.Errorf("failed to stop nodehost %d, %v", idx, err)
+		}
+	}
+}
+
+// WaitForLeader waits until all running NodeHosts that host the specified
+// Raft cluster agree on the same leader. When the network is partitioned,
+// only NodeHosts in the group containing more than half of all NodeHosts are
+// considered, the Raft cluster is assumed to have a node on each NodeHost.
+// The NodeHost index of the leader is returned.
+func (o *Orchestrator) WaitForLeader(ctx context.Context,
+	clusterID uint64) (int, error) {
+	for {
+		if idx, ok := o.getLeader(clusterID); ok {
+			return idx, nil
+		}
+		if err := o.wait(ctx); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// WaitForConvergence waits until all running NodeHosts that host the
+// specified Raft cluster return the same result when the specified query is
+// linearizably read from the local replica, e.g. a query returning the hash
+// of the state machine. It should be called after the network partition is
+// healed and the workload is stopped.
+func (o *Orchestrator) WaitForConvergence(ctx context.Context,
+	clusterID uint64, query []byte) error {
+	for {
+		if o.converged(ctx, clusterID, query) {
+			return nil
+		}
+		if err := o.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (o *Orchestrator) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		if ctx.Err() == context.Canceled {
+			return dragonboat.ErrCanceled
+		}
+		return dragonboat.ErrTimeout
+	case <-time.After(chaosPollInterval):
+		return nil
+	}
+}
+
+func (o *Orchestrator) getLeader(clusterID uint64) (int, bool) {
+	var leaderID uint64
+	found := false
+	for _, nh := range o.getRunningNodeHosts() {
+		if !o.network.inMajority(nh.RaftAddress(), len(o.configs)) {
+			continue
+		}
+		v, valid, err := nh.GetLeaderID(clusterID)
+		if err == dragonboat.ErrClusterNotFound {
+			continue
+		}
+		if err != nil || !valid || (found && v != leaderID) {
+			return 0, false
+		}
+		leaderID = v
+		found = true
+	}
+	if !found {
+		return 0, false
+	}
+	for idx, nh := range o.getRunningNodeHosts() {
+		if nh.HasNodeInfo(clusterID, leaderID) {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+func (o *Orchestrator) converged(ctx context.Context,
+	clusterID uint64, query []byte) bool {
+	var result []byte
+	found := false
+	for _, nh := range o.getRunningNodeHosts() {
+		rctx, cancel := context.WithTimeout(ctx, time.Second)
+		v, err := nh.SyncRead(rctx, clusterID, query)
+		cancel()
+		if err == dragonboat.ErrClusterNotFound {
+			continue
+		}
+		if err != nil || (found && !bytes.Equal(v, result)) {
+			return false
+		}
+		result = v
+		found = true
+	}
+	return found
+}
+
+// getRunningNodeHosts returns a map of NodeHost indexes to running NodeHost
+// instances.
+func (o *Orchestrator) getRunningNodeHosts() map[int]*dragonboat.NodeHost {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	result := make(map[int]*dragonboat.NodeHost)
+	for idx, nh := range o.hosts {
+		if nh != nil {
+			result[idx] = nh
+		}
+	}
+	return result
+}
+
+// network tracks the network partition, addresses are mapped to group IDs,
+// NodeHosts can only communicate with others in the same group. No partition
+// is in effect when the map is nil.
+type network struct {
+	mu     sync.RWMutex
+	groups map[string]int
+}
+
+func newNetwork() *network {
+	return &network{}
+}
+
+func (n *network) partition(groups map[string]int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.groups = groups
+}
+
+func (n *network) blocked(from string, to string) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.groups == nil {
+		return false
+	}
+	g, ok := n.groups[from]
+	return !ok || g != n.groups[to]
+}
+
+// inMajority returns a boolean value indicating whether the specified address
+// is in the group that contains more than half of all total NodeHosts.
+func (n *network) inMajority(addr string, total int) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.groups == nil {
+		return true
+	}
+	g, ok := n.groups[addr]
+	if !ok {
+		return false
+	}
+	count := 0
+	for _, v := range n.groups {
+		if v == g {
+			count++
+		}
+	}
+	return count*2 > total
+}
+
+func (n *network) wrap(f config.RaftRPCFactoryFunc) config.RaftRPCFactoryFunc {
+	if f == nil {
+		f = transport.NewTCPTransport
+	}
+	return func(nhc config.NodeHostConfig, handler raftio.RequestHandler,
+		sinkFactory raftio.ChunkSinkFactory) raftio.IRaftRPC {
+		return &chaosRPC{
+			IRaftRPC: f(nhc, handler, sinkFactory),
+			from:     nhc.RaftAddress,
+			network:  n,
+		}
+	}
+}
+
+type chaosRPC struct {
+	raftio.IRaftRPC
+	from    string
+	network *network
+}
+
+func (c *chaosRPC) GetConnection(ctx context.Context,
+	target string) (raftio.IConnection, error) {
+	if c.network.blocked(c.from, target) {
+		return nil, errPartitioned
+	}
+	conn, err := c.IRaftRPC.GetConnection(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return &chaosConnection{IConnection: conn, rpc: c, target: target}, nil
+}
+
+func (c *chaosRPC) GetSnapshotConnection(ctx context.Context,
+	target string) (raftio.ISnapshotConnection, error) {
+	if c.network.blocked(c.from, target) {
+		return nil, errPartitioned
+	}
+	conn, err := c.IRaftRPC.GetSnapshotConnection(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return &chaosSnapshotConnection{
+		ISnapshotConnection: conn,
+		rpc:                 c,
+		target:              target,
+	}, nil
+}
+
+func (c *chaosRPC) GetMetrics() raftio.TransportMetrics {
+	if m, ok := c.IRaftRPC.(raftio.IMetricsRaftRPC); ok {
+		return m.GetMetrics()
+	}
+	return raftio.TransportMetrics{}
+}
+
+type chaosConnection struct {
+	raftio.IConnection
+	rpc    *chaosRPC
+	target string
+}
+
+func (c *chaosConnection) SendMessageBatch(batch pb.MessageBatch) error {
+	if c.rpc.network.blocked(c.rpc.from, c.target) {
+		return errPartitioned
+	}
+	return c.IConnection.SendMessageBatch(batch)
+}
+
+type chaosSnapshotConnection struct {
+	raftio.ISnapshotConnection
+	rpc    *chaosRPC
+	target string
+}
+
+func (c *chaosSnapshotConnection) SendSnapshotChunk(chunk pb.SnapshotChunk) error {
+	if c.rpc.network.blocked(c.rpc.from, c.target) {
+		return errPartitioned
+	}
+	return c.ISnapshotConnection.SendSnapshotChunk(chunk)
+}
@@ -0,0 +1,198 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/config"
+	"github.com/lni/dragonboat/raftio"
+	pb "github.com/lni/dragonboat/raftpb"
+)
+
+type testRPC struct {
+	sent   map[string]int
+	chunks map[string]int
+}
+
+func (r *testRPC) Name() string { return "test-rpc" }
+func (r *testRPC) Start() error { return nil }
+func (r *testRPC) Stop()        {}
+func (r *testRPC) GetConnection(ctx context.Context,
+	target string) (raftio.IConnection, error) {
+	return &testConnection{r: r, target: target}, nil
+}
+func (r *testRPC) GetSnapshotConnection(ctx context.Context,
+	target string) (raftio.ISnapshotConnection, error) {
+	return &testConnection{r: r, target: target}, nil
+}
+
+type testConnection struct {
+	r      *testRPC
+	target string
+}
+
+func (c *testConnection) Close() {}
+func (c *testConnection) SendMessageBatch(batch pb.MessageBatch) error {
+	c.r.sent[c.target]++
+	return nil
+}
+func (c *testConnection) SendSnapshotChunk(chunk pb.SnapshotChunk) error {
+	c.r.chunks[c.target]++
+	return nil
+}
+
+func getTestOrchestrator() (*Orchestrator, *testRPC) {
+	rpc := &testRPC{sent: make(map[string]int), chunks: make(map[string]int)}
+	o := &Orchestrator{
+		configs: []config.NodeHostConfig{
+			{RaftAddress: "a1:1"},
+			{RaftAddress: "a2:2"},
+			{RaftAddress: "a3:3"},
+		},
+		hosts:   make([]*dragonboat.NodeHost, 3),
+		network: newNetwork(),
+	}
+	return o, rpc
+}
+
+func getTestChaosRPC(o *Orchestrator,
+	rpc *testRPC, idx int) raftio.IRaftRPC {
+	f := func(config.NodeHostConfig,
+		raftio.RequestHandler, raftio.ChunkSinkFactory) raftio.IRaftRPC {
+		return rpc
+	}
+	return o.network.wrap(f)(o.configs[idx], nil, nil)
+}
+
+func TestPartitionedMessagesAreDropped(t *testing.T) {
+	o, rpc := getTestOrchestrator()
+	r := getTestChaosRPC(o, rpc, 0)
+	conn, err := r.GetConnection(context.Background(), "a2:2")
+	if err != nil {
+		t.Fatalf("failed to get connection %v", err)
+	}
+	if err := conn.SendMessageBatch(pb.MessageBatch{}); err != nil {
+		t.Fatalf("failed to send %v", err)
+	}
+	o.Partition([]int{0}, []int{1, 2})
+	if err := conn.SendMessageBatch(pb.MessageBatch{}); err != errPartitioned {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := r.GetConnection(context.Background(), "a3:3"); err != errPartitioned {
+		t.Errorf("unexpected error %v", err)
+	}
+	sconn, err := r.GetSnapshotConnection(context.Background(), "a2:2")
+	if err != errPartitioned || sconn != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	o.Heal()
+	if err := conn.SendMessageBatch(pb.MessageBatch{}); err != nil {
+		t.Fatalf("failed to send %v", err)
+	}
+	if rpc.sent["a2:2"] != 2 {
+		t.Errorf("sent %d, want 2", rpc.sent["a2:2"])
+	}
+	sconn, err = r.GetSnapshotConnection(context.Background(), "a2:2")
+	if err != nil {
+		t.Fatalf("failed to get snapshot connection %v", err)
+	}
+	if err := sconn.SendSnapshotChunk(pb.SnapshotChunk{}); err != nil {
+		t.Fatalf("failed to send chunk %v", err)
+	}
+	o.Isolate(1)
+	if err := sconn.SendSnapshotChunk(pb.SnapshotChunk{}); err != errPartitioned {
+		t.Errorf("unexpected error %v", err)
+	}
+	if rpc.chunks["a2:2"] != 1 {
+		t.Errorf("chunks %d, want 1", rpc.chunks["a2:2"])
+	}
+}
+
+func TestIsolatedNodeHostCanNotCommunicate(t *testing.T) {
+	o, _ := getTestOrchestrator()
+	o.Isolate(2)
+	tests := []struct {
+		from    int
+		to      int
+		blocked bool
+	}{
+		{0, 1, false},
+		{1, 0, false},
+		{0, 2, true},
+		{2, 0, true},
+		{2, 1, true},
+	}
+	for idx, tt := range tests {
+		from := o.configs[tt.from].RaftAddress
+		to := o.configs[tt.to].RaftAddress
+		if v := o.network.blocked(from, to); v != tt.blocked {
+			t.Errorf("%d, blocked %t, want %t", idx, v, tt.blocked)
+		}
+	}
+	if o.network.inMajority("a3:3", 3) || !o.network.inMajority("a1:1", 3) {
+		t.Errorf("unexpected majority state")
+	}
+	o.Heal()
+	if o.network.blocked("a3:3", "a1:1") || !o.network.inMajority("a3:3", 3) {
+		t.Errorf("partition not healed")
+	}
+}
+
+func TestMinorityGroupIsNotInMajority(t *testing.T) {
+	o, _ := getTestOrchestrator()
+	o.configs = append(o.configs,
+		config.NodeHostConfig{RaftAddress: "a4:4"},
+		config.NodeHostConfig{RaftAddress: "a5:5"})
+	o.Partition([]int{0, 1}, []int{2, 3, 4})
+	for idx, nhc := range o.configs {
+		if v := o.network.inMajority(nhc.RaftAddress, 5); v != (idx >= 2) {
+			t.Errorf("%d, in majority %t", idx, v)
+		}
+	}
+	o.Partition([]int{0, 1}, []int{2, 3})
+	for idx, nhc := range o.configs {
+		if o.network.inMajority(nhc.RaftAddress, 5) {
+			t.Errorf("%d unexpectedly in majority", idx)
+		}
+	}
+}
+
+func TestDropDiskRemovesNodeHostData(t *testing.T) {
+	defer os.RemoveAll(testDir)
+	o, _ := getTestOrchestrator()
+	o.configs[1].NodeHostDir = filepath.Join(testDir, "nh")
+	o.configs[1].WALDir = filepath.Join(testDir, "wal")
+	for _, dir := range []string{o.configs[1].NodeHostDir, o.configs[1].WALDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create dir %v", err)
+		}
+	}
+	if err := o.DropDisk(1); err != nil {
+		t.Fatalf("drop disk failed %v", err)
+	}
+	for _, dir := range []string{o.configs[1].NodeHostDir, o.configs[1].WALDir} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s not removed", dir)
+		}
+	}
+	if err := o.Stop(1); err != ErrNodeHostStopped {
+		t.Errorf("unexpected error %v", err)
+	}
+}
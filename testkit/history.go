@@ -0,0 +1,248 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/internal/tests/lcm/porcupine"
+)
+
+// Operation is a completed client operation in a history.
+type Operation struct {
+	// Input is the input of the operation.
+	Input interface{}
+	// Output is the output of the operation. It is nil when the outcome of the
+	// operation is unknown.
+	Output interface{}
+	// Call is the logical time when the operation was invoked.
+	Call int64
+	// Return is the logical time when the operation returned. It is
+	// math.MaxInt64 when the outcome of the operation is unknown.
+	Return int64
+}
+
+// Model is the sequential specification of the service being checked.
+type Model struct {
+	// Partition optionally splits the history into independent partitions,
+	// e.g. one partition per key, each partition is checked separately.
+	Partition func(history []Operation) [][]Operation
+	// Init returns the initial state.
+	Init func() interface{}
+	// Step returns whether the state can be transitioned using the specified
+	// input and output, it also returns the new state. Step must not mutate
+	// the input state. Step must accept nil outputs which are used for
+	// operations with unknown outcome.
+	Step func(state interface{},
+		input interface{}, output interface{}) (bool, interface{})
+	// Equal optionally checks whether two states are equal. The == operator is
+	// used when it is not set.
+	Equal func(state1 interface{}, state2 interface{}) bool
+}
+
+// ProposeInput is the Input of operations recorded by the SyncPropose method
+// of History.
+type ProposeInput struct {
+	ClusterID uint64
+	Cmd       []byte
+}
+
+// ProposeOutput is the Output of operations recorded by the SyncPropose
+// method of History.
+type ProposeOutput struct {
+	Result uint64
+}
+
+// ReadInput is the Input of operations recorded by the SyncRead method of
+// History.
+type ReadInput struct {
+	ClusterID uint64
+	Query     []byte
+}
+
+// ReadOutput is the Output of operations recorded by the SyncRead method of
+// History.
+type ReadOutput struct {
+	Data []byte
+}
+
+// History records client operations for checking linearizability. It is
+// safe for concurrent use.
+type History struct {
+	mu    sync.Mutex
+	clock int64
+	ops   []Operation
+}
+
+// NewHistory returns a new History instance.
+func NewHistory() *History {
+	return &History{}
+}
+
+// Call is an operation invoked but not yet completed.
+type Call struct {
+	h     *History
+	input interface{}
+	call  int64
+}
+
+// Invoke records the invocation of an operation with the specified input.
+// One of the Return, Unknown and Fail methods of the returned Call must be
+// invoked once the outcome of the operation is known.
+func (h *History) Invoke(input interface{}) *Call {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clock++
+	return &Call{h: h, input: input, call: h.clock}
+}
+
+// Return records the completion of the operation with the specified output.
+func (c *Call) Return(output interface{}) {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+	c.h.clock++
+	c.h.ops = append(c.h.ops, Operation{
+		Input:  c.input,
+		Output: output,
+		Call:   c.call,
+		Return: c.h.clock,
+	})
+}
+
+// Unknown records that the outcome of the operation is unknown, e.g. it timed
+// out. Such operation might take effect at any time after its invocation.
+func (c *Call) Unknown() {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+	c.h.ops = append(c.h.ops, Operation{
+		Input:  c.input,
+		Call:   c.call,
+		Return: math.MaxInt64,
+	})
+}
+
+// Fail records that the operation failed without taking effect, it is not
+// included in the history.
+func (c *Call) Fail() {}
+
+// Operations returns the recorded operations.
+func (h *History) Operations() []Operation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Operation(nil), h.ops...)
+}
+
+// SyncPropose makes a proposal using the SyncPropose method of the specified
+// NodeHost and records it in the history.
+func (h *History) SyncPropose(ctx context.Context, nh dragonboat.ISyncProposer,
+	session *client.Session, cmd []byte) (uint64, error) {
+	c := h.Invoke(ProposeInput{ClusterID: session.ClusterID, Cmd: cmd})
+	result, err := nh.SyncPropose(ctx, session, cmd)
+	if err == nil {
+		c.Return(ProposeOutput{Result: result})
+	} else if proposalOutcomeUnknown(err) {
+		c.Unknown()
+	} else {
+		c.Fail()
+	}
+	return result, err
+}
+
+// SyncRead makes a linearizable read using the SyncRead method of the
+// specified NodeHost and records it in the history. Failed reads are not
+// recorded.
+func (h *History) SyncRead(ctx context.Context, nh dragonboat.ISyncRequester,
+	clusterID uint64, query []byte) ([]byte, error) {
+	c := h.Invoke(ReadInput{ClusterID: clusterID, Query: query})
+	data, err := nh.SyncRead(ctx, clusterID, query)
+	if err == nil {
+		c.Return(ReadOutput{Data: data})
+	} else {
+		c.Fail()
+	}
+	return data, err
+}
+
+// Check checks whether the recorded history is linearizable with respect to
+// the specified model. Check gives up when the timeout is reached and reports
+// the history as linearizable in such case. A zero timeout value means no
+// timeout.
+func (h *History) Check(model Model, timeout time.Duration) bool {
+	return CheckOperations(model, h.Operations(), timeout)
+}
+
+// CheckOperations checks whether the specified history is linearizable with
+// respect to the specified model. See the Check method of History for details
+// on the timeout parameter.
+func CheckOperations(model Model,
+	history []Operation, timeout time.Duration) bool {
+	return porcupine.CheckOperationsTimeout(toPorcupineModel(model),
+		toPorcupineOperations(history), timeout)
+}
+
+func proposalOutcomeUnknown(err error) bool {
+	return err == dragonboat.ErrTimeout ||
+		err == dragonboat.ErrCanceled ||
+		err == dragonboat.ErrClusterClosed
+}
+
+func toPorcupineModel(model Model) porcupine.Model {
+	m := porcupine.Model{
+		Init:  model.Init,
+		Step:  model.Step,
+		Equal: model.Equal,
+	}
+	if model.Partition != nil {
+		m.Partition = func(history []porcupine.Operation) [][]porcupine.Operation {
+			var result [][]porcupine.Operation
+			for _, p := range model.Partition(fromPorcupineOperations(history)) {
+				result = append(result, toPorcupineOperations(p))
+			}
+			return result
+		}
+	}
+	return m
+}
+
+func toPorcupineOperations(history []Operation) []porcupine.Operation {
+	result := make([]porcupine.Operation, 0, len(history))
+	for _, op := range history {
+		result = append(result, porcupine.Operation{
+			Input:  op.Input,
+			Output: op.Output,
+			Call:   op.Call,
+			Return: op.Return,
+		})
+	}
+	return result
+}
+
+func fromPorcupineOperations(history []porcupine.Operation) []Operation {
+	result := make([]Operation, 0, len(history))
+	for _, op := range history {
+		result = append(result, Operation{
+			Input:  op.Input,
+			Output: op.Output,
+			Call:   op.Call,
+			Return: op.Return,
+		})
+	}
+	return result
+}
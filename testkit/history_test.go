@@ -0,0 +1,139 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/internal/utils/random"
+)
+
+type registerNodeHost struct {
+	mu       sync.Mutex
+	value    uint64
+	timeouts int
+}
+
+func (nh *registerNodeHost) SyncPropose(ctx context.Context,
+	session *client.Session, cmd []byte) (uint64, error) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	nh.value = binary.LittleEndian.Uint64(cmd)
+	if nh.timeouts > 0 {
+		nh.timeouts--
+		return 0, dragonboat.ErrTimeout
+	}
+	return nh.value, nil
+}
+
+func (nh *registerNodeHost) SyncRead(ctx context.Context,
+	clusterID uint64, query []byte) ([]byte, error) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, nh.value)
+	return data, nil
+}
+
+func (nh *registerNodeHost) GetNoOPSession(clusterID uint64) *client.Session {
+	return client.NewNoOPSession(clusterID, random.LockGuardedRand)
+}
+
+func getRegisterModel() Model {
+	return Model{
+		Init: func() interface{} { return uint64(0) },
+		Step: func(state interface{},
+			input interface{}, output interface{}) (bool, interface{}) {
+			switch in := input.(type) {
+			case ProposeInput:
+				return true, binary.LittleEndian.Uint64(in.Cmd)
+			case ReadInput:
+				out := output.(ReadOutput)
+				return binary.LittleEndian.Uint64(out.Data) == state.(uint64), state
+			}
+			panic("unknown input")
+		},
+	}
+}
+
+func getValue(v uint64) []byte {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, v)
+	return data
+}
+
+func TestRecordedHistoryIsLinearizable(t *testing.T) {
+	nh := &registerNodeHost{timeouts: 2}
+	h := NewHistory()
+	var wg sync.WaitGroup
+	for i := uint64(0); i < 4; i++ {
+		wg.Add(1)
+		go func(clientID uint64) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			session := client.NewNoOPSession(1, random.LockGuardedRand)
+			for j := uint64(0); j < 20; j++ {
+				h.SyncPropose(ctx, nh, session, getValue(clientID*100+j))
+				if _, err := h.SyncRead(ctx, nh, 1, nil); err != nil {
+					t.Errorf("read failed %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if len(h.Operations()) != 160 {
+		t.Errorf("unexpected operation count %d", len(h.Operations()))
+	}
+	if !h.Check(getRegisterModel(), 0) {
+		t.Errorf("history not linearizable")
+	}
+}
+
+func TestNonLinearizableHistoryIsReported(t *testing.T) {
+	h := NewHistory()
+	w := h.Invoke(ProposeInput{ClusterID: 1, Cmd: getValue(1)})
+	w.Return(ProposeOutput{Result: 1})
+	// a stale read started after the completed write
+	r := h.Invoke(ReadInput{ClusterID: 1})
+	r.Return(ReadOutput{Data: getValue(0)})
+	if h.Check(getRegisterModel(), 0) {
+		t.Errorf("stale read not reported")
+	}
+}
+
+func TestUnknownOperationCanTakeEffectLater(t *testing.T) {
+	h := NewHistory()
+	w := h.Invoke(ProposeInput{ClusterID: 1, Cmd: getValue(1)})
+	w.Unknown()
+	r1 := h.Invoke(ReadInput{ClusterID: 1})
+	r1.Return(ReadOutput{Data: getValue(0)})
+	r2 := h.Invoke(ReadInput{ClusterID: 1})
+	r2.Return(ReadOutput{Data: getValue(1)})
+	if !h.Check(getRegisterModel(), 0) {
+		t.Errorf("history not linearizable")
+	}
+	f := h.Invoke(ProposeInput{ClusterID: 1, Cmd: getValue(2)})
+	f.Fail()
+	if len(h.Operations()) != 3 {
+		t.Errorf("failed operation recorded")
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package testkit provides tools for testing applications built on top of
+Dragonboat.
+
+The deterministic replay harness validates user state machines without
+running Raft clusters. A Recording is a sequence of updates, snapshots and
+restarts. A Replayer feeds the recording into new state machine instances
+using the same adapters used by NodeHost, it checks that each snapshot can be
+recovered into a state machine with the same hash, that each restart, which
+recovers the state machine from the latest snapshot and replays updates
+applied after it, produces the same hash and update results, and that
+replaying the same recording multiple times always produces the same hashes
+and update results.
+
+	rec := &testkit.Recording{}
+	rec.Update([]byte("cmd-1"))
+	rec.Snapshot()
+	rec.Update([]byte("cmd-2"))
+	rec.Restart()
+	r := testkit.NewReplayer(dir, createStateMachine)
+	if err := r.Verify(rec, 3); err != nil {
+		t.Fatalf("verification failed, %v", err)
+	}
+
+The package also provides the History type for recording client operations
+made against NodeHost, recorded histories can be checked for linearizability
+against a user provided sequential Model. The Orchestrator type manages a
+group of NodeHost instances in the current process, it stops and restarts
+NodeHosts, drops their data, partitions the network between them and waits
+for Raft clusters to converge, allowing applications to be chaos tested.
+*/
+package testkit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lni/dragonboat/internal/rsm"
+	"github.com/lni/dragonboat/logger"
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+var (
+	plog = logger.GetLogger("testkit")
+)
+
+const (
+	testClusterID uint64 = 1
+	testNodeID    uint64 = 1
+)
+
+var (
+	// ErrSnapshotMismatch indicates that the state machine recovered from a
+	// snapshot has a different hash value.
+	ErrSnapshotMismatch = errors.New("recovered state machine hash mismatch")
+	// ErrRestartMismatch indicates that the restarted state machine produced
+	// different update results or hash value.
+	ErrRestartMismatch = errors.New("restarted state machine mismatch")
+	// ErrNotDeterministic indicates that replays of the same recording produced
+	// different update results or hash values.
+	ErrNotDeterministic = errors.New("state machine is not deterministic")
+)
+
+// OpType is the type of operations in a recording.
+type OpType uint8
+
+const (
+	// Update applies the Cmd of the Op as a committed proposal.
+	Update OpType = iota
+	// Snapshot saves a snapshot of the state machine and checks that it can be
+	// recovered into a new state machine instance with the same hash.
+	Snapshot
+	// Restart closes the state machine and creates a new instance using the
+	// latest snapshot and updates applied after it.
+	Restart
+)
+
+// Op is an operation in a recording.
+type Op struct {
+	Type OpType
+	Cmd  []byte
+}
+
+// Recording is a sequence of operations to be replayed.
+type Recording struct {
+	Ops []Op
+}
+
+// Update appends an update operation with the specified cmd.
+func (r *Recording) Update(cmd []byte) {
+	r.Ops = append(r.Ops, Op{Type: Update, Cmd: cmd})
+}
+
+// Snapshot appends a snapshot operation.
+func (r *Recording) Snapshot() {
+	r.Ops = append(r.Ops, Op{Type: Snapshot})
+}
+
+// Restart appends a restart operation.
+func (r *Recording) Restart() {
+	r.Ops = append(r.Ops, Op{Type: Restart})
+}
+
+// Result is the outcome of a replay.
+type Result struct {
+	// Results are the update results returned by the state machine in the
+	// order of update operations.
+	Results []uint64
+	// Hashes are the hash values of the state machine after each operation.
+	Hashes []uint64
+}
+
+func (r *Result) equal(o *Result) bool {
+	if len(r.Results) != len(o.Results) || len(r.Hashes) != len(o.Hashes) {
+		return false
+	}
+	for idx := range r.Results {
+		if r.Results[idx] != o.Results[idx] {
+			return false
+		}
+	}
+	for idx := range r.Hashes {
+		if r.Hashes[idx] != o.Hashes[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// Replayer replays recordings into state machines.
+type Replayer struct {
+	dir    string
+	create func() rsm.IManagedStateMachine
+}
+
+// NewReplayer returns a Replayer for the IStateMachine type created by the
+// specified factory function. Snapshot files are saved into the specified
+// directory.
+func NewReplayer(dir string,
+	create func(clusterID uint64, nodeID uint64) sm.IStateMachine) *Replayer {
+	return &Replayer{
+		dir: dir,
+		create: func() rsm.IManagedStateMachine {
+			s := create(testClusterID, testNodeID)
+			return rsm.NewNativeStateMachine(rsm.NewStateMachineAdapter(s), nil)
+		},
+	}
+}
+
+// NewConcurrentReplayer returns a Replayer for the IConcurrentStateMachine
+// type created by the specified factory function. Snapshot files are saved
+// into the specified directory.
+func NewConcurrentReplayer(dir string,
+	create func(uint64, uint64) sm.IConcurrentStateMachine) *Replayer {
+	return &Replayer{
+		dir: dir,
+		create: func() rsm.IManagedStateMachine {
+			s := create(testClusterID, testNodeID)
+			return rsm.NewNativeStateMachine(rsm.NewConcurrentStateMachine(s), nil)
+		},
+	}
+}
+
+// Verify replays the recording the specified number of times and checks that
+// all replays produced the same update results and hash values.
+func (r *Replayer) Verify(rec *Recording, replays int) error {
+	var first *Result
+	for i := 0; i < replays; i++ {
+		result, err := r.Replay(rec)
+		if err != nil {
+			return err
+		}
+		if first == nil {
+			first = result
+		} else if !first.equal(result) {
+			plog.Errorf("replay %d produced different results", i)
+			return ErrNotDeterministic
+		}
+	}
+	return nil
+}
+
+// Replay replays the recording into new state machine instances and returns
+// the update results and hash values.
+func (r *Replayer) Replay(rec *Recording) (*Result, error) {
+	p := &replay{r: r, s: r.create()}
+	defer func() {
+		p.s.Offloaded(rsm.FromNodeHost)
+	}()
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return nil, err
+	}
+	defer p.removeSnapshot()
+	result := &Result{}
+	for _, op := range rec.Ops {
+		switch op.Type {
+		case Update:
+			result.Results = append(result.Results, p.update(op.Cmd))
+		case Snapshot:
+			if err := p.snapshot(); err != nil {
+				return nil, err
+			}
+		case Restart:
+			if err := p.restart(); err != nil {
+				return nil, err
+			}
+		default:
+			panic(fmt.Sprintf("unknown op type %d", op.Type))
+		}
+		result.Hashes = append(result.Hashes, p.s.GetHash())
+	}
+	return result, nil
+}
+
+type replay struct {
+	r        *Replayer
+	s        rsm.IManagedStateMachine
+	index    uint64
+	fp       string
+	files    []sm.SnapshotFile
+	pending  []sm.Entry
+	versions uint64
+}
+
+func (p *replay) update(cmd []byte) uint64 {
+	p.index++
+	e := sm.Entry{Index: p.index, Cmd: cmd}
+	results := p.s.BatchedUpdate([]sm.Entry{e})
+	e.Result = results[0].Result
+	p.pending = append(p.pending, e)
+	return e.Result
+}
+
+func (p *replay) snapshot() error {
+	p.removeSnapshot()
+	p.versions++
+	fp := filepath.Join(p.r.dir,
+		fmt.Sprintf("testkit-snapshot-%d-%d.gbsnap", p.index, p.versions))
+	var ctx interface{}
+	if p.s.ConcurrentSnapshot() {
+		var err error
+		if ctx, err = p.s.PrepareSnapshot(); err != nil {
+			return err
+		}
+	}
+	w, err := rsm.NewSnapshotWriter(fp)
+	if err != nil {
+		return err
+	}
+	sessions := bytes.NewBuffer(nil)
+	if _, err := p.s.SaveSessions(sessions); err != nil {
+		w.Close()
+		return err
+	}
+	fc := &fileCollection{}
+	if _, err := p.s.SaveSnapshot(ctx, w, sessions.Bytes(), fc); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	p.fp = fp
+	p.files = fc.files
+	p.pending = nil
+	s := p.r.create()
+	defer s.Offloaded(rsm.FromNodeHost)
+	if err := s.RecoverFromSnapshot(p.fp, p.files); err != nil {
+		return err
+	}
+	if s.GetHash() != p.s.GetHash() {
+		plog.Errorf("snapshot %s, hash %d, want %d",
+			fp, s.GetHash(), p.s.GetHash())
+		return ErrSnapshotMismatch
+	}
+	return nil
+}
+
+func (p *replay) restart() error {
+	hash := p.s.GetHash()
+	p.s.Offloaded(rsm.FromNodeHost)
+	p.s = p.r.create()
+	if len(p.fp) > 0 {
+		if err := p.s.RecoverFromSnapshot(p.fp, p.files); err != nil {
+			return err
+		}
+	}
+	for _, e := range p.pending {
+		results := p.s.BatchedUpdate([]sm.Entry{{Index: e.Index, Cmd: e.Cmd}})
+		if results[0].Result != e.Result {
+			plog.Errorf("entry %d, result %d, want %d",
+				e.Index, results[0].Result, e.Result)
+			return ErrRestartMismatch
+		}
+	}
+	if p.s.GetHash() != hash {
+		plog.Errorf("restarted hash %d, want %d", p.s.GetHash(), hash)
+		return ErrRestartMismatch
+	}
+	return nil
+}
+
+func (p *replay) removeSnapshot() {
+	if len(p.fp) > 0 {
+		if err := os.Remove(p.fp); err != nil {
+			plog.Warningf("failed to remove %s, %v", p.fp, err)
+		}
+	}
+}
+
+type fileCollection struct {
+	files []sm.SnapshotFile
+}
+
+func (fc *fileCollection) AddFile(fileID uint64,
+	path string, metadata []byte) {
+	fc.files = append(fc.files, sm.SnapshotFile{
+		FileID:   fileID,
+		Filepath: path,
+		Metadata: metadata,
+	})
+}
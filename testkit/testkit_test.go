@@ -0,0 +1,142 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"testing"
+
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+const (
+	testDir = "testkit_safe_to_delete"
+)
+
+var (
+	// used by the non-deterministic state machine
+	globalCounter uint64
+)
+
+type counterStateMachine struct {
+	count          uint64
+	skipSnapshot   bool
+	nonDeterminism bool
+}
+
+func (s *counterStateMachine) Update(data []byte) uint64 {
+	s.count += uint64(len(data))
+	if s.nonDeterminism {
+		globalCounter++
+		s.count += globalCounter
+	}
+	return s.count
+}
+
+func (s *counterStateMachine) Lookup(query []byte) []byte {
+	return nil
+}
+
+func (s *counterStateMachine) SaveSnapshot(w io.Writer,
+	fc sm.ISnapshotFileCollection, done <-chan struct{}) (uint64, error) {
+	v := s.count
+	if s.skipSnapshot {
+		v = 0
+	}
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, v)
+	if _, err := w.Write(data); err != nil {
+		return 0, err
+	}
+	return 8, nil
+}
+
+func (s *counterStateMachine) RecoverFromSnapshot(r io.Reader,
+	files []sm.SnapshotFile, done <-chan struct{}) error {
+	data := make([]byte, 8)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	s.count = binary.LittleEndian.Uint64(data)
+	return nil
+}
+
+func (s *counterStateMachine) Close() {}
+
+func (s *counterStateMachine) GetHash() uint64 {
+	return s.count
+}
+
+func getTestRecording() *Recording {
+	rec := &Recording{}
+	rec.Update([]byte("test-data-1"))
+	rec.Update([]byte("test-data-2"))
+	rec.Snapshot()
+	rec.Update([]byte("test-data-3"))
+	rec.Restart()
+	rec.Update([]byte("test-data-4"))
+	rec.Restart()
+	return rec
+}
+
+func TestDeterministicStateMachineCanBeVerified(t *testing.T) {
+	defer os.RemoveAll(testDir)
+	create := func(clusterID uint64, nodeID uint64) sm.IStateMachine {
+		return &counterStateMachine{}
+	}
+	r := NewReplayer(testDir, create)
+	result, err := r.Replay(getTestRecording())
+	if err != nil {
+		t.Fatalf("replay failed %v", err)
+	}
+	if len(result.Results) != 4 || result.Results[3] != 44 {
+		t.Errorf("unexpected results %v", result.Results)
+	}
+	if len(result.Hashes) != 7 || result.Hashes[6] != 44 {
+		t.Errorf("unexpected hashes %v", result.Hashes)
+	}
+	if err := r.Verify(getTestRecording(), 3); err != nil {
+		t.Errorf("verify failed %v", err)
+	}
+}
+
+func TestBrokenSnapshotIsReported(t *testing.T) {
+	defer os.RemoveAll(testDir)
+	create := func(clusterID uint64, nodeID uint64) sm.IStateMachine {
+		return &counterStateMachine{skipSnapshot: true}
+	}
+	r := NewReplayer(testDir, create)
+	if err := r.Verify(getTestRecording(), 1); err != ErrSnapshotMismatch {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestNonDeterministicStateMachineIsReported(t *testing.T) {
+	defer os.RemoveAll(testDir)
+	create := func(clusterID uint64, nodeID uint64) sm.IStateMachine {
+		return &counterStateMachine{nonDeterminism: true}
+	}
+	r := NewReplayer(testDir, create)
+	rec := &Recording{}
+	rec.Update([]byte("test-data-1"))
+	if err := r.Verify(rec, 2); err != ErrNotDeterministic {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := r.Verify(getTestRecording(), 1); err != ErrRestartMismatch {
+		t.Errorf("unexpected error %v", err)
+	}
+}
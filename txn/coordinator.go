@@ -0,0 +1,160 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lni/dragonboat"
+)
+
+const (
+	retryInterval = 50 * time.Millisecond
+	abortTimeout  = 5 * time.Second
+)
+
+// Coordinator coordinates transactions across multiple Raft clusters using
+// the two-phase commit protocol.
+type Coordinator struct {
+	nh dragonboat.ISyncProposer
+}
+
+// NewCoordinator returns a new Coordinator instance which makes proposals
+// using the specified NodeHost instance.
+func NewCoordinator(nh dragonboat.ISyncProposer) *Coordinator {
+	return &Coordinator{nh: nh}
+}
+
+// Execute atomically applies the specified writes, a map of ClusterID values
+// to payloads, as a transaction identified by txnID. The transaction ID must
+// be unique. Payloads are passed to the Prepare method of the participant
+// state machines.
+//
+// ErrAborted is returned when any participant voted to abort, all
+// participants are asked to abort the transaction in such case. When other
+// errors are returned during the prepare phase, the transaction is aborted in
+// a best effort manner. Aborts are made using their own timeout so they are
+// still attempted when the specified context is done. When an error is
+// returned from the commit phase, all participants have voted to commit, the
+// caller should call Commit again to complete the transaction.
+func (c *Coordinator) Execute(ctx context.Context,
+	txnID uint64, writes map[uint64][]byte) error {
+	clusterIDs := make([]uint64, 0, len(writes))
+	for clusterID := range writes {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	sort.Slice(clusterIDs, func(i, j int) bool {
+		return clusterIDs[i] < clusterIDs[j]
+	})
+	if err := c.prepare(ctx, txnID, clusterIDs, writes); err != nil {
+		plog.Warningf("txn %d failed to prepare, %v", txnID, err)
+		actx, cancel := context.WithTimeout(context.Background(), abortTimeout)
+		defer cancel()
+		if aerr := c.Abort(actx, txnID, clusterIDs); aerr != nil {
+			plog.Warningf("txn %d failed to abort, %v", txnID, aerr)
+		}
+		return err
+	}
+	return c.Commit(ctx, txnID, clusterIDs)
+}
+
+// Commit asks all specified Raft clusters to commit the transaction. It keeps
+// retrying until all Raft clusters applied the commit request or the context
+// is done. Commit should only be called when all participants have voted to
+// commit the transaction.
+func (c *Coordinator) Commit(ctx context.Context,
+	txnID uint64, clusterIDs []uint64) error {
+	r := Request{TxnID: txnID, Op: Commit}
+	return c.complete(ctx, clusterIDs, r.Encode())
+}
+
+// Abort asks all specified Raft clusters to abort the transaction. It keeps
+// retrying until all Raft clusters applied the abort request or the context
+// is done.
+func (c *Coordinator) Abort(ctx context.Context,
+	txnID uint64, clusterIDs []uint64) error {
+	r := Request{TxnID: txnID, Op: Abort}
+	return c.complete(ctx, clusterIDs, r.Encode())
+}
+
+func (c *Coordinator) prepare(ctx context.Context,
+	txnID uint64, clusterIDs []uint64, writes map[uint64][]byte) error {
+	return c.forEachCluster(clusterIDs, func(clusterID uint64) error {
+		r := Request{TxnID: txnID, Op: Prepare, Payload: writes[clusterID]}
+		result, err := c.propose(ctx, clusterID, r.Encode(), false)
+		if err != nil {
+			return err
+		}
+		if result != ResultPrepared {
+			return ErrAborted
+		}
+		return nil
+	})
+}
+
+func (c *Coordinator) complete(ctx context.Context,
+	clusterIDs []uint64, cmd []byte) error {
+	return c.forEachCluster(clusterIDs, func(clusterID uint64) error {
+		_, err := c.propose(ctx, clusterID, cmd, true)
+		return err
+	})
+}
+
+func (c *Coordinator) propose(ctx context.Context,
+	clusterID uint64, cmd []byte, retry bool) (uint64, error) {
+	session := c.nh.GetNoOPSession(clusterID)
+	for {
+		result, err := c.nh.SyncPropose(ctx, session, cmd)
+		if err == nil || !retry || !retryable(err) {
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			return 0, err
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func (c *Coordinator) forEachCluster(clusterIDs []uint64,
+	f func(clusterID uint64) error) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(clusterIDs))
+	for idx, clusterID := range clusterIDs {
+		wg.Add(1)
+		go func(idx int, clusterID uint64) {
+			defer wg.Done()
+			errs[idx] = f(clusterID)
+		}(idx, clusterID)
+	}
+	wg.Wait()
+	var result error
+	for _, err := range errs {
+		if err == ErrAborted {
+			return err
+		}
+		if err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func retryable(err error) bool {
+	return err == dragonboat.ErrTimeout || err == dragonboat.ErrSystemBusy
+}
@@ -0,0 +1,97 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"io"
+
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+// IStateMachine is the interface to be implemented by application state
+// machines taking part in cross cluster transactions. Staged payloads of
+// prepared transactions are a part of the state machine state, they must be
+// included in snapshots created by the SaveSnapshot method.
+type IStateMachine interface {
+	sm.IStateMachine
+	// Prepare validates and stages the payload of the specified transaction.
+	// It returns a boolean value indicating whether the participant votes to
+	// commit the transaction. A transaction once voted to commit must be able
+	// to be committed later.
+	Prepare(txnID uint64, payload []byte) bool
+	// Commit applies the staged payload of the specified transaction.
+	Commit(txnID uint64)
+	// Abort discards the staged payload of the specified transaction. Abort
+	// can be invoked for transactions that have never been prepared.
+	Abort(txnID uint64)
+}
+
+type stateMachine struct {
+	sm IStateMachine
+}
+
+// NewStateMachine returns a IStateMachine instance which dispatches
+// transaction requests to the specified user state machine. All regular
+// commands proposed to the returned state machine must be encoded by
+// EncodeCommand.
+func NewStateMachine(s IStateMachine) sm.IStateMachine {
+	return &stateMachine{sm: s}
+}
+
+func (s *stateMachine) Update(data []byte) uint64 {
+	if len(data) > 0 && data[0] == regularCommand {
+		return s.sm.Update(data[1:])
+	}
+	r, ok := DecodeRequest(data)
+	if !ok {
+		plog.Panicf("unknown command %v", data)
+	}
+	switch r.Op {
+	case Prepare:
+		if s.sm.Prepare(r.TxnID, r.Payload) {
+			return ResultPrepared
+		}
+		return ResultRejected
+	case Commit:
+		s.sm.Commit(r.TxnID)
+	case Abort:
+		s.sm.Abort(r.TxnID)
+	default:
+		plog.Panicf("unknown op %d", r.Op)
+	}
+	return 0
+}
+
+func (s *stateMachine) Lookup(query []byte) []byte {
+	return s.sm.Lookup(query)
+}
+
+func (s *stateMachine) SaveSnapshot(w io.Writer,
+	fc sm.ISnapshotFileCollection, done <-chan struct{}) (uint64, error) {
+	return s.sm.SaveSnapshot(w, fc, done)
+}
+
+func (s *stateMachine) RecoverFromSnapshot(r io.Reader,
+	files []sm.SnapshotFile, done <-chan struct{}) error {
+	return s.sm.RecoverFromSnapshot(r, files, done)
+}
+
+func (s *stateMachine) Close() {
+	s.sm.Close()
+}
+
+func (s *stateMachine) GetHash() uint64 {
+	return s.sm.GetHash()
+}
@@ -0,0 +1,131 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package txn implements a two-phase commit helper for applications that shard
+their data across multiple Raft clusters managed by dragonboat.
+
+Each Raft cluster taking part in a transaction runs an IStateMachine wrapped by
+NewStateMachine. The Coordinator proposes prepare, commit and abort requests
+to those Raft clusters using the regular proposal path, the wrapped state
+machine dispatches such requests to the Prepare, Commit and Abort methods of
+the user state machine. Every other proposal made to such Raft clusters must
+be encoded by EncodeCommand, the wrapped state machine passes the original
+command to the Update method of the user state machine.
+
+The Coordinator itself is stateless, the transaction outcome is only known to
+the caller of Coordinator.Execute. When Execute fails after all participants
+have voted to commit, e.g. due to a timeout, the caller is expected to finish
+the transaction by calling Coordinator.Commit again with the same transaction
+ID. Prepare, Commit and Abort must thus be idempotent for any given
+transaction ID.
+*/
+package txn
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/lni/dragonboat/logger"
+)
+
+var (
+	plog = logger.GetLogger("txn")
+)
+
+var (
+	// ErrAborted indicates that the transaction has been aborted as at least
+	// one participant voted to abort.
+	ErrAborted = errors.New("transaction aborted")
+)
+
+const (
+	// ResultPrepared is the Update result of a prepare request when the
+	// participant voted to commit the transaction.
+	ResultPrepared uint64 = 1
+	// ResultRejected is the Update result of a prepare request when the
+	// participant voted to abort the transaction.
+	ResultRejected uint64 = 2
+)
+
+// Op is the type of a transaction request.
+type Op uint8
+
+const (
+	// Prepare asks the participant to stage the payload and vote.
+	Prepare Op = iota + 1
+	// Commit asks the participant to apply the staged payload.
+	Commit
+	// Abort asks the participant to discard the staged payload.
+	Abort
+)
+
+const (
+	regularCommand    byte = 0
+	requestCommand    byte = 1
+	requestHeaderSize      = 10
+)
+
+// EncodeCommand returns the byte slice representation of a regular command
+// ready to be proposed to a Raft cluster running a state machine returned by
+// NewStateMachine.
+func EncodeCommand(cmd []byte) []byte {
+	data := make([]byte, 1+len(cmd))
+	data[0] = regularCommand
+	copy(data[1:], cmd)
+	return data
+}
+
+// Request is a transaction request proposed to a participant Raft cluster.
+type Request struct {
+	TxnID   uint64
+	Op      Op
+	Payload []byte
+}
+
+// Encode returns the byte slice representation of the request ready to be
+// proposed to a Raft cluster.
+func (r *Request) Encode() []byte {
+	data := make([]byte, requestHeaderSize+len(r.Payload))
+	data[0] = requestCommand
+	data[1] = byte(r.Op)
+	binary.BigEndian.PutUint64(data[2:], r.TxnID)
+	copy(data[requestHeaderSize:], r.Payload)
+	return data
+}
+
+// IsRequest returns a boolean value indicating whether the proposed command is
+// a transaction request.
+func IsRequest(cmd []byte) bool {
+	if len(cmd) < requestHeaderSize || cmd[0] != requestCommand {
+		return false
+	}
+	op := Op(cmd[1])
+	return op == Prepare || op == Commit || op == Abort
+}
+
+// DecodeRequest decodes the proposed command as a transaction request. The
+// returned boolean value indicates whether the command is a transaction
+// request.
+func DecodeRequest(cmd []byte) (Request, bool) {
+	if !IsRequest(cmd) {
+		return Request{}, false
+	}
+	r := Request{
+		Op:      Op(cmd[1]),
+		TxnID:   binary.BigEndian.Uint64(cmd[2:]),
+		Payload: cmd[requestHeaderSize:],
+	}
+	return r, true
+}
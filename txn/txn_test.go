@@ -0,0 +1,260 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lni/dragonboat"
+	"github.com/lni/dragonboat/client"
+	"github.com/lni/dragonboat/internal/utils/random"
+	sm "github.com/lni/dragonboat/statemachine"
+)
+
+type testParticipant struct {
+	reject    bool
+	updates   int
+	staged    map[uint64][]byte
+	committed [][]byte
+	aborted   []uint64
+}
+
+func newTestParticipant(reject bool) *testParticipant {
+	return &testParticipant{
+		reject: reject,
+		staged: make(map[uint64][]byte),
+	}
+}
+
+func (p *testParticipant) Update(data []byte) uint64 {
+	p.updates++
+	return uint64(len(data))
+}
+
+func (p *testParticipant) Lookup(query []byte) []byte { return query }
+
+func (p *testParticipant) SaveSnapshot(w io.Writer,
+	fc sm.ISnapshotFileCollection, done <-chan struct{}) (uint64, error) {
+	return 0, nil
+}
+
+func (p *testParticipant) RecoverFromSnapshot(r io.Reader,
+	files []sm.SnapshotFile, done <-chan struct{}) error {
+	return nil
+}
+
+func (p *testParticipant) Close() {}
+
+func (p *testParticipant) GetHash() uint64 { return 0 }
+
+func (p *testParticipant) Prepare(txnID uint64, payload []byte) bool {
+	if p.reject {
+		return false
+	}
+	p.staged[txnID] = append([]byte(nil), payload...)
+	return true
+}
+
+func (p *testParticipant) Commit(txnID uint64) {
+	if v, ok := p.staged[txnID]; ok {
+		p.committed = append(p.committed, v)
+		delete(p.staged, txnID)
+	}
+}
+
+func (p *testParticipant) Abort(txnID uint64) {
+	delete(p.staged, txnID)
+	p.aborted = append(p.aborted, txnID)
+}
+
+type testNodeHost struct {
+	mu        sync.Mutex
+	failures  int
+	onPropose func()
+	sms       map[uint64]sm.IStateMachine
+}
+
+func newTestNodeHost(participants map[uint64]*testParticipant) *testNodeHost {
+	nh := &testNodeHost{sms: make(map[uint64]sm.IStateMachine)}
+	for clusterID, p := range participants {
+		nh.sms[clusterID] = NewStateMachine(p)
+	}
+	return nh
+}
+
+func (nh *testNodeHost) SyncPropose(ctx context.Context,
+	session *client.Session, cmd []byte) (uint64, error) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return 0, dragonboat.ErrCanceled
+	}
+	if nh.onPropose != nil {
+		nh.onPropose()
+	}
+	if nh.failures > 0 {
+		nh.failures--
+		return 0, dragonboat.ErrTimeout
+	}
+	return nh.sms[session.ClusterID].Update(cmd), nil
+}
+
+func (nh *testNodeHost) GetNoOPSession(clusterID uint64) *client.Session {
+	return client.NewNoOPSession(clusterID, random.LockGuardedRand)
+}
+
+func TestRequestCanBeEncodedAndDecoded(t *testing.T) {
+	r := Request{TxnID: 12345, Op: Commit, Payload: []byte("test-data")}
+	data := r.Encode()
+	if !IsRequest(data) {
+		t.Fatalf("not considered as a request")
+	}
+	dr, ok := DecodeRequest(data)
+	if !ok {
+		t.Fatalf("failed to decode")
+	}
+	if dr.TxnID != r.TxnID || dr.Op != r.Op ||
+		!bytes.Equal(dr.Payload, r.Payload) {
+		t.Errorf("unexpected request %v, want %v", dr, r)
+	}
+	cmds := [][]byte{nil, EncodeCommand(data), []byte("test-data"), data[:5]}
+	for _, cmd := range cmds {
+		if IsRequest(cmd) {
+			t.Errorf("%v considered as a request", cmd)
+		}
+	}
+}
+
+func TestRegularProposalIsPassedToUpdate(t *testing.T) {
+	p := newTestParticipant(false)
+	s := NewStateMachine(p)
+	r := Request{TxnID: 100, Op: Prepare, Payload: []byte("test-data")}
+	for _, cmd := range [][]byte{[]byte("test-data"), r.Encode(), nil} {
+		if v := s.Update(EncodeCommand(cmd)); v != uint64(len(cmd)) {
+			t.Errorf("unexpected result %d", v)
+		}
+	}
+	if p.updates != 3 {
+		t.Errorf("Update not called")
+	}
+	if len(p.staged) != 0 {
+		t.Errorf("regular command considered as a request")
+	}
+}
+
+func TestUnknownCommandCausesPanic(t *testing.T) {
+	s := NewStateMachine(newTestParticipant(false))
+	for _, cmd := range [][]byte{nil, []byte("test-data")} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("panic not triggered for %v", cmd)
+				}
+			}()
+			s.Update(cmd)
+		}()
+	}
+}
+
+func TestTransactionCanBeCommitted(t *testing.T) {
+	participants := map[uint64]*testParticipant{
+		1: newTestParticipant(false),
+		2: newTestParticipant(false),
+	}
+	c := NewCoordinator(newTestNodeHost(participants))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	writes := map[uint64][]byte{1: []byte("v1"), 2: []byte("v2")}
+	if err := c.Execute(ctx, 100, writes); err != nil {
+		t.Fatalf("failed to execute txn %v", err)
+	}
+	for clusterID, p := range participants {
+		if len(p.committed) != 1 ||
+			!bytes.Equal(p.committed[0], writes[clusterID]) {
+			t.Errorf("cluster %d not committed", clusterID)
+		}
+		if len(p.staged) != 0 || len(p.aborted) != 0 {
+			t.Errorf("unexpected staged or aborted txn")
+		}
+	}
+}
+
+func TestTransactionIsAbortedWhenRejected(t *testing.T) {
+	participants := map[uint64]*testParticipant{
+		1: newTestParticipant(false),
+		2: newTestParticipant(true),
+	}
+	c := NewCoordinator(newTestNodeHost(participants))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	writes := map[uint64][]byte{1: []byte("v1"), 2: []byte("v2")}
+	if err := c.Execute(ctx, 100, writes); err != ErrAborted {
+		t.Fatalf("unexpected error %v, want ErrAborted", err)
+	}
+	for clusterID, p := range participants {
+		if len(p.committed) != 0 || len(p.staged) != 0 {
+			t.Errorf("cluster %d not aborted", clusterID)
+		}
+		if len(p.aborted) != 1 || p.aborted[0] != 100 {
+			t.Errorf("cluster %d abort not applied", clusterID)
+		}
+	}
+}
+
+func TestAbortIsMadeAfterContextIsDone(t *testing.T) {
+	participants := map[uint64]*testParticipant{
+		1: newTestParticipant(false),
+		2: newTestParticipant(true),
+	}
+	nh := newTestNodeHost(participants)
+	ctx, cancel := context.WithCancel(context.Background())
+	nh.onPropose = cancel
+	c := NewCoordinator(nh)
+	writes := map[uint64][]byte{1: []byte("v1"), 2: []byte("v2")}
+	if err := c.Execute(ctx, 100, writes); err == nil {
+		t.Fatalf("txn unexpectedly committed")
+	}
+	for clusterID, p := range participants {
+		if len(p.aborted) != 1 || p.aborted[0] != 100 {
+			t.Errorf("cluster %d abort not applied", clusterID)
+		}
+	}
+}
+
+func TestCommitIsRetried(t *testing.T) {
+	participants := map[uint64]*testParticipant{
+		1: newTestParticipant(false),
+	}
+	nh := newTestNodeHost(participants)
+	c := NewCoordinator(nh)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r := Request{TxnID: 100, Op: Prepare, Payload: []byte("v1")}
+	if v := nh.sms[1].Update(r.Encode()); v != ResultPrepared {
+		t.Fatalf("failed to prepare")
+	}
+	nh.failures = 2
+	if err := c.Commit(ctx, 100, []uint64{1}); err != nil {
+		t.Fatalf("failed to commit %v", err)
+	}
+	if len(participants[1].committed) != 1 {
+		t.Errorf("not committed")
+	}
+}
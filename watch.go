@@ -0,0 +1,240 @@
+// Copyright 2017-2019 Lei Ni (nilei81@gmail.com)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dragonboat
+
+import (
+	"sync"
+
+	"github.com/lni/dragonboat/internal/raft"
+	pb "github.com/lni/dragonboat/raftpb"
+)
+
+const (
+	watchBufferSize   = 4096
+	watchMaxBatchSize = 4 * 1024 * 1024
+)
+
+// WatchRecord is a committed proposal observed on the local node.
+type WatchRecord struct {
+	// Index is the Raft log index of the proposal.
+	Index uint64
+	// Cmd is the proposed command.
+	Cmd []byte
+	// Result is the value returned by the Update method of the state machine
+	// when applying the proposal. It is only valid when ResultAvailable is true.
+	Result uint64
+	// ResultAvailable indicates whether the Result field is available. Records
+	// of proposals applied before the Watch call are read from the Raft log,
+	// their results are not available. Results are also not available for
+	// proposals rejected for using unregistered client sessions.
+	ResultAvailable bool
+}
+
+// Watcher is the change feed returned by NodeHost's Watch method.
+type Watcher struct {
+	node         *node
+	from         uint64
+	history      uint64
+	liveC        chan WatchRecord
+	recordC      chan WatchRecord
+	stopc        chan struct{}
+	overflowc    chan struct{}
+	stopOnce     sync.Once
+	overflowOnce sync.Once
+	err          error
+}
+
+func newWatcher(n *node) *Watcher {
+	return &Watcher{
+		node:      n,
+		liveC:     make(chan WatchRecord, watchBufferSize),
+		recordC:   make(chan WatchRecord),
+		stopc:     make(chan struct{}),
+		overflowc: make(chan struct{}),
+	}
+}
+
+// RecordC returns the channel from which WatchRecord instances can be received
+// in Raft log index order. The channel is closed when the Watcher is closed or
+// the watch can not be continued, Err can be used to get the reason in the
+// latter case.
+func (w *Watcher) RecordC() <-chan WatchRecord {
+	return w.recordC
+}
+
+// Err returns the error that caused the channel returned by RecordC to be
+// closed. It returns nil when the Watcher has been closed by the application.
+// Err should only be called after the RecordC channel is closed.
+func (w *Watcher) Err() error {
+	return w.err
+}
+
+// overflow marks the Watcher as failed for not keeping up with the applied
+// proposals.
+func (w *Watcher) overflow() {
+	w.overflowOnce.Do(func() {
+		close(w.overflowc)
+	})
+}
+
+// Close stops the Watcher.
+func (w *Watcher) Close() {
+	w.stopOnce.Do(func() {
+		close(w.stopc)
+	})
+	w.node.watchers.remove(w)
+}
+
+func (w *Watcher) run() {
+	defer close(w.recordC)
+	// unblocks and unregisters the watcher on all exit paths
+	defer w.Close()
+	next := w.from
+	for next <= w.history {
+		ents, err := w.node.logreader.Entries(next,
+			w.history+1, watchMaxBatchSize)
+		if err != nil {
+			if err == raft.ErrCompacted {
+				err = ErrCompacted
+			}
+			w.err = err
+			return
+		}
+		if len(ents) == 0 {
+			w.err = ErrCompacted
+			return
+		}
+		for _, e := range ents {
+			if !watchable(e) {
+				continue
+			}
+			if !w.send(WatchRecord{Index: e.Index, Cmd: e.Cmd}) {
+				return
+			}
+		}
+		next = ents[len(ents)-1].Index + 1
+	}
+	for {
+		select {
+		case r := <-w.liveC:
+			if r.Index < next {
+				continue
+			}
+			if !w.send(r) {
+				return
+			}
+		case <-w.stopc:
+			return
+		case <-w.overflowc:
+			w.err = ErrWatcherOverflow
+			return
+		case <-w.node.stopc:
+			w.err = ErrClusterClosed
+			return
+		}
+	}
+}
+
+func (w *Watcher) send(r WatchRecord) bool {
+	select {
+	case w.recordC <- r:
+		return true
+	case <-w.stopc:
+		return false
+	case <-w.overflowc:
+		w.err = ErrWatcherOverflow
+		return false
+	case <-w.node.stopc:
+		w.err = ErrClusterClosed
+		return false
+	}
+}
+
+// watchable returns a boolean value indicating whether the entry should be
+// received by watchers, the same filter is used for both entries read from the
+// Raft log and those published when applied.
+func watchable(e pb.Entry) bool {
+	return e.IsUpdateEntry()
+}
+
+type watchers struct {
+	mu       sync.Mutex
+	watchers map[*Watcher]struct{}
+}
+
+func (ws *watchers) add(w *Watcher) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.watchers == nil {
+		ws.watchers = make(map[*Watcher]struct{})
+	}
+	ws.watchers[w] = struct{}{}
+}
+
+func (ws *watchers) remove(w *Watcher) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	delete(ws.watchers, w)
+}
+
+// publish is invoked with the state machine lock held, it never blocks.
+// Watchers that can not keep up are removed and fail with the
+// ErrWatcherOverflow error.
+func (ws *watchers) publish(entry pb.Entry, result uint64, rejected bool) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if len(ws.watchers) == 0 {
+		return
+	}
+	r := WatchRecord{
+		Index:           entry.Index,
+		Cmd:             append([]byte(nil), entry.Cmd...),
+		Result:          result,
+		ResultAvailable: !rejected,
+	}
+	for w := range ws.watchers {
+		select {
+		case w.liveC <- r:
+		default:
+			plog.Warningf("watcher removed for not keeping up at index %d",
+				entry.Index)
+			w.overflow()
+			delete(ws.watchers, w)
+		}
+	}
+}
+
+func (rc *node) watch(fromIndex uint64) (*Watcher, error) {
+	w := newWatcher(rc)
+	// the watcher is registered before the last applied index is read so any
+	// proposal applied after it is guaranteed to be published to the watcher,
+	// those already read from the Raft log are skipped by run. The watchers
+	// lock is never held when acquiring the state machine lock as publish is
+	// invoked with the state machine lock held.
+	rc.watchers.add(w)
+	w.history = rc.sm.GetLastApplied()
+	if fromIndex == 0 {
+		fromIndex = w.history + 1
+	}
+	w.from = fromIndex
+	if w.from <= w.history {
+		if first, _ := rc.logreader.GetRange(); w.from < first {
+			rc.watchers.remove(w)
+			return nil, ErrCompacted
+		}
+	}
+	go w.run()
+	return w, nil
+}